@@ -0,0 +1,215 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"time"
+
+	"github.com/OWASP/Amass/v3/config"
+	"github.com/OWASP/Amass/v3/datasrcs"
+	"github.com/caffix/netmap"
+	"github.com/caffix/stringset"
+	"github.com/fatih/color"
+)
+
+const (
+	cacheUsageMsg = "cache [options]"
+)
+
+type cacheArgs struct {
+	Sources *stringset.Set
+	Age     int
+	Pattern string
+	Options struct {
+		List    bool
+		Purge   bool
+		NoColor bool
+		Silent  bool
+	}
+	Filepaths struct {
+		ConfigFile string
+		Directory  string
+		Export     string
+		Import     string
+	}
+}
+
+func runCacheCommand(clArgs []string) {
+	var args cacheArgs
+	var help1, help2 bool
+	cacheCommand := flag.NewFlagSet("cache", flag.ContinueOnError)
+
+	cacheBuf := new(bytes.Buffer)
+	cacheCommand.SetOutput(cacheBuf)
+	args.Sources = stringset.New()
+	defer args.Sources.Close()
+
+	cacheCommand.BoolVar(&help1, "h", false, "Show the program usage message")
+	cacheCommand.BoolVar(&help2, "help", false, "Show the program usage message")
+	cacheCommand.Var(args.Sources, "src", "Data source names separated by commas (can be used multiple times)")
+	cacheCommand.IntVar(&args.Age, "age", 0, "Only select responses cached at least this many minutes ago")
+	cacheCommand.StringVar(&args.Pattern, "url", "", "Only select responses for URLs matching the regular expression")
+	cacheCommand.BoolVar(&args.Options.List, "list", false, "List the cached responses with their age and size")
+	cacheCommand.BoolVar(&args.Options.Purge, "purge", false, "Remove the selected responses from the cache")
+	cacheCommand.BoolVar(&args.Options.NoColor, "nocolor", false, "Disable colorized output")
+	cacheCommand.BoolVar(&args.Options.Silent, "silent", false, "Disable all output during execution")
+	cacheCommand.StringVar(&args.Filepaths.ConfigFile, "config", "", "Path to the INI, YAML or JSON configuration file. Additional details below")
+	cacheCommand.StringVar(&args.Filepaths.Directory, "dir", "", "Path to the directory containing the graph database")
+	cacheCommand.StringVar(&args.Filepaths.Export, "export", "", "Path to the file that the selected responses will be written to")
+	cacheCommand.StringVar(&args.Filepaths.Import, "import", "", "Path to a file of responses previously exported")
+
+	if len(clArgs) < 1 {
+		commandUsage(cacheUsageMsg, cacheCommand, cacheBuf)
+		return
+	}
+	if err := cacheCommand.Parse(clArgs); err != nil {
+		r.Fprintf(color.Error, "%v\n", err)
+		os.Exit(1)
+	}
+	if help1 || help2 {
+		commandUsage(cacheUsageMsg, cacheCommand, cacheBuf)
+		return
+	}
+	if args.Options.NoColor {
+		color.NoColor = true
+	}
+	if args.Options.Silent {
+		color.Output = io.Discard
+		color.Error = io.Discard
+	}
+	if !args.Options.List && !args.Options.Purge && args.Filepaths.Export == "" && args.Filepaths.Import == "" {
+		commandUsage(cacheUsageMsg, cacheCommand, cacheBuf)
+		return
+	}
+
+	filter := &datasrcs.CacheFilter{
+		Sources:   args.Sources.Slice(),
+		OlderThan: time.Duration(args.Age) * time.Minute,
+	}
+	if args.Pattern != "" {
+		re, err := regexp.Compile(args.Pattern)
+		if err != nil {
+			r.Fprintf(color.Error, "Failed to compile the URL regular expression: %v\n", err)
+			os.Exit(1)
+		}
+		filter.Pattern = re
+	}
+
+	cfg := config.NewConfig()
+	// Check if a configuration file was provided, and if so, load the settings
+	if err := config.AcquireConfig(args.Filepaths.Directory, args.Filepaths.ConfigFile, cfg); err == nil {
+		if args.Filepaths.Directory == "" {
+			args.Filepaths.Directory = cfg.Dir
+		}
+	} else if args.Filepaths.ConfigFile != "" {
+		r.Fprintf(color.Error, "Failed to load the configuration file: %v\n", err)
+		os.Exit(1)
+	}
+
+	db := openGraphDatabase(args.Filepaths.Directory, cfg)
+	if db == nil {
+		r.Fprintln(color.Error, "Failed to connect with the database")
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	if args.Filepaths.Import != "" {
+		f, err := os.Open(args.Filepaths.Import)
+		if err != nil {
+			r.Fprintf(color.Error, "Failed to open the import file: %v\n", err)
+			os.Exit(1)
+		}
+
+		num, err := datasrcs.ImportCache(ctx, db, filter, f)
+		f.Close()
+		if err != nil {
+			r.Fprintf(color.Error, "Failed to import the cached responses: %v\n", err)
+			os.Exit(1)
+		}
+		g.Fprintf(color.Error, "%d cached responses were imported\n", num)
+	}
+	if args.Options.List {
+		listCachedResponses(ctx, db, filter)
+	}
+	if args.Filepaths.Export != "" {
+		f, err := os.OpenFile(args.Filepaths.Export, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+		if err != nil {
+			r.Fprintf(color.Error, "Failed to open the export file: %v\n", err)
+			os.Exit(1)
+		}
+
+		num, err := datasrcs.ExportCache(ctx, db, filter, f)
+		_ = f.Sync()
+		f.Close()
+		if err != nil {
+			r.Fprintf(color.Error, "Failed to export the cached responses: %v\n", err)
+			os.Exit(1)
+		}
+		g.Fprintf(color.Error, "%d cached responses were exported\n", num)
+	}
+	if args.Options.Purge {
+		num, err := datasrcs.PurgeCache(ctx, db, filter)
+		if err != nil {
+			r.Fprintf(color.Error, "Failed to purge the cached responses: %v\n", err)
+			os.Exit(1)
+		}
+		g.Fprintf(color.Error, "%d cached responses were purged\n", num)
+	}
+}
+
+func listCachedResponses(ctx context.Context, db *netmap.Graph, filter *datasrcs.CacheFilter) {
+	entries, err := datasrcs.CacheEntries(ctx, db, filter)
+	if err != nil {
+		r.Fprintf(color.Error, "Failed to obtain the cached responses: %v\n", err)
+		os.Exit(1)
+	}
+	if len(entries) == 0 {
+		r.Fprintln(color.Error, "No cached responses were found")
+		return
+	}
+
+	var size int
+	var src string
+	for _, e := range entries {
+		if e.Source != src {
+			src = e.Source
+			fmt.Fprintf(color.Output, "\n%s\n", blue(src))
+		}
+
+		size += len(e.Response)
+		fmt.Fprintf(color.Output, "%-22s %-20s %s\n",
+			yellow(formatCacheAge(e.Age())), yellow(formatCacheSize(len(e.Response))), green(e.Query))
+	}
+	fmt.Fprintf(color.Output, "\n%s\n", blue(fmt.Sprintf("%d cached responses using %s",
+		len(entries), formatCacheSize(size))))
+}
+
+func formatCacheAge(age time.Duration) string {
+	switch {
+	case age < time.Hour:
+		return fmt.Sprintf("%dm", int(age.Minutes()))
+	case age < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(age.Hours()))
+	}
+	return fmt.Sprintf("%dd", int(age.Hours()/24))
+}
+
+func formatCacheSize(size int) string {
+	switch {
+	case size < 1024:
+		return fmt.Sprintf("%dB", size)
+	case size < 1024*1024:
+		return fmt.Sprintf("%.1fKB", float64(size)/1024)
+	}
+	return fmt.Sprintf("%.1fMB", float64(size)/(1024*1024))
+}
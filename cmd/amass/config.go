@@ -0,0 +1,196 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/OWASP/Amass/v3/config"
+	"github.com/fatih/color"
+)
+
+const (
+	configUsageMsg      = "config check|show|init [options]"
+	configCheckUsageMsg = "config check [options]"
+	configShowUsageMsg  = "config show [options]"
+	configInitUsageMsg  = "config init [options]"
+)
+
+func runConfigCommand(clArgs []string) {
+	var help1, help2 bool
+	configCommand := flag.NewFlagSet("config", flag.ContinueOnError)
+
+	configBuf := new(bytes.Buffer)
+	configCommand.SetOutput(configBuf)
+
+	configCommand.BoolVar(&help1, "h", false, "Show the program usage message")
+	configCommand.BoolVar(&help2, "help", false, "Show the program usage message")
+
+	if len(clArgs) < 1 {
+		commandUsage(configUsageMsg, configCommand, configBuf)
+		return
+	}
+
+	switch clArgs[0] {
+	case "check":
+		runConfigCheckCommand(clArgs[1:])
+	case "show":
+		runConfigShowCommand(clArgs[1:])
+	case "init":
+		runConfigInitCommand(clArgs[1:])
+	default:
+		if err := configCommand.Parse(clArgs); err != nil {
+			r.Fprintf(color.Error, "%v\n", err)
+			os.Exit(1)
+		}
+		commandUsage(configUsageMsg, configCommand, configBuf)
+		if !help1 && !help2 {
+			os.Exit(1)
+		}
+	}
+}
+
+func runConfigCheckCommand(clArgs []string) {
+	var help1, help2, noColor bool
+	var file, dir, profile string
+	checkCommand := flag.NewFlagSet("config check", flag.ContinueOnError)
+
+	checkBuf := new(bytes.Buffer)
+	checkCommand.SetOutput(checkBuf)
+
+	checkCommand.BoolVar(&help1, "h", false, "Show the program usage message")
+	checkCommand.BoolVar(&help2, "help", false, "Show the program usage message")
+	checkCommand.BoolVar(&noColor, "nocolor", false, "Disable colorized output")
+	checkCommand.StringVar(&file, "config", "", "Path to the INI, YAML or JSON configuration file to check")
+	checkCommand.StringVar(&dir, "dir", "", "Path to the directory containing the configuration file")
+	checkCommand.StringVar(&profile, "profile", "", "Name of the configuration file profile also checked")
+
+	if err := checkCommand.Parse(clArgs); err != nil {
+		r.Fprintf(color.Error, "%v\n", err)
+		os.Exit(1)
+	}
+	if help1 || help2 {
+		commandUsage(configCheckUsageMsg, checkCommand, checkBuf)
+		return
+	}
+	if noColor {
+		color.NoColor = true
+	}
+
+	path := config.LocateConfigFile(dir, file)
+	if path == "" {
+		r.Fprintln(color.Error, "No configuration file was found")
+		os.Exit(1)
+	}
+
+	if err := config.CheckFile(path, profile); err != nil {
+		var serr *config.SettingsError
+
+		if errors.As(err, &serr) {
+			r.Fprintf(color.Error, "The configuration file %s is invalid:\n", serr.Path)
+			for _, issue := range serr.Issues {
+				fmt.Fprintf(color.Error, "\t%s\n", issue)
+			}
+		} else {
+			r.Fprintf(color.Error, "The configuration file %s is invalid: %v\n", path, err)
+		}
+		os.Exit(1)
+	}
+	g.Fprintf(color.Output, "The configuration file %s is valid\n", path)
+}
+
+func runConfigShowCommand(clArgs []string) {
+	args := newEnumArgs()
+	var help1, help2 bool
+	showCommand := flag.NewFlagSet("config show", flag.ContinueOnError)
+
+	showBuf := new(bytes.Buffer)
+	showCommand.SetOutput(showBuf)
+
+	showCommand.BoolVar(&help1, "h", false, "Show the program usage message")
+	showCommand.BoolVar(&help2, "help", false, "Show the program usage message")
+	// The enum flags are accepted to show how they override the configuration file
+	defineEnumArgumentFlags(showCommand, &args)
+	defineEnumOptionFlags(showCommand, &args)
+	defineEnumFilepathFlags(showCommand, &args)
+
+	if err := showCommand.Parse(clArgs); err != nil {
+		r.Fprintf(color.Error, "%v\n", err)
+		os.Exit(1)
+	}
+	if help1 || help2 {
+		commandUsage(configShowUsageMsg, showCommand, showBuf)
+		return
+	}
+	if args.Options.NoColor {
+		color.NoColor = true
+	}
+	if err := processEnumInputFiles(&args); err != nil {
+		r.Fprintf(color.Error, "%v\n", err)
+		os.Exit(1)
+	}
+
+	cfg := config.NewConfig()
+	cfg.Profile = args.Profile
+	settings, err := config.ExplainConfig(args.Filepaths.Directory, args.Filepaths.ConfigFile, cfg, args)
+	if err != nil {
+		r.Fprintf(color.Error, "Failed to load the configuration: %v\n", err)
+		os.Exit(1)
+	}
+
+	var width int
+	for _, s := range settings {
+		if len(s.Key) > width {
+			width = len(s.Key)
+		}
+	}
+	for _, s := range settings {
+		fmt.Fprintf(color.Output, "%s = %s %s\n", blue(fmt.Sprintf("%-*s", width, s.Key)), s.Value, yellow("("+s.Origin+")"))
+	}
+}
+
+func runConfigInitCommand(clArgs []string) {
+	var help1, help2 bool
+	var output string
+	initCommand := flag.NewFlagSet("config init", flag.ContinueOnError)
+
+	initBuf := new(bytes.Buffer)
+	initCommand.SetOutput(initBuf)
+
+	initCommand.BoolVar(&help1, "h", false, "Show the program usage message")
+	initCommand.BoolVar(&help2, "help", false, "Show the program usage message")
+	initCommand.StringVar(&output, "o", "", "Path to the new configuration file (default: stdout)")
+
+	if err := initCommand.Parse(clArgs); err != nil {
+		r.Fprintf(color.Error, "%v\n", err)
+		os.Exit(1)
+	}
+	if help1 || help2 {
+		commandUsage(configInitUsageMsg, initCommand, initBuf)
+		return
+	}
+
+	if output == "" {
+		fmt.Fprint(os.Stdout, config.Template())
+		return
+	}
+	// The template is never written over an existing configuration file
+	f, err := os.OpenFile(output, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		r.Fprintf(color.Error, "Failed to create the configuration file: %v\n", err)
+		os.Exit(1)
+	}
+	defer f.Close()
+
+	if _, err := f.WriteString(config.Template()); err != nil {
+		r.Fprintf(color.Error, "Failed to write the configuration file: %v\n", err)
+		os.Exit(1)
+	}
+	g.Fprintf(color.Error, "The configuration file was written to %s\n", output)
+}
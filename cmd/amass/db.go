@@ -17,6 +17,7 @@ import (
 
 	"github.com/OWASP/Amass/v3/config"
 	"github.com/OWASP/Amass/v3/datasrcs"
+	"github.com/OWASP/Amass/v3/enum"
 	"github.com/OWASP/Amass/v3/format"
 	"github.com/OWASP/Amass/v3/requests"
 	"github.com/OWASP/Amass/v3/systems"
@@ -49,6 +50,7 @@ type dbArgs struct {
 		ConfigFile string
 		Directory  string
 		Domains    string
+		Import     format.ParseStrings
 		JSONOutput string
 		TermOut    string
 	}
@@ -79,9 +81,10 @@ func runDBCommand(clArgs []string) {
 	dbCommand.BoolVar(&args.Options.NoColor, "nocolor", false, "Disable colorized output")
 	dbCommand.BoolVar(&args.Options.ShowAll, "show", false, "Print the results for the enumeration index + domains provided")
 	dbCommand.BoolVar(&args.Options.Silent, "silent", false, "Disable all output during execution")
-	dbCommand.StringVar(&args.Filepaths.ConfigFile, "config", "", "Path to the INI configuration file. Additional details below")
+	dbCommand.StringVar(&args.Filepaths.ConfigFile, "config", "", "Path to the INI, YAML or JSON configuration file. Additional details below")
 	dbCommand.StringVar(&args.Filepaths.Directory, "dir", "", "Path to the directory containing the graph database")
 	dbCommand.StringVar(&args.Filepaths.Domains, "df", "", "Path to a file providing root domain names")
+	dbCommand.Var(&args.Filepaths.Import, "import", "Path to a massdns, subfinder, findomain or nmap output file to import")
 	dbCommand.StringVar(&args.Filepaths.JSONOutput, "json", "", "Path to the JSON output file")
 	dbCommand.StringVar(&args.Filepaths.TermOut, "o", "", "Path to the text file containing terminal stdout/stderr")
 
@@ -139,6 +142,11 @@ func runDBCommand(clArgs []string) {
 		os.Exit(1)
 	}
 	defer db.Close()
+	// Store the output of other tools as a new enumeration
+	if len(args.Filepaths.Import) > 0 {
+		importToolOutput(&args, cfg, db)
+		return
+	}
 	// Create the in-memory graph database for events that have information in scope
 	memDB, err := memGraphForScope(context.Background(), args.Domains.Slice(), db)
 	if err != nil {
@@ -185,6 +193,37 @@ func runDBCommand(clArgs []string) {
 	showEventData(&args, uuids, asninfo, memDB)
 }
 
+func importToolOutput(args *dbArgs, cfg *config.Config, db *netmap.Graph) {
+	ctx := context.Background()
+	uuid := cfg.UUID.String()
+	domains := args.Domains.Slice()
+
+	for _, path := range args.Filepaths.Import {
+		f, err := os.Open(path)
+		if err != nil {
+			r.Fprintf(color.Error, "Failed to open the import file: %v\n", err)
+			os.Exit(1)
+		}
+
+		var count int
+		tool, err := format.ReadImport(f, func(n *format.ImportedName) {
+			if len(domains) > 0 && !domainNameInScope(n.Name, domains) {
+				return
+			}
+			if err := enum.StoreImportedName(ctx, db, uuid, n); err == nil {
+				count++
+			}
+		})
+		f.Close()
+
+		if err != nil {
+			r.Fprintf(color.Error, "Failed to import %s: %v\n", path, err)
+			os.Exit(1)
+		}
+		g.Fprintf(color.Error, "Imported %d names from the %s output in %s\n", count, tool, path)
+	}
+}
+
 func listEvents(uuids []string, db *netmap.Graph) {
 	events, earliest, latest := orderedEvents(context.Background(), uuids, db)
 	// Check if the user has requested the list of enumerations
@@ -256,11 +295,15 @@ func showEventData(args *dbArgs, uuids []string, asninfo bool, db *netmap.Graph)
 		if ips != "" {
 			ips = " " + ips
 		}
+		seen := format.OutputSeenTimes(out)
+		if seen != "" {
+			seen = " " + seen
+		}
 
 		if args.Options.DiscoveredNames {
 			var written bool
 			if outfile != nil {
-				fmt.Fprintf(outfile, "%s%s%s\n", source, name, ips)
+				fmt.Fprintf(outfile, "%s%s%s%s\n", source, name, ips, seen)
 				written = true
 			}
 			if args.Filepaths.JSONOutput != "" {
@@ -268,7 +311,17 @@ func showEventData(args *dbArgs, uuids []string, asninfo bool, db *netmap.Graph)
 				written = true
 			}
 			if !written {
-				fmt.Fprintf(color.Output, "%s%s%s\n", blue(source), green(name), yellow(ips))
+				fmt.Fprintf(color.Output, "%s%s%s%s\n", blue(source), green(name), yellow(ips), blue(seen))
+			}
+			// The locations where the data sources found the name are shown with the sources
+			if args.Options.Sources && !args.Options.DemoMode {
+				for _, loc := range out.Provenance {
+					if outfile != nil {
+						fmt.Fprintf(outfile, "\t%s\n", loc)
+					} else if !written {
+						fmt.Fprintf(color.Output, "\t%s\n", yellow(loc))
+					}
+				}
 			}
 		}
 	}
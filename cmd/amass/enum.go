@@ -30,6 +30,7 @@ import (
 	"github.com/OWASP/Amass/v3/requests"
 	"github.com/OWASP/Amass/v3/systems"
 	"github.com/caffix/netmap"
+	"github.com/caffix/service"
 	"github.com/caffix/stringset"
 	"github.com/fatih/color"
 )
@@ -47,6 +48,7 @@ type enumArgs struct {
 	Blacklist         *stringset.Set
 	Domains           *stringset.Set
 	Excluded          *stringset.Set
+	Hosts             *stringset.Set
 	Included          *stringset.Set
 	Interface         string
 	MaxDNSQueries     int
@@ -56,13 +58,16 @@ type enumArgs struct {
 	MinForRecursive   int
 	Names             *stringset.Set
 	Ports             format.ParseInts
+	Profile           string
 	Resolvers         *stringset.Set
+	Scopes            []*config.ProgramScope
 	Trusted           *stringset.Set
 	Timeout           int
 	Options           struct {
 		Active          bool
 		Alterations     bool
 		BruteForcing    bool
+		Confidence      bool
 		DemoMode        bool
 		IPs             bool
 		IPv4            bool
@@ -87,11 +92,15 @@ type enumArgs struct {
 		Domains          format.ParseStrings
 		ExcludedSrcs     string
 		IncludedSrcs     string
+		Import           format.ParseStrings
 		JSONOutput       string
 		LogFile          string
 		Names            format.ParseStrings
+		PassiveDNS       format.ParseStrings
 		Resolvers        format.ParseStrings
+		Scopes           format.ParseStrings
 		Trusted          format.ParseStrings
+		ScanDirs         format.ParseStrings
 		ScriptsDirectory string
 		TermOut          string
 	}
@@ -106,6 +115,7 @@ func defineEnumArgumentFlags(enumFlags *flag.FlagSet, args *enumArgs) {
 	enumFlags.Var(args.BruteWordListMask, "wm", "\"hashcat-style\" wordlist masks for DNS brute forcing")
 	enumFlags.Var(args.Domains, "d", "Domain names separated by commas (can be used multiple times)")
 	enumFlags.Var(args.Excluded, "exclude", "Data source names separated by commas to be excluded")
+	enumFlags.Var(args.Hosts, "host", "Exact host names in scope separated by commas (can be used multiple times)")
 	enumFlags.Var(args.Included, "include", "Data source names separated by commas to be included")
 	enumFlags.StringVar(&args.Interface, "iface", "", "Provide the network interface to send traffic through")
 	enumFlags.IntVar(&args.MaxDNSQueries, "max-dns-queries", 0, "Deprecated flag to be replaced by dns-qps in version 4.0")
@@ -115,6 +125,7 @@ func defineEnumArgumentFlags(enumFlags *flag.FlagSet, args *enumArgs) {
 	enumFlags.IntVar(&args.MaxDepth, "max-depth", 0, "Maximum number of subdomain labels for brute forcing")
 	enumFlags.IntVar(&args.MinForRecursive, "min-for-recursive", 1, "Subdomain labels seen before recursive brute forcing (Default: 1)")
 	enumFlags.Var(&args.Ports, "p", "Ports separated by commas (default: 80, 443)")
+	enumFlags.StringVar(&args.Profile, "profile", "", "Name of the configuration file profile applied over the base settings")
 	enumFlags.Var(args.Resolvers, "r", "IP addresses of untrusted DNS resolvers (can be used multiple times)")
 	enumFlags.Var(args.Resolvers, "tr", "IP addresses of trusted DNS resolvers (can be used multiple times)")
 	enumFlags.IntVar(&args.Timeout, "timeout", 0, "Number of minutes to let enumeration run before quitting")
@@ -124,6 +135,7 @@ func defineEnumOptionFlags(enumFlags *flag.FlagSet, args *enumArgs) {
 	var placeholder bool
 	enumFlags.BoolVar(&args.Options.Active, "active", false, "Attempt zone transfers and certificate name grabs")
 	enumFlags.BoolVar(&args.Options.BruteForcing, "brute", false, "Execute brute forcing after searches")
+	enumFlags.BoolVar(&args.Options.Confidence, "conf", false, "Print the confidence value for the discovered names")
 	enumFlags.BoolVar(&args.Options.DemoMode, "demo", false, "Censor output to make it suitable for demonstrations")
 	enumFlags.BoolVar(&args.Options.IPs, "ip", false, "Show the IP addresses for discovered names")
 	enumFlags.BoolVar(&args.Options.IPv4, "ipv4", false, "Show the IPv4 addresses for discovered names")
@@ -146,16 +158,20 @@ func defineEnumFilepathFlags(enumFlags *flag.FlagSet, args *enumArgs) {
 	enumFlags.Var(&args.Filepaths.AltWordlist, "aw", "Path to a different wordlist file for alterations")
 	enumFlags.StringVar(&args.Filepaths.Blacklist, "blf", "", "Path to a file providing blacklisted subdomains")
 	enumFlags.Var(&args.Filepaths.BruteWordlist, "w", "Path to a different wordlist file for brute forcing")
-	enumFlags.StringVar(&args.Filepaths.ConfigFile, "config", "", "Path to the INI configuration file. Additional details below")
+	enumFlags.StringVar(&args.Filepaths.ConfigFile, "config", "", "Path to the INI, YAML or JSON configuration file. Additional details below")
 	enumFlags.StringVar(&args.Filepaths.Directory, "dir", "", "Path to the directory containing the output files")
 	enumFlags.Var(&args.Filepaths.Domains, "df", "Path to a file providing root domain names")
 	enumFlags.StringVar(&args.Filepaths.ExcludedSrcs, "ef", "", "Path to a file providing data sources to exclude")
 	enumFlags.StringVar(&args.Filepaths.IncludedSrcs, "if", "", "Path to a file providing data sources to include")
+	enumFlags.Var(&args.Filepaths.Import, "import", "Path to a massdns, subfinder, findomain or nmap output file to import")
 	enumFlags.StringVar(&args.Filepaths.JSONOutput, "json", "", "Path to the JSON output file")
 	enumFlags.StringVar(&args.Filepaths.LogFile, "log", "", "Path to the log file where errors will be written")
 	enumFlags.Var(&args.Filepaths.Names, "nf", "Path to a file providing already known subdomain names (from other tools/sources)")
+	enumFlags.Var(&args.Filepaths.PassiveDNS, "pdns", "Path to a file providing Passive DNS records in the Common Output Format")
 	enumFlags.Var(&args.Filepaths.Resolvers, "rf", "Path to a file providing untrusted DNS resolvers")
+	enumFlags.Var(&args.Filepaths.Scopes, "scope", "Path to a HackerOne, Bugcrowd or Intigriti scope export (CSV or JSON)")
 	enumFlags.Var(&args.Filepaths.Trusted, "trf", "Path to a file providing trusted DNS resolvers")
+	enumFlags.Var(&args.Filepaths.ScanDirs, "scan-dir", "Path to a local directory or git repository to search for names")
 	enumFlags.StringVar(&args.Filepaths.ScriptsDirectory, "scripts", "", "Path to a directory containing ADS scripts")
 	enumFlags.StringVar(&args.Filepaths.TermOut, "o", "", "Path to the text file containing terminal stdout/stderr")
 }
@@ -229,10 +245,10 @@ func runEnumCommand(clArgs []string) {
 
 	var ctx context.Context
 	var cancel context.CancelFunc
-	if args.Timeout == 0 {
+	if cfg.Timeout == 0 {
 		ctx, cancel = context.WithCancel(context.Background())
 	} else {
-		ctx, cancel = context.WithTimeout(context.Background(), time.Duration(args.Timeout)*time.Minute)
+		ctx, cancel = context.WithTimeout(context.Background(), time.Duration(cfg.Timeout)*time.Minute)
 	}
 	defer cancel()
 
@@ -260,6 +276,8 @@ func runEnumCommand(clArgs []string) {
 	close(done)
 	wg.Wait()
 	fmt.Fprintf(color.Error, "\n%s\n", green("The enumeration has finished"))
+	printSourceReports(e.SourceReports())
+	printCacheStats(sys.DataSources())
 	// If necessary, handle graph database migration
 	if len(e.Sys.GraphDatabases()) > 1 {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
@@ -286,8 +304,69 @@ func runEnumCommand(clArgs []string) {
 	}
 }
 
-func argsAndConfig(clArgs []string) (*config.Config, *enumArgs) {
-	args := enumArgs{
+// Returns the confidence value to be shown before the discovered name when requested.
+func confidencePart(out *requests.Output, args *enumArgs) string {
+	if !args.Options.Confidence {
+		return ""
+	}
+	return fmt.Sprintf("%-6s", fmt.Sprintf("%.2f", out.Confidence))
+}
+
+// Prints the effectiveness of each data source that provided names or sent requests during the enumeration.
+func printSourceReports(reports []*enum.SourceReport) {
+	var lines []string
+
+	for _, rep := range reports {
+		if rep.Submitted == 0 && rep.Requests == 0 {
+			continue
+		}
+
+		lines = append(lines, fmt.Sprintf("%-35s %-20s %-20s %-20s %-20s %-20s %-20s %s",
+			blue(rep.Source), yellow(rep.Submitted), yellow(rep.Validated), yellow(rep.Unique),
+			yellow(rep.Wildcards), red(rep.Errors), yellow(rep.AverageLatency.Round(time.Millisecond)),
+			yellow(rep.RateLimitWait.Round(time.Second))))
+	}
+	if len(lines) == 0 {
+		return
+	}
+
+	fmt.Fprintf(color.Error, "\n%s\n", green("Data Source Effectiveness"))
+	fmt.Fprintf(color.Error, "%-35s %-20s %-20s %-20s %-20s %-20s %-20s %s\n", blue("Data Source"),
+		blue("Submitted"), blue("Validated"), blue("Unique"), blue("Wildcards"), blue("Errors"),
+		blue("Avg Latency"), blue("Rate Limit Wait"))
+	for _, line := range lines {
+		fmt.Fprintln(color.Error, line)
+	}
+}
+
+// Prints the cache hit rate for each data source that looked for cached responses during the enumeration.
+func printCacheStats(srcs []service.Service) {
+	var lines []string
+
+	for _, src := range srcs {
+		cs, ok := src.(datasrcs.CacheStatsSource)
+		if !ok {
+			continue
+		}
+
+		hits, misses := cs.CacheStats()
+		if total := hits + misses; total > 0 {
+			rate := float64(hits) / float64(total) * 100
+			lines = append(lines, fmt.Sprintf("%-35s %s", blue(src.String()),
+				yellow(fmt.Sprintf("%d of %d responses from the cache (%.1f%%)", hits, total, rate))))
+		}
+	}
+
+	if len(lines) > 0 {
+		fmt.Fprintf(color.Error, "\n%s\n", green("Data Source Cache Hit Rates"))
+		for _, line := range lines {
+			fmt.Fprintln(color.Error, line)
+		}
+	}
+}
+
+func newEnumArgs() enumArgs {
+	return enumArgs{
 		AltWordList:       stringset.New(),
 		AltWordListMask:   stringset.New(),
 		BruteWordList:     stringset.New(),
@@ -295,11 +374,16 @@ func argsAndConfig(clArgs []string) (*config.Config, *enumArgs) {
 		Blacklist:         stringset.New(),
 		Domains:           stringset.New(),
 		Excluded:          stringset.New(),
+		Hosts:             stringset.New(),
 		Included:          stringset.New(),
 		Names:             stringset.New(),
 		Resolvers:         stringset.New(),
 		Trusted:           stringset.New(),
 	}
+}
+
+func argsAndConfig(clArgs []string) (*config.Config, *enumArgs) {
+	args := newEnumArgs()
 	var help1, help2 bool
 	enumCommand := flag.NewFlagSet("enum", flag.ContinueOnError)
 
@@ -361,13 +445,14 @@ func argsAndConfig(clArgs []string) (*config.Config, *enumArgs) {
 	}
 
 	cfg := config.NewConfig()
+	cfg.Profile = args.Profile
 	// Check if a configuration file was provided, and if so, load the settings
 	if err := config.AcquireConfig(args.Filepaths.Directory, args.Filepaths.ConfigFile, cfg); err == nil {
 		// Check if a config file was provided that has DNS resolvers specified
 		if len(cfg.Resolvers) > 0 && args.Resolvers.Len() == 0 {
 			args.Resolvers = stringset.New(cfg.Resolvers...)
 		}
-	} else if args.Filepaths.ConfigFile != "" {
+	} else if args.Filepaths.ConfigFile != "" || args.Profile != "" {
 		r.Fprintf(color.Error, "Failed to load the configuration file: %v\n", err)
 		os.Exit(1)
 	}
@@ -392,8 +477,8 @@ func argsAndConfig(clArgs []string) (*config.Config, *enumArgs) {
 		r.Fprintln(color.Error, "Ports can only be scanned in the active mode")
 		os.Exit(1)
 	}
-	if len(cfg.Domains()) == 0 {
-		r.Fprintln(color.Error, "Configuration error: No root domain names were provided")
+	if len(cfg.Domains()) == 0 && len(cfg.Hosts()) == 0 {
+		r.Fprintln(color.Error, "Configuration error: No root domain names or hosts were provided")
 		os.Exit(1)
 	}
 	return cfg, &args
@@ -423,7 +508,7 @@ func printOutput(e *enum.Enumeration, args *enumArgs, output chan *requests.Outp
 			ips = " " + ips
 		}
 
-		fmt.Fprintf(color.Output, "%s%s%s\n", blue(source), green(name), yellow(ips))
+		fmt.Fprintf(color.Output, "%s%s%s%s\n", blue(source), yellow(confidencePart(out, args)), green(name), yellow(ips))
 	}
 
 	if total == 0 {
@@ -473,7 +558,7 @@ func saveTextOutput(e *enum.Enumeration, args *enumArgs, output chan *requests.O
 			ips = " " + ips
 		}
 		// Write the line to the output file
-		fmt.Fprintf(outptr, "%s%s%s\n", source, name, ips)
+		fmt.Fprintf(outptr, "%s%s%s%s\n", source, confidencePart(out, args), name, ips)
 	}
 }
 
@@ -677,6 +762,11 @@ func processEnumInputFiles(args *enumArgs) error {
 			args.Resolvers.InsertMany(list...)
 		}
 	}
+	scopes, err := readScopeFiles(args.Filepaths.Scopes)
+	if err != nil {
+		return err
+	}
+	args.Scopes = scopes
 	return nil
 }
 
@@ -703,6 +793,17 @@ func (e enumArgs) OverrideConfig(conf *config.Config) error {
 	if e.Names.Len() > 0 {
 		conf.ProvidedNames = e.Names.Slice()
 	}
+	if len(e.Filepaths.PassiveDNS) > 0 {
+		conf.PassiveDNSFiles = e.Filepaths.PassiveDNS
+	}
+	if len(e.Filepaths.Import) > 0 {
+		conf.ImportFiles = e.Filepaths.Import
+	}
+	if len(e.Filepaths.ScanDirs) > 0 {
+		// The directories are searched by the LocalFiles data source
+		dsc := conf.GetDataSourceConfig("LocalFiles")
+		dsc.SetOption("path", append(dsc.OptionValues("path"), e.Filepaths.ScanDirs...)...)
+	}
 	if e.BruteWordList.Len() > 0 {
 		conf.Wordlist = e.BruteWordList.Slice()
 	}
@@ -737,6 +838,9 @@ func (e enumArgs) OverrideConfig(conf *config.Config) error {
 	if e.Blacklist.Len() > 0 {
 		conf.Blacklist = e.Blacklist.Slice()
 	}
+	for _, scope := range e.Scopes {
+		conf.ApplyScope(scope)
+	}
 	if e.Options.Verbose {
 		conf.Verbose = true
 	}
@@ -755,6 +859,9 @@ func (e enumArgs) OverrideConfig(conf *config.Config) error {
 	if e.MaxDNSQueries > 0 {
 		conf.MaxDNSQueries = e.MaxDNSQueries
 	}
+	if e.Timeout > 0 {
+		conf.Timeout = e.Timeout
+	}
 	if e.Included.Len() > 0 {
 		conf.SourceFilter.Include = true
 		// Check if brute forcing and alterations should be added
@@ -765,7 +872,7 @@ func (e enumArgs) OverrideConfig(conf *config.Config) error {
 			e.Included.Insert(requests.BRUTE)
 		}
 		conf.SourceFilter.Sources = e.Included.Slice()
-	} else if e.Excluded.Len() > 0 || conf.Alterations || conf.BruteForcing {
+	} else if e.Excluded.Len() > 0 {
 		conf.SourceFilter.Include = false
 		// Check if brute forcing and alterations should be added
 		if conf.Alterations {
@@ -776,7 +883,8 @@ func (e enumArgs) OverrideConfig(conf *config.Config) error {
 		}
 		conf.SourceFilter.Sources = e.Excluded.Slice()
 	}
-	// Attempt to add the provided domains to the configuration
+	// Attempt to add the provided domains and hosts to the configuration
 	conf.AddDomains(e.Domains.Slice()...)
+	conf.AddHosts(e.Hosts.Slice()...)
 	return nil
 }
@@ -19,6 +19,10 @@ func runHelpCommand(clArgs []string) {
 		return
 	}
 	switch clArgs[0] {
+	case "cache":
+		runCacheCommand(help)
+	case "config":
+		runConfigCommand(help)
 	case "db":
 		runDBCommand(help)
 	case "enum":
@@ -15,6 +15,7 @@ import (
 	"os/signal"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
@@ -35,13 +36,16 @@ type intelArgs struct {
 	Addresses        format.ParseIPs
 	ASNs             format.ParseASNs
 	CIDRs            format.ParseCIDRs
+	CountryCodes     format.ParseStrings
 	OrganizationName string
 	Domains          *stringset.Set
 	Excluded         *stringset.Set
 	Included         *stringset.Set
 	MaxDNSQueries    int
 	Ports            format.ParseInts
+	Profile          string
 	Resolvers        *stringset.Set
+	Scopes           []*config.ProgramScope
 	Timeout          int
 	Options          struct {
 		Active       bool
@@ -59,9 +63,11 @@ type intelArgs struct {
 		Directory    string
 		Domains      format.ParseStrings
 		ExcludedSrcs string
+		Hitlists     format.ParseStrings
 		IncludedSrcs string
 		LogFile      string
 		Resolvers    format.ParseStrings
+		Scopes       format.ParseStrings
 		TermOut      string
 	}
 }
@@ -69,13 +75,15 @@ type intelArgs struct {
 func defineIntelArgumentFlags(intelFlags *flag.FlagSet, args *intelArgs) {
 	intelFlags.Var(&args.Addresses, "addr", "IPs and ranges (192.168.1.1-254) separated by commas")
 	intelFlags.Var(&args.ASNs, "asn", "ASNs separated by commas (can be used multiple times)")
-	intelFlags.Var(&args.CIDRs, "cidr", "CIDRs separated by commas (can be used multiple times)")
+	intelFlags.Var(&args.CIDRs, "cidr", "CIDRs and ranges (2001:db8::-2001:db8::ff) separated by commas (can be used multiple times)")
 	intelFlags.StringVar(&args.OrganizationName, "org", "", "Search string provided against AS description information")
+	intelFlags.Var(&args.CountryCodes, "cc", "Country codes separated by commas that the -org search is restricted to")
 	intelFlags.Var(args.Domains, "d", "Domain names separated by commas (can be used multiple times)")
 	intelFlags.Var(args.Excluded, "exclude", "Data source names separated by commas to be excluded")
 	intelFlags.Var(args.Included, "include", "Data source names separated by commas to be included")
 	intelFlags.IntVar(&args.MaxDNSQueries, "max-dns-queries", 0, "Maximum number of concurrent DNS queries")
 	intelFlags.Var(&args.Ports, "p", "Ports separated by commas (default: 80, 443)")
+	intelFlags.StringVar(&args.Profile, "profile", "", "Name of the configuration file profile applied over the base settings")
 	intelFlags.Var(args.Resolvers, "r", "IP addresses of preferred DNS resolvers (can be used multiple times)")
 	intelFlags.IntVar(&args.Timeout, "timeout", 0, "Number of minutes to let enumeration run before quitting")
 }
@@ -93,13 +101,15 @@ func defineIntelOptionFlags(intelFlags *flag.FlagSet, args *intelArgs) {
 }
 
 func defineIntelFilepathFlags(intelFlags *flag.FlagSet, args *intelArgs) {
-	intelFlags.StringVar(&args.Filepaths.ConfigFile, "config", "", "Path to the INI configuration file. Additional details below")
+	intelFlags.StringVar(&args.Filepaths.ConfigFile, "config", "", "Path to the INI, YAML or JSON configuration file. Additional details below")
 	intelFlags.StringVar(&args.Filepaths.Directory, "dir", "", "Path to the directory containing the output files")
 	intelFlags.Var(&args.Filepaths.Domains, "df", "Path to a file providing root domain names")
 	intelFlags.StringVar(&args.Filepaths.ExcludedSrcs, "ef", "", "Path to a file providing data sources to exclude")
+	intelFlags.Var(&args.Filepaths.Hitlists, "hitlist", "Path to a file providing IPv6 addresses known to be in use")
 	intelFlags.StringVar(&args.Filepaths.IncludedSrcs, "if", "", "Path to a file providing data sources to include")
 	intelFlags.StringVar(&args.Filepaths.LogFile, "log", "", "Path to the log file where errors will be written")
 	intelFlags.Var(&args.Filepaths.Resolvers, "rf", "Path to a file providing preferred DNS resolvers")
+	intelFlags.Var(&args.Filepaths.Scopes, "scope", "Path to a HackerOne, Bugcrowd or Intigriti scope export (CSV or JSON)")
 	intelFlags.StringVar(&args.Filepaths.TermOut, "o", "", "Path to the text file containing terminal stdout/stderr")
 }
 
@@ -145,13 +155,14 @@ func runIntelCommand(clArgs []string) {
 	}
 
 	cfg := config.NewConfig()
+	cfg.Profile = args.Profile
 	// Check if a configuration file was provided, and if so, load the settings
 	if err := config.AcquireConfig(args.Filepaths.Directory, args.Filepaths.ConfigFile, cfg); err == nil {
 		// Check if a config file was provided that has DNS resolvers specified
 		if len(cfg.Resolvers) > 0 && args.Resolvers.Len() == 0 {
 			args.Resolvers = stringset.New(cfg.Resolvers...)
 		}
-	} else if args.Filepaths.ConfigFile != "" {
+	} else if args.Filepaths.ConfigFile != "" || args.Profile != "" {
 		r.Fprintf(color.Error, "Failed to load the configuration file: %v\n", err)
 		os.Exit(1)
 	}
@@ -181,6 +192,14 @@ func runIntelCommand(clArgs []string) {
 		return
 	}
 
+	// The organization search is performed offline against the bundled ASN data
+	if args.OrganizationName != "" {
+		if !printOrganizationSearch(args.OrganizationName, args.CountryCodes) {
+			os.Exit(1)
+		}
+		return
+	}
+
 	rLog, wLog := io.Pipe()
 	cfg.Log = log.New(wLog, "", log.Lmicroseconds)
 	logfile := filepath.Join(config.OutputDirectory(cfg.Dir), "amass.log")
@@ -200,16 +219,6 @@ func runIntelCommand(clArgs []string) {
 		return
 	}
 
-	if args.OrganizationName != "" {
-		var asns []int
-		for _, entry := range sys.Cache().DescriptionSearch(args.OrganizationName) {
-			asns = append(asns, entry.ASN)
-		}
-		if len(asns) > 0 {
-			printNetblocks(asns, cfg, sys)
-		}
-		return
-	}
 	// Check if the user requested additional ASN & netblock information
 	if args.Options.ListSources && len(args.ASNs) > 0 {
 		printNetblocks(args.ASNs, cfg, sys)
@@ -235,10 +244,10 @@ func runIntelCommand(clArgs []string) {
 	} else {
 		var ctx context.Context
 		var cancel context.CancelFunc
-		if args.Timeout == 0 {
+		if cfg.Timeout == 0 {
 			ctx, cancel = context.WithCancel(context.Background())
 		} else {
-			ctx, cancel = context.WithTimeout(context.Background(), time.Duration(args.Timeout)*time.Minute)
+			ctx, cancel = context.WithTimeout(context.Background(), time.Duration(cfg.Timeout)*time.Minute)
 		}
 		defer cancel()
 		// Monitor for cancellation by the user
@@ -261,6 +270,38 @@ func runIntelCommand(clArgs []string) {
 	}
 }
 
+func printOrganizationSearch(name string, ccs []string) bool {
+	cache := cacheWithData()
+	if cache == nil {
+		r.Fprintln(color.Error, "Failed to load the bundled ASN data")
+		return false
+	}
+
+	groups := cache.OrganizationSearch(name, ccs...)
+	if len(groups) == 0 {
+		r.Fprintf(color.Error, "No ASNs matched the organization %s\n", name)
+		return false
+	}
+
+	var asns []string
+	for _, g := range groups {
+		fmt.Printf("%s%s %s\n", blue("Organization: "), green(g.Name), yellow(fmt.Sprintf("(%.2f)", g.Score)))
+
+		for _, as := range g.ASNs {
+			asn := strconv.Itoa(as.ASN)
+
+			fmt.Printf("%s%s %s %s %s\n", blue("ASN: "), yellow(asn), green("-"), green(as.Description), blue(as.CC))
+			for _, cidr := range as.Netblocks {
+				fmt.Printf("%s\n", yellow(fmt.Sprintf("\t%s", cidr)))
+			}
+			asns = append(asns, asn)
+		}
+	}
+	// Provide the ASNs ready for the next step of the investigation
+	fmt.Printf("\n%s%s\n", blue("ASNs: "), yellow(strings.Join(asns, ",")))
+	return true
+}
+
 func printNetblocks(asns []int, cfg *config.Config, sys systems.System) {
 	for _, asn := range asns {
 		systems.PopulateCache(context.Background(), asn, sys)
@@ -318,9 +359,67 @@ func processIntelOutput(ic *intel.Collection, args *intelArgs) bool {
 		}
 		found = true
 	}
+	// Show how the certificates obtained in the active mode group the addresses and domains
+	if clusters := ic.CertClusters(); len(clusters) > 0 {
+		printCertClusters(clusters, outptr, args.Options.DemoMode)
+	}
 	return found
 }
 
+func printCertClusters(clusters []*intel.CertCluster, outptr *os.File, demo bool) {
+	fmt.Fprintf(color.Output, "\n%s\n", blue("Certificate Clusters"))
+	if outptr != nil {
+		fmt.Fprintf(outptr, "\nCertificate Clusters\n")
+	}
+
+	for _, cluster := range clusters {
+		org, details, addrs, domains := certClusterParts(cluster, demo)
+
+		fmt.Fprintf(color.Output, "%s %s %s\n", green(org), blue("-"), yellow(details))
+		fmt.Fprintf(color.Output, "\t%s %s\n", blue("Addresses:"), yellow(addrs))
+		fmt.Fprintf(color.Output, "\t%s %s\n", blue("Domains:"), green(domains))
+		if outptr != nil {
+			fmt.Fprintf(outptr, "%s - %s\n\tAddresses: %s\n\tDomains: %s\n", org, details, addrs, domains)
+		}
+	}
+}
+
+// Returns the parts of the lines to be printed for the certificate cluster.
+func certClusterParts(cluster *intel.CertCluster, demo bool) (org, details, addrs, domains string) {
+	org = strings.Join(cluster.Organizations, ", ")
+	if org == "" {
+		org = "No Subject Organization"
+	} else if demo {
+		org = format.CensorString(org, 0, len(org))
+	}
+
+	details = fmt.Sprintf("%d certificate(s)", cluster.Certificates)
+	if len(cluster.Issuers) > 0 {
+		details += " issued by " + strings.Join(cluster.Issuers, ", ")
+	}
+
+	for i, a := range cluster.Addresses {
+		if i != 0 {
+			addrs += ","
+		}
+		if demo {
+			a = format.CensorIP(a)
+		}
+		addrs += a
+	}
+
+	for i, d := range cluster.Domains {
+		if i != 0 {
+			domains += ","
+		}
+		if demo {
+			d = format.CensorDomain(d)
+		}
+		domains += d
+	}
+	return
+}
+
 // Obtain parameters from provided input files
 func processIntelInputFiles(args *intelArgs) error {
 	if args.Filepaths.ExcludedSrcs != "" {
@@ -357,6 +456,11 @@ func processIntelInputFiles(args *intelArgs) error {
 			args.Resolvers.InsertMany(list...)
 		}
 	}
+	scopes, err := readScopeFiles(args.Filepaths.Scopes)
+	if err != nil {
+		return err
+	}
+	args.Scopes = scopes
 	return nil
 }
 
@@ -380,6 +484,9 @@ func (i intelArgs) OverrideConfig(conf *config.Config) error {
 	if i.Filepaths.Directory != "" {
 		conf.Dir = i.Filepaths.Directory
 	}
+	if len(i.Filepaths.Hitlists) > 0 {
+		conf.IPv6HitlistFiles = i.Filepaths.Hitlists
+	}
 	if i.Options.Verbose {
 		conf.Verbose = true
 	}
@@ -389,6 +496,12 @@ func (i intelArgs) OverrideConfig(conf *config.Config) error {
 	if i.MaxDNSQueries > 0 {
 		conf.MaxDNSQueries = i.MaxDNSQueries
 	}
+	if i.Timeout > 0 {
+		conf.Timeout = i.Timeout
+	}
+	for _, scope := range i.Scopes {
+		conf.ApplyScope(scope)
+	}
 
 	if i.Included.Len() > 0 {
 		conf.SourceFilter.Include = true
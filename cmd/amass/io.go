@@ -26,10 +26,19 @@ func init() {
 
 // ExtractOutput is a convenience method for obtaining new discoveries made by the enumeration process.
 func ExtractOutput(ctx context.Context, g *netmap.Graph, e *enum.Enumeration, filter *stringset.Set, asinfo bool, limit int) []*requests.Output {
+	var output []*requests.Output
+
 	if e.Config.Passive {
-		return EventNames(ctx, g, e.Config.UUID.String(), filter)
+		output = EventNames(ctx, g, e.Config.UUID.String(), filter)
+	} else {
+		output = EventOutput(ctx, g, e.Config.UUID.String(), filter, asinfo, e.Sys.Cache(), limit)
+	}
+
+	trust := e.TrustScores()
+	for _, o := range output {
+		o.Confidence = trust.Confidence(o.Sources)
 	}
-	return EventOutput(ctx, g, e.Config.UUID.String(), filter, asinfo, e.Sys.Cache(), limit)
+	return output
 }
 
 type outLookup map[string]*requests.Output
@@ -178,6 +187,14 @@ func buildNameInfo(ctx context.Context, g *netmap.Graph, uuid string, names []st
 		o.Domain = d
 
 		o.Tag = selectTag(o.Sources)
+		// Names imported from Passive DNS records have observation times
+		if first, last, err := enum.ReadSeenTimes(ctx, g, o.Name); err == nil && !first.IsZero() {
+			o.FirstSeen, o.LastSeen = &first, &last
+		}
+		// Names found in local files have the paths and commits they were found in
+		if locations, err := enum.ReadProvenance(ctx, g, o.Name); err == nil {
+			o.Provenance = locations
+		}
 		final = append(final, o)
 	}
 	return final
@@ -49,7 +49,7 @@ import (
 )
 
 const (
-	mainUsageMsg         = "intel|enum|viz|track|db [options]"
+	mainUsageMsg         = "intel|enum|viz|track|db|cache|config [options]"
 	exampleConfigFileURL = "https://github.com/OWASP/Amass/blob/master/examples/config.ini"
 	userGuideURL         = "https://github.com/OWASP/Amass/blob/master/doc/user_guide.md"
 	tutorialURL          = "https://github.com/OWASP/Amass/blob/master/doc/tutorial.md"
@@ -81,6 +81,8 @@ func commandUsage(msg string, cmdFlagSet *flag.FlagSet, errBuf *bytes.Buffer) {
 		g.Fprintf(color.Error, "\t%-11s - Visualize enumeration results\n", "amass viz")
 		g.Fprintf(color.Error, "\t%-11s - Track differences between enumerations\n", "amass track")
 		g.Fprintf(color.Error, "\t%-11s - Manipulate the Amass graph database\n", "amass db")
+		g.Fprintf(color.Error, "\t%-11s - Manage the cached data source responses\n", "amass cache")
+		g.Fprintf(color.Error, "\t%-11s - Check, show and generate configuration files\n", "amass config")
 	}
 
 	g.Fprintln(color.Error)
@@ -118,6 +120,10 @@ func main() {
 	}
 
 	switch os.Args[1] {
+	case "cache":
+		runCacheCommand(os.Args[2:])
+	case "config":
+		runConfigCommand(os.Args[2:])
 	case "db":
 		runDBCommand(os.Args[2:])
 	case "enum":
@@ -159,13 +165,15 @@ func GetAllSourceInfo(cfg *config.Config) []string {
 func DataSourceInfo(all []service.Service, sys systems.System) []string {
 	var names []string
 
-	names = append(names, fmt.Sprintf("%-35s%-35s%s", blue("Data Source"), blue("| Type"), blue("| Available")))
+	names = append(names, fmt.Sprintf("%-35s%-35s%-22s%-35s%-20s%s", blue("Data Source"), blue("| Type"),
+		blue("| Available"), blue("| Missing Credentials"), blue("| TTL"), blue("| Rate Limit")))
 	var line string
-	for i := 0; i < 8; i++ {
+	for i := 0; i < 14; i++ {
 		line += blue("----------")
 	}
 	names = append(names, line)
 
+	cfg := sys.Config()
 	available := sys.DataSources()
 	for _, src := range all {
 		var avail string
@@ -177,8 +185,29 @@ func DataSourceInfo(all []service.Service, sys systems.System) []string {
 			}
 		}
 
-		names = append(names, fmt.Sprintf("%-35s  %-35s  %s",
-			green(src.String()), yellow(src.Description()), yellow(avail)))
+		ttl := "-"
+		missing := "-"
+		ratelimit := "-"
+		if dsc := cfg.GetDataSourceConfig(src.String()); dsc != nil {
+			if dsc.TTL > 0 {
+				ttl = fmt.Sprintf("%dm", dsc.TTL)
+			}
+
+			if ms, ok := src.(datasrcs.MetadataSource); ok {
+				m := ms.Metadata()
+
+				if fields := dsc.MissingCredentials(m.Credentials...); len(fields) > 0 {
+					missing = strings.Join(fields, ",")
+				}
+				if m.RateLimit > 0 {
+					ratelimit = fmt.Sprintf("%ds", m.RateLimit)
+				}
+			}
+		}
+
+		names = append(names, fmt.Sprintf("%-35s  %-35s  %-20s  %-33s  %-18s  %s",
+			green(src.String()), yellow(src.Description()), yellow(avail), red(missing),
+			yellow(ttl), yellow(ratelimit)))
 	}
 
 	return names
@@ -198,6 +227,23 @@ func createOutputDirectory(cfg *config.Config) {
 	}
 }
 
+// Reads the bug bounty program scope exports and warns about the assets that could not be mapped.
+func readScopeFiles(paths []string) ([]*config.ProgramScope, error) {
+	var scopes []*config.ProgramScope
+
+	for _, path := range paths {
+		scope, err := config.ReadScopeFile(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, asset := range scope.Unmapped {
+			fgY.Fprintf(color.Error, "Could not map the scope asset %s from %s\n", asset, path)
+		}
+		scopes = append(scopes, scope)
+	}
+	return scopes, nil
+}
+
 func generateCategoryMap(sys systems.System) map[string][]string {
 	catToSources := make(map[string][]string)
 
@@ -14,6 +14,7 @@ import (
 	"time"
 
 	"github.com/OWASP/Amass/v3/config"
+	"github.com/OWASP/Amass/v3/format"
 	"github.com/OWASP/Amass/v3/requests"
 	"github.com/caffix/netmap"
 	"github.com/caffix/stringset"
@@ -28,6 +29,7 @@ const (
 type trackArgs struct {
 	Domains *stringset.Set
 	Last    int
+	Profile string
 	Since   string
 	Options struct {
 		History bool
@@ -56,11 +58,12 @@ func runTrackCommand(clArgs []string) {
 	trackCommand.BoolVar(&help2, "help", false, "Show the program usage message")
 	trackCommand.Var(args.Domains, "d", "Domain names separated by commas (can be used multiple times)")
 	trackCommand.IntVar(&args.Last, "last", 0, "The number of recent enumerations to include in the tracking")
+	trackCommand.StringVar(&args.Profile, "profile", "", "Name of the configuration file profile applied over the base settings")
 	trackCommand.StringVar(&args.Since, "since", "", "Exclude all enumerations before (format: "+timeFormat+")")
 	trackCommand.BoolVar(&args.Options.History, "history", false, "Show the difference between all enumeration pairs")
 	trackCommand.BoolVar(&args.Options.NoColor, "nocolor", false, "Disable colorized output")
 	trackCommand.BoolVar(&args.Options.Silent, "silent", false, "Disable all output during execution")
-	trackCommand.StringVar(&args.Filepaths.ConfigFile, "config", "", "Path to the INI configuration file. Additional details below")
+	trackCommand.StringVar(&args.Filepaths.ConfigFile, "config", "", "Path to the INI, YAML or JSON configuration file. Additional details below")
 	trackCommand.StringVar(&args.Filepaths.Directory, "dir", "", "Path to the directory containing the graph database")
 	trackCommand.StringVar(&args.Filepaths.Domains, "df", "", "Path to a file providing root domain names")
 
@@ -116,6 +119,7 @@ func runTrackCommand(clArgs []string) {
 	}
 
 	cfg := config.NewConfig()
+	cfg.Profile = args.Profile
 	// Check if a configuration file was provided, and if so, load the settings
 	if err := config.AcquireConfig(args.Filepaths.Directory, args.Filepaths.ConfigFile, cfg); err == nil {
 		if args.Filepaths.Directory == "" {
@@ -124,7 +128,7 @@ func runTrackCommand(clArgs []string) {
 		if args.Domains.Len() == 0 {
 			args.Domains.InsertMany(cfg.Domains()...)
 		}
-	} else if args.Filepaths.ConfigFile != "" {
+	} else if args.Filepaths.ConfigFile != "" || args.Profile != "" {
 		r.Fprintf(color.Error, "Failed to load the configuration file: %v\n", err)
 		os.Exit(1)
 	}
@@ -295,8 +299,8 @@ func diffEnumOutput(older, newer []*requests.Output) []string {
 	for name, o := range newmap {
 		o2, found := oldmap[name]
 		if !found {
-			diff = append(diff, fmt.Sprintf("%s%s %s", blue("Found: "),
-				green(name), yellow(lineOfAddresses(o.Addresses))))
+			diff = append(diff, fmt.Sprintf("%s%s %s%s", blue("Found: "),
+				green(name), yellow(lineOfAddresses(o.Addresses)), seenTimes(o)))
 			continue
 		}
 
@@ -316,6 +320,13 @@ func diffEnumOutput(older, newer []*requests.Output) []string {
 	return diff
 }
 
+func seenTimes(o *requests.Output) string {
+	if seen := format.OutputSeenTimes(o); seen != "" {
+		return " " + blue(seen)
+	}
+	return ""
+}
+
 func lineOfAddresses(addrs []requests.AddressInfo) string {
 	var line string
 
@@ -59,7 +59,7 @@ func runVizCommand(clArgs []string) {
 	vizCommand.BoolVar(&help2, "help", false, "Show the program usage message")
 	vizCommand.Var(args.Domains, "d", "Domain names separated by commas (can be used multiple times)")
 	vizCommand.IntVar(&args.Enum, "enum", 0, "Identify an enumeration via an index from the listing")
-	vizCommand.StringVar(&args.Filepaths.ConfigFile, "config", "", "Path to the INI configuration file. Additional details below")
+	vizCommand.StringVar(&args.Filepaths.ConfigFile, "config", "", "Path to the INI, YAML or JSON configuration file. Additional details below")
 	vizCommand.StringVar(&args.Filepaths.Directory, "dir", "", "Path to the directory containing the graph database")
 	vizCommand.StringVar(&args.Filepaths.Domains, "df", "", "Path to a file providing root domain names")
 	vizCommand.StringVar(&args.Filepaths.Input, "i", "", "The Amass data operations JSON file")
@@ -0,0 +1,466 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/OWASP/Amass/v3/format"
+	"github.com/OWASP/Amass/v3/net/dns"
+	"github.com/caffix/stringset"
+)
+
+// The bug bounty platforms recognized in the scope exports.
+const (
+	PlatformHackerOne = "HackerOne"
+	PlatformBugcrowd  = "Bugcrowd"
+	PlatformIntigriti = "Intigriti"
+)
+
+// The fields identifying the asset, asset type and platform in the scope exports, in order of preference.
+var (
+	scopeIdentifierFields = []string{"asset_identifier", "identifier", "endpoint", "target", "asset"}
+	scopeTypeFields       = []string{"asset_type", "type", "category"}
+	scopeFieldPlatforms   = map[string]string{
+		"asset_identifier":        PlatformHackerOne,
+		"asset_type":              PlatformHackerOne,
+		"eligible_for_submission": PlatformHackerOne,
+		"endpoint":                PlatformIntigriti,
+		"tier":                    PlatformIntigriti,
+		"target":                  PlatformBugcrowd,
+		"category":                PlatformBugcrowd,
+	}
+	// The asset types that can provide DNS names and netblocks, after removing all but the letters
+	scopeNetworkTypes = map[string]struct{}{
+		"":          {},
+		"url":       {},
+		"wildcard":  {},
+		"domain":    {},
+		"website":   {},
+		"api":       {},
+		"cidr":      {},
+		"ipaddress": {},
+		"iprange":   {},
+		"network":   {},
+	}
+	scopeHostRE = regexp.MustCompile("^" + dns.AnySubdomainRegexString() + "$")
+)
+
+// ProgramScope is the scope of a bug bounty program read from the export of the platform.
+type ProgramScope struct {
+	Platform string
+	// The root domain names provided by the in scope wildcard assets
+	Domains []string
+	// The exact hosts that are in scope
+	Names []string
+	// The IP addresses and netblocks that are in scope
+	Addresses []net.IP
+	CIDRs     []*net.IPNet
+	// The names, patterns and netblocks that are out of scope
+	Blacklist     []string
+	ExcludedCIDRs []*net.IPNet
+	// The assets that could not be mapped to the enumeration scope
+	Unmapped []string
+}
+
+type scopeAsset struct {
+	identifier string
+	kind       string
+	inScope    bool
+}
+
+// ReadScopeFile reads the scope export, in the CSV or JSON format, downloaded from HackerOne, Bugcrowd
+// or Intigriti. Wildcard assets provide root domain names, exact hosts provide names, and the CIDRs and
+// IP addresses provide netblocks. Out of scope assets become exclusions. Assets that cannot be mapped,
+// such as mobile applications and source code repositories, are listed in the Unmapped field.
+func ReadScopeFile(path string) (*ProgramScope, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the scope file %s: %v", path, err)
+	}
+	defer f.Close()
+
+	s, err := ReadScope(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the scope file %s: %v", path, err)
+	}
+	return s, nil
+}
+
+// ReadScope reads a bug bounty program scope export in the CSV or JSON format.
+func ReadScope(r io.Reader) (*ProgramScope, error) {
+	br := bufio.NewReader(r)
+
+	var platform string
+	var assets []*scopeAsset
+	if first, err := format.FirstNonSpace(br); err == io.EOF {
+		return nil, errors.New("the file is empty")
+	} else if err != nil {
+		return nil, err
+	} else if first == '{' || first == '[' {
+		platform, assets, err = readScopeJSON(br)
+		if err != nil {
+			return nil, err
+		}
+	} else if platform, assets, err = readScopeCSV(br); err != nil {
+		return nil, err
+	}
+	if len(assets) == 0 {
+		return nil, errors.New("no scope assets were found")
+	}
+
+	s := &ProgramScope{Platform: platform}
+	for _, a := range assets {
+		s.addAsset(a)
+	}
+
+	s.Domains = stringset.Deduplicate(s.Domains)
+	s.Names = stringset.Deduplicate(s.Names)
+	s.Blacklist = stringset.Deduplicate(s.Blacklist)
+	sort.Strings(s.Unmapped)
+	return s, nil
+}
+
+// Reads the CSV export, where the header row names the columns.
+func readScopeCSV(r io.Reader) (string, []*scopeAsset, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.LazyQuotes = true
+
+	header, err := cr.Read()
+	if err != nil {
+		return "", nil, err
+	}
+
+	columns := make(map[string]int)
+	for i, h := range header {
+		columns[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+
+	id := -1
+	for _, field := range scopeIdentifierFields {
+		if i, found := columns[field]; found {
+			id = i
+			break
+		}
+	}
+	if id == -1 {
+		return "", nil, errors.New("the CSV header does not provide an asset identifier column")
+	}
+
+	var platform string
+	for _, h := range header {
+		if p, found := scopeFieldPlatforms[strings.ToLower(strings.TrimSpace(h))]; found {
+			platform = p
+			break
+		}
+	}
+
+	var assets []*scopeAsset
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return "", nil, err
+		}
+		if id >= len(record) {
+			continue
+		}
+
+		fields := make(map[string]interface{})
+		for name, i := range columns {
+			if i < len(record) {
+				fields[name] = record[i]
+			}
+		}
+		if a := newScopeAsset(record[id], fields, true); a != nil {
+			assets = append(assets, a)
+		}
+	}
+	return platform, assets, nil
+}
+
+// Reads the JSON export by walking all the objects, since the platforms nest the assets differently,
+// such as the HackerOne structured scopes, the Bugcrowd target groups and the Intigriti domains.
+func readScopeJSON(r io.Reader) (string, []*scopeAsset, error) {
+	var doc interface{}
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return "", nil, err
+	}
+
+	var platform string
+	var assets []*scopeAsset
+	var walk func(v interface{}, inScope bool)
+	walk = func(v interface{}, inScope bool) {
+		switch t := v.(type) {
+		case []interface{}:
+			for _, e := range t {
+				walk(e, inScope)
+			}
+		case map[string]interface{}:
+			if id, ok := scopeIdentifier(t); ok {
+				if a := newScopeAsset(id, t, inScope); a != nil {
+					assets = append(assets, a)
+				}
+				if platform == "" {
+					platform = scopePlatform(t)
+				}
+				return
+			}
+			// The target groups provide the scope of the assets within the group
+			if b, ok := t["in_scope"].(bool); ok {
+				inScope = b
+			}
+
+			for key, value := range t {
+				switch strings.ToLower(key) {
+				case "in_scope":
+					walk(value, true)
+				case "out_of_scope":
+					walk(value, false)
+				default:
+					walk(value, inScope)
+				}
+			}
+		}
+	}
+	walk(doc, true)
+	return platform, assets, nil
+}
+
+// Returns the asset identifier when the JSON object is a scope asset.
+func scopeIdentifier(obj map[string]interface{}) (string, bool) {
+	for _, field := range scopeIdentifierFields {
+		if id, ok := obj[field].(string); ok {
+			return id, true
+		}
+	}
+	// The Bugcrowd targets are named, and have a category or URI
+	if name, ok := obj["name"].(string); ok {
+		if uri, ok := obj["uri"].(string); ok && strings.TrimSpace(uri) != "" {
+			return uri, true
+		}
+		if _, found := obj["category"]; found {
+			return name, true
+		}
+		if _, found := obj["uri"]; found {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+func scopePlatform(obj map[string]interface{}) string {
+	for field, platform := range scopeFieldPlatforms {
+		if _, found := obj[field]; found {
+			return platform
+		}
+	}
+	return ""
+}
+
+// Returns the asset described by the fields of the CSV row or JSON object, or nil when the identifier is empty.
+func newScopeAsset(id string, fields map[string]interface{}, inScope bool) *scopeAsset {
+	if id = strings.TrimSpace(id); id == "" {
+		return nil
+	}
+
+	a := &scopeAsset{identifier: id, inScope: inScope}
+	for _, field := range scopeTypeFields {
+		if kind := scopeFieldString(fields[field]); kind != "" {
+			a.kind = kind
+			break
+		}
+	}
+	if eligible := scopeFieldString(fields["eligible_for_submission"]); eligible != "" {
+		if b, err := strconv.ParseBool(eligible); err == nil {
+			a.inScope = b
+		}
+	}
+	if b := scopeFieldString(fields["in_scope"]); b != "" {
+		if v, err := strconv.ParseBool(b); err == nil {
+			a.inScope = v
+		}
+	}
+	for _, field := range []string{"tier", "scope"} {
+		if v := strings.ToLower(scopeFieldString(fields[field])); strings.Contains(v, "out of scope") ||
+			strings.Contains(v, "out-of-scope") || v == "out_of_scope" {
+			a.inScope = false
+		}
+	}
+	return a
+}
+
+// Returns the string value of the field, including the values of the Intigriti type and tier objects.
+func scopeFieldString(v interface{}) string {
+	switch t := v.(type) {
+	case string:
+		return strings.TrimSpace(t)
+	case bool:
+		return strconv.FormatBool(t)
+	case map[string]interface{}:
+		if s, ok := t["value"].(string); ok {
+			return strings.TrimSpace(s)
+		}
+	}
+	return ""
+}
+
+func (s *ProgramScope) addAsset(a *scopeAsset) {
+	state := "in scope"
+	if !a.inScope {
+		state = "out of scope"
+	}
+	unmapped := fmt.Sprintf("%s (%s, %s)", a.identifier, strings.ToLower(a.kind), state)
+	if a.kind == "" {
+		unmapped = fmt.Sprintf("%s (%s)", a.identifier, state)
+	}
+
+	kind := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' {
+			return r
+		}
+		return -1
+	}, strings.ToLower(a.kind))
+	if _, found := scopeNetworkTypes[kind]; !found {
+		s.Unmapped = append(s.Unmapped, unmapped)
+		return
+	}
+
+	// Some programs list several assets in a single entry
+	for _, id := range strings.FieldsFunc(a.identifier, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\n' || r == '\t'
+	}) {
+		if !s.addIdentifier(id, a.inScope) {
+			s.Unmapped = append(s.Unmapped, unmapped)
+			return
+		}
+	}
+}
+
+// Adds the asset identifier to the scope, and returns false when it could not be mapped.
+func (s *ProgramScope) addIdentifier(id string, inScope bool) bool {
+	id = strings.ToLower(strings.TrimSpace(id))
+
+	if _, ipnet, err := net.ParseCIDR(id); err == nil {
+		if inScope {
+			s.CIDRs = append(s.CIDRs, ipnet)
+		} else {
+			s.ExcludedCIDRs = append(s.ExcludedCIDRs, ipnet)
+		}
+		return true
+	}
+
+	// Only part of the host is out of scope, so it must not be excluded
+	if !inScope && scopeHasPath(id) {
+		return false
+	}
+
+	host := scopeHost(id)
+	if ip := net.ParseIP(host); ip != nil {
+		if inScope {
+			s.Addresses = append(s.Addresses, ip)
+		} else {
+			bits := 8 * len(ip.To16())
+			if ip4 := ip.To4(); ip4 != nil {
+				ip, bits = ip4, 32
+			}
+			s.ExcludedCIDRs = append(s.ExcludedCIDRs, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+		}
+		return true
+	}
+
+	if strings.Contains(host, "*") {
+		if !inScope {
+			// The wildcards are matched as patterns by the blacklist
+			s.Blacklist = append(s.Blacklist, host)
+			return true
+		}
+
+		domain := strings.TrimPrefix(host, "*.")
+		if strings.Contains(domain, "*") || !scopeHostRE.MatchString(domain) {
+			return false
+		}
+		s.Domains = append(s.Domains, domain)
+		return true
+	}
+
+	if !scopeHostRE.MatchString(host) {
+		return false
+	}
+	if inScope {
+		s.Names = append(s.Names, host)
+	} else {
+		s.Blacklist = append(s.Blacklist, host)
+	}
+	return true
+}
+
+// Returns the host of the asset identifier, removing the URL scheme, user information, port and path.
+func scopeHost(id string) string {
+	if i := strings.Index(id, "://"); i >= 0 {
+		id = id[i+3:]
+	}
+	if i := strings.IndexAny(id, "/?#"); i >= 0 {
+		id = id[:i]
+	}
+	if i := strings.LastIndex(id, "@"); i >= 0 {
+		id = id[i+1:]
+	}
+	if strings.HasPrefix(id, "[") {
+		// An IPv6 address with a port
+		if i := strings.Index(id, "]"); i >= 0 {
+			return id[1:i]
+		}
+	}
+	if i := strings.LastIndex(id, ":"); i >= 0 && strings.Count(id, ":") == 1 {
+		if _, err := strconv.Atoi(id[i+1:]); err == nil || id[i+1:] == "*" {
+			id = id[:i]
+		}
+	}
+	return strings.Trim(id, ".")
+}
+
+// Returns true when the asset identifier is a URL that specifies a path, query or fragment.
+func scopeHasPath(id string) bool {
+	if i := strings.Index(id, "://"); i >= 0 {
+		id = id[i+3:]
+	}
+	if i := strings.IndexAny(id, "/?#"); i >= 0 {
+		return strings.Trim(id[i:], "/") != ""
+	}
+	return false
+}
+
+// ApplyScope adds the bug bounty program scope to the configuration. The exact hosts not covered by
+// a wildcard asset are added as hosts, so the names below them remain out of scope.
+func (c *Config) ApplyScope(s *ProgramScope) {
+	c.AddDomains(s.Domains...)
+	for _, name := range s.Names {
+		if c.WhichDomain(name) == "" {
+			c.AddHost(name)
+		}
+	}
+	c.ProvidedNames = stringset.Deduplicate(append(c.ProvidedNames, s.Names...))
+
+	c.Addresses = append(c.Addresses, s.Addresses...)
+	c.CIDRs = append(c.CIDRs, s.CIDRs...)
+	c.ExcludedCIDRs = append(c.ExcludedCIDRs, s.ExcludedCIDRs...)
+	for _, name := range s.Blacklist {
+		c.BlacklistSubdomain(name)
+	}
+}
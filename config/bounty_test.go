@@ -0,0 +1,183 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestReadScope(t *testing.T) {
+	tests := []struct {
+		name     string
+		export   string
+		platform string
+		domains  []string
+		names    []string
+		cidrs    []string
+		excluded []string
+		black    []string
+		unmapped []string
+		wantErr  bool
+	}{
+		{
+			name: "success - hackerone csv",
+			export: "identifier,asset_type,instruction,eligible_for_bounty,eligible_for_submission,max_severity\n" +
+				"*.example.com,WILDCARD,,true,true,critical\n" +
+				"https://api.example.net/v1,URL,,true,true,critical\n" +
+				"192.168.0.0/24,CIDR,,true,true,critical\n" +
+				"legacy.example.com,URL,,false,false,none\n" +
+				"github.com/example/app,SOURCE_CODE,,true,true,high\n",
+			platform: PlatformHackerOne,
+			domains:  []string{"example.com"},
+			names:    []string{"api.example.net"},
+			cidrs:    []string{"192.168.0.0/24"},
+			black:    []string{"legacy.example.com"},
+			unmapped: []string{"github.com/example/app (source_code, in scope)"},
+		},
+		{
+			name: "success - hackerone api json",
+			export: `{"relationships": {"structured_scopes": {"data": [
+				{"id": "1", "type": "structured-scope", "attributes": {"asset_identifier": "*.example.com", "asset_type": "WILDCARD", "eligible_for_submission": true}},
+				{"id": "2", "type": "structured-scope", "attributes": {"asset_identifier": "10.0.0.1", "asset_type": "IP_ADDRESS", "eligible_for_submission": false}},
+				{"id": "3", "type": "structured-scope", "attributes": {"asset_identifier": "com.example.app", "asset_type": "GOOGLE_PLAY_APP_ID", "eligible_for_submission": true}}
+			]}}}`,
+			platform: PlatformHackerOne,
+			domains:  []string{"example.com"},
+			excluded: []string{"10.0.0.1/32"},
+			unmapped: []string{"com.example.app (google_play_app_id, in scope)"},
+		},
+		{
+			name: "success - bugcrowd target groups",
+			export: `{"target_groups": [
+				{"name": "In Scope", "in_scope": true, "targets": [
+					{"name": "*.example.com", "uri": "", "category": "website"},
+					{"name": "Main API", "uri": "https://api.example.org", "category": "api"}
+				]},
+				{"name": "Out of Scope", "in_scope": false, "targets": [
+					{"name": "*.staging.example.com", "uri": "", "category": "website"},
+					{"name": "Example iOS", "uri": "", "category": "ios"}
+				]}
+			]}`,
+			platform: PlatformBugcrowd,
+			domains:  []string{"example.com"},
+			names:    []string{"api.example.org"},
+			black:    []string{"*.staging.example.com"},
+			unmapped: []string{"Example iOS (ios, out of scope)"},
+		},
+		{
+			name: "success - intigriti domains",
+			export: `{"domains": [
+				{"endpoint": "*.example.com", "type": {"id": 2, "value": "Wildcard"}, "tier": {"id": 1, "value": "Tier 1"}},
+				{"endpoint": "shop.example.io", "type": {"id": 1, "value": "Url"}, "tier": {"id": 5, "value": "Out Of Scope"}},
+				{"endpoint": "198.51.100.0/24", "type": {"id": 4, "value": "IpRange"}, "tier": {"id": 2, "value": "Tier 2"}}
+			]}`,
+			platform: PlatformIntigriti,
+			domains:  []string{"example.com"},
+			cidrs:    []string{"198.51.100.0/24"},
+			black:    []string{"shop.example.io"},
+		},
+		{
+			name: "success - out of scope url path",
+			export: "identifier,asset_type,eligible_for_submission\n" +
+				"*.example.com,WILDCARD,true\n" +
+				"https://example.com/careers,URL,false\n" +
+				"https://blog.example.com/,URL,false\n",
+			platform: PlatformHackerOne,
+			domains:  []string{"example.com"},
+			black:    []string{"blog.example.com"},
+			unmapped: []string{"https://example.com/careers (url, out of scope)"},
+		},
+		{
+			name:    "failure - missing identifier column",
+			export:  "name,description\nexample,test\n",
+			wantErr: true,
+		},
+		{
+			name:    "failure - no assets",
+			export:  `{"targets": []}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := ReadScope(strings.NewReader(tt.export))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ReadScope() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			var cidrs, excluded []string
+			for _, ipnet := range s.CIDRs {
+				cidrs = append(cidrs, ipnet.String())
+			}
+			for _, ipnet := range s.ExcludedCIDRs {
+				excluded = append(excluded, ipnet.String())
+			}
+
+			for _, c := range []struct {
+				field     string
+				got, want []string
+			}{
+				{field: "Domains", got: s.Domains, want: tt.domains},
+				{field: "Names", got: s.Names, want: tt.names},
+				{field: "CIDRs", got: cidrs, want: tt.cidrs},
+				{field: "ExcludedCIDRs", got: excluded, want: tt.excluded},
+				{field: "Blacklist", got: s.Blacklist, want: tt.black},
+				{field: "Unmapped", got: s.Unmapped, want: tt.unmapped},
+			} {
+				if len(c.got) != 0 || len(c.want) != 0 {
+					if !reflect.DeepEqual(c.got, c.want) {
+						t.Errorf("ReadScope() %s = %v, want %v", c.field, c.got, c.want)
+					}
+				}
+			}
+			if s.Platform != tt.platform {
+				t.Errorf("ReadScope() Platform = %s, want %s", s.Platform, tt.platform)
+			}
+		})
+	}
+}
+
+func TestConfigApplyScope(t *testing.T) {
+	s, err := ReadScope(strings.NewReader("identifier,asset_type,eligible_for_submission\n" +
+		"*.example.com,WILDCARD,true\n" +
+		"www.example.com,URL,true\n" +
+		"app.example.net,URL,true\n" +
+		"10.1.0.0/16,CIDR,true\n" +
+		"10.1.2.3,IP_ADDRESS,false\n" +
+		"*.corp.example.com,WILDCARD,false\n"))
+	if err != nil {
+		t.Fatalf("ReadScope() failed: %v", err)
+	}
+
+	c := NewConfig()
+	c.ApplyScope(s)
+
+	if domains := c.Domains(); !reflect.DeepEqual(domains, []string{"example.com"}) {
+		t.Errorf("ApplyScope() provided the domains %v", domains)
+	}
+	if hosts := c.Hosts(); !reflect.DeepEqual(hosts, []string{"app.example.net"}) {
+		t.Errorf("ApplyScope() provided the hosts %v", hosts)
+	}
+	sort.Strings(c.ProvidedNames)
+	if !reflect.DeepEqual(c.ProvidedNames, []string{"app.example.net", "www.example.com"}) {
+		t.Errorf("ApplyScope() provided the names %v", c.ProvidedNames)
+	}
+	if len(c.CIDRs) != 1 || c.CIDRs[0].String() != "10.1.0.0/16" {
+		t.Errorf("ApplyScope() provided the CIDRs %v", c.CIDRs)
+	}
+	if !c.IsAddressExcluded("10.1.2.3") || c.IsAddressInScope("10.1.2.3") || !c.IsAddressInScope("10.1.2.4") {
+		t.Errorf("ApplyScope() did not exclude the out of scope address")
+	}
+	if !c.Blacklisted("vpn.corp.example.com") || c.Blacklisted("www.example.com") {
+		t.Errorf("ApplyScope() did not blacklist the out of scope wildcard")
+	}
+}
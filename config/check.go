@@ -0,0 +1,217 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/go-ini/ini"
+)
+
+// The sections of the INI configuration file that have a fixed name.
+var iniSectionSchemas = map[string]*settingSchema{
+	"resolvers": {kind: kindMap, fields: map[string]*settingSchema{
+		"resolver": {kind: kindStringList},
+	}},
+	"scope.domains": {kind: kindMap, fields: map[string]*settingSchema{
+		"domain": {kind: kindStringList},
+	}},
+	"scope.hosts": {kind: kindMap, fields: map[string]*settingSchema{
+		"host": {kind: kindStringList},
+	}},
+	"scope.blacklisted": {kind: kindMap, fields: map[string]*settingSchema{
+		"subdomain": {kind: kindStringList},
+	}},
+	"scope.excluded": configSchema.fields["scope"].fields["excluded"],
+	"graphdbs":       {kind: kindMap},
+	"data_sources": {kind: kindMap, fields: map[string]*settingSchema{
+		"minimum_ttl": {kind: kindInt},
+	}},
+	"data_sources.enabled": {kind: kindMap, fields: map[string]*settingSchema{
+		"data_source": {kind: kindStringList},
+	}},
+	"data_sources.disabled": {kind: kindMap, fields: map[string]*settingSchema{
+		"data_source": {kind: kindStringList},
+	}},
+}
+
+// CheckFile validates the configuration file and returns a SettingsError reporting the unknown sections
+// and keys, and the values of the wrong type. The settings are also loaded, with the profile layered
+// over the base settings when provided, to report the problems found by the loaders, such as invalid
+// proxy URLs and missing wordlist files.
+func CheckFile(path, profile string) error {
+	if !isStructuredFile(path) {
+		cfg, err := loadSettingsFile(path)
+		if err != nil {
+			return err
+		}
+		if issues := validateINI(cfg); len(issues) > 0 {
+			return &SettingsError{Path: path, Issues: issues}
+		}
+	}
+
+	c := NewConfig()
+	c.Profile = profile
+	if err := c.LoadSettings(path); err != nil {
+		return err
+	}
+	return c.CheckSettings()
+}
+
+// Checks the sections and keys of the INI settings against the schema of the configuration file.
+func validateINI(cfg *ini.File) []string {
+	var issues []string
+
+	for _, sec := range cfg.Sections() {
+		name := sec.Name()
+		if name == ini.DefaultSection || name == strings.ToLower(ini.DefaultSection) {
+			name = ""
+		}
+
+		schema := iniSectionSchema(name)
+		if schema == nil {
+			msg := fmt.Sprintf("unknown section [%s]", name)
+			if suggestion := closestSettingKey(name, knownINISections()); suggestion != "" {
+				msg += fmt.Sprintf(" (did you mean [%s]?)", suggestion)
+			}
+			issues = append(issues, msg)
+			continue
+		}
+
+		for _, key := range sec.Keys() {
+			issues = append(issues, validateINIKey(name, key, schema)...)
+		}
+	}
+	return issues
+}
+
+func validateINIKey(section string, key *ini.Key, schema *settingSchema) []string {
+	name := key.Name()
+	if section != "" {
+		name = fmt.Sprintf("[%s] %s", section, key.Name())
+	}
+
+	k := strings.ToLower(key.Name())
+	if reason, found := schema.invalid[k]; found {
+		return []string{fmt.Sprintf("%s: %s", name, reason)}
+	}
+
+	s, found := schema.fields[k]
+	if !found {
+		s = schema.other
+	}
+	if s == nil || s.kind == kindMap {
+		msg := fmt.Sprintf("unknown key %s", name)
+		if suggestion := closestSettingKey(k, schema.fields); suggestion != "" {
+			msg += fmt.Sprintf(" (did you mean %s?)", suggestion)
+		}
+		return []string{msg}
+	}
+
+	if s.kind == kindBool {
+		// The INI file accepts the other boolean values recognized by the loaders, such as yes and no
+		if _, err := key.Bool(); err != nil {
+			return []string{fmt.Sprintf("%s: %q is not true or false", name, key.String())}
+		}
+		return nil
+	}
+
+	var issues []string
+	for _, v := range key.ValueWithShadows() {
+		if err := checkValue(s.kind, v); err != nil {
+			issues = append(issues, fmt.Sprintf("%s: %v", name, err))
+		}
+	}
+	return issues
+}
+
+// Returns the schema of the keys accepted by the INI section, or nil when the section is not recognized.
+func iniSectionSchema(name string) *settingSchema {
+	if name == "" {
+		// The default section only accepts the values, since the maps are provided by other sections
+		schema := &settingSchema{kind: kindMap, fields: make(map[string]*settingSchema)}
+		for k, f := range configSchema.fields {
+			if f.kind != kindMap && k != "resolvers" {
+				schema.fields[k] = f
+			}
+		}
+		return schema
+	}
+	if schema, found := iniSectionSchemas[name]; found {
+		return schema
+	}
+
+	parts := strings.Split(name, ".")
+	switch parts[0] {
+	case "scope", "bruteforce", "alterations":
+		if len(parts) == 1 {
+			schema := &settingSchema{kind: kindMap, fields: make(map[string]*settingSchema)}
+			for k, f := range configSchema.fields[parts[0]].fields {
+				// The scope domains, hosts, blacklist and exclusions are provided by child sections
+				if k != "domains" && k != "hosts" && k != "blacklisted" && k != "excluded" {
+					schema.fields[k] = f
+				}
+			}
+			return schema
+		}
+	case "graphdbs":
+		if len(parts) == 2 {
+			return databaseSchema
+		}
+	case "data_sources":
+		if len(parts) == 2 {
+			return &settingSchema{
+				kind:   kindMap,
+				fields: dataSourceSchema.fields,
+				other:  dataSourceSchema.other,
+				invalid: map[string]string{
+					"apikey":   "credentials must be provided in a credential set section, such as [" + name + ".Credentials]",
+					"secret":   "credentials must be provided in a credential set section, such as [" + name + ".Credentials]",
+					"username": "credentials must be provided in a credential set section, such as [" + name + ".Credentials]",
+					"password": "credentials must be provided in a credential set section, such as [" + name + ".Credentials]",
+				},
+			}
+		} else if len(parts) == 3 {
+			return credentialsSchema
+		}
+	case profilesSection:
+		if len(parts) == 2 {
+			return iniSectionSchema("")
+		} else if len(parts) > 2 && parts[2] != profilesSection {
+			return iniSectionSchema(strings.Join(parts[2:], "."))
+		}
+	}
+	return nil
+}
+
+// Returns the section names used for the suggestions when a section is not recognized.
+func knownINISections() map[string]*settingSchema {
+	sections := map[string]*settingSchema{
+		"scope":       nil,
+		"bruteforce":  nil,
+		"alterations": nil,
+	}
+	for name, schema := range iniSectionSchemas {
+		sections[name] = schema
+	}
+	return sections
+}
+
+// Returns an error when the value cannot be converted to the kind of setting.
+func checkValue(kind int, value string) error {
+	switch kind {
+	case kindInt, kindIntList:
+		if _, err := strconv.Atoi(strings.TrimSpace(value)); err != nil {
+			return fmt.Errorf("%q is not a number", value)
+		}
+	case kindBool:
+		if _, err := strconv.ParseBool(strings.TrimSpace(value)); err != nil {
+			return fmt.Errorf("%q is not true or false", value)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckFile(t *testing.T) {
+	for _, name := range []string{"config.ini", "config.yaml"} {
+		if err := CheckFile(filepath.Join("..", "examples", name), ""); err != nil {
+			t.Errorf("The example %s was reported as invalid: %v", name, err)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "config.ini")
+	content := `maximum_dns_querys = 500
+timeout = soon
+
+[bruteforce]
+enabled = maybe
+
+[scopes]
+
+[data_sources]
+
+[data_sources.Censys]
+apikey = key
+
+[profiles.fast]
+resolvers_qps = many
+`
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("Failed to write the configuration file: %v", err)
+	}
+
+	var serr *SettingsError
+	if err := CheckFile(path, ""); !errors.As(err, &serr) {
+		t.Fatalf("CheckFile did not return a SettingsError: %v", err)
+	}
+	for _, expected := range []string{
+		"did you mean maximum_dns_queries?",
+		`timeout: "soon" is not a number`,
+		`[bruteforce] enabled: "maybe" is not true or false`,
+		"did you mean [scope]?",
+		"[data_sources.censys] apikey: credentials must be provided in a credential set section",
+		`[profiles.fast] resolvers_qps: "many" is not a number`,
+	} {
+		if !strings.Contains(serr.Error(), expected) {
+			t.Errorf("The issue %q was not reported: %v", expected, serr)
+		}
+	}
+
+	if err := os.WriteFile(path, []byte("[data_sources]\n\n[profiles.fast]\ntimeout = 5\n"), 0600); err != nil {
+		t.Fatalf("Failed to write the configuration file: %v", err)
+	}
+	if err := CheckFile(path, "fast"); err != nil {
+		t.Errorf("The valid configuration file was reported as invalid: %v", err)
+	}
+	if err := CheckFile(path, "slow"); err == nil {
+		t.Errorf("The missing profile was not reported")
+	}
+}
+
+func TestTemplate(t *testing.T) {
+	var lines []string
+	// Uncomment all the sections and keys, except for the wordlist files that do not exist
+	for _, line := range strings.Split(Template(), "\n") {
+		if strings.HasPrefix(line, "#") && !strings.HasPrefix(line, "# ") && line != "#" {
+			line = strings.TrimPrefix(line, "#")
+		}
+		if !strings.HasPrefix(line, "wordlist_file") {
+			lines = append(lines, line)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "config.ini")
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0600); err != nil {
+		t.Fatalf("Failed to write the configuration file: %v", err)
+	}
+	if err := CheckFile(path, "NAME"); err != nil {
+		t.Errorf("The uncommented template was reported as invalid: %v", err)
+	}
+
+	for _, key := range []string{"#output_directory =", "#http_retries = 2", "#resolvers_qps =", "#ttl =", "#apikey =", "#url ="} {
+		if !strings.Contains(Template(), key) {
+			t.Errorf("The template is missing the %s setting", key)
+		}
+	}
+}
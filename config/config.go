@@ -23,17 +23,20 @@ import (
 	"sync"
 	"time"
 
+	amassnet "github.com/OWASP/Amass/v3/net"
 	"github.com/OWASP/Amass/v3/resources"
 	"github.com/caffix/stringset"
 	"github.com/go-ini/ini"
 	"github.com/google/uuid"
 )
 
+// The configuration file names searched for in the output and system directories.
+var defaultCfgFiles = []string{"config.ini", "config.yaml", "config.yml", "config.json"}
+
 const (
-	outputDirName  = "amass"
-	defaultCfgFile = "config.ini"
-	cfgEnvironVar  = "AMASS_CONFIG"
-	systemCfgDir   = "/etc"
+	outputDirName = "amass"
+	cfgEnvironVar = "AMASS_CONFIG"
+	systemCfgDir  = "/etc"
 )
 
 // Updater allows an object to implement a method that updates a configuration.
@@ -55,20 +58,45 @@ type Config struct {
 	Log *log.Logger
 
 	// The directory that stores the bolt db and other files created
-	Dir string `ini:"output_directory"`
+	Dir string `ini:"output_directory" comment:"The directory that stores the graph database and other output files"`
+
+	// The named profile of the configuration file layered over the base settings
+	Profile string `ini:"-"`
 
 	// Alternative directory for scripts provided by the user
-	ScriptsDirectory string `ini:"scripts_directory"`
+	ScriptsDirectory string `ini:"scripts_directory" comment:"Another directory where the user can provide ADS scripts to the engine"`
+
+	// Path to the IANA RDAP bootstrap registry file (dns.json) used for RDAP queries
+	RDAPBootstrap string `ini:"rdap_bootstrap" comment:"The local copy of the IANA RDAP bootstrap registry (dns.json) used for RDAP queries"`
+
+	// The proxy URL used for all outbound web traffic (http, https, socks5 or socks5h)
+	Proxy string `ini:"proxy" comment:"The HTTP or SOCKS5 proxy used for all outbound web traffic, e.g. socks5://127.0.0.1:9050"`
+
+	// The number of times failed web requests are retried, and the seconds before the first retry
+	HTTPRetries      int `ini:"http_retries" comment:"The number of times failed web requests are retried"`
+	HTTPRetryBackoff int `ini:"http_retry_backoff" comment:"The number of seconds before the first retry of a failed web request"`
+
+	// The maximum number of concurrent web requests sent to a single host
+	HTTPHostConcurrency int `ini:"http_host_concurrency" comment:"The maximum number of concurrent web requests sent to a single host"`
 
 	// The graph databases used by the system / enumerations
 	GraphDBs []*Database
 
 	// The maximum number of concurrent DNS queries
-	MaxDNSQueries int `ini:"maximum_dns_queries"`
+	MaxDNSQueries int `ini:"maximum_dns_queries" comment:"The maximum number of DNS queries that can be performed concurrently during the enumeration"`
 
 	// Names provided to seed the enumeration
 	ProvidedNames []string
 
+	// Files containing Passive DNS records in the Common Output Format
+	PassiveDNSFiles []string
+
+	// Files containing IPv6 addresses known to be in use, such as the public IPv6 hitlists
+	IPv6HitlistFiles []string
+
+	// Files containing the output of other reconnaissance tools, such as massdns, subfinder and nmap
+	ImportFiles []string
+
 	// The IP addresses specified as in scope
 	Addresses []net.IP
 
@@ -112,10 +140,21 @@ type Config struct {
 	// Determines if zone transfers will be attempted
 	Active bool
 
-	// A blacklist of subdomain names that will not be investigated
+	// A blacklist of subdomain names that will not be investigated, and the names
+	// containing the * wildcard, such as dev-*.example.com, are matched as patterns
 	Blacklist     []string
 	blacklistLock sync.Mutex
 
+	// Regular expressions matching the subdomain names that will not be investigated
+	BlacklistRegexps []string
+
+	// The compiled blacklist patterns and regular expressions
+	blacklistRes map[string]*regexp.Regexp
+
+	// The netblocks and ASNs that are out of scope, such as third-party hosting
+	ExcludedCIDRs []*net.IPNet
+	ExcludedASNs  []int
+
 	// A list of data sources that should not be utilized
 	SourceFilter struct {
 		Include bool // true = include, false = exclude
@@ -130,9 +169,12 @@ type Config struct {
 
 	// Resolver settings
 	Resolvers        []string
-	ResolversQPS     int
+	ResolversQPS     int `ini:"resolvers_qps" comment:"The maximum number of DNS queries per second for each untrusted resolver"`
 	TrustedResolvers []string
-	TrustedQPS       int
+	TrustedQPS       int `ini:"trusted_resolvers_qps" comment:"The maximum number of DNS queries per second for each trusted resolver"`
+
+	// The number of minutes the enumeration is allowed to run, or zero for no limit
+	Timeout int `ini:"timeout" comment:"The number of minutes to let the enumeration run before quitting, or zero for no limit"`
 
 	// Option for verbose logging and output
 	Verbose bool
@@ -143,6 +185,9 @@ type Config struct {
 	// The regular expressions for the root domains added to the enumeration
 	regexps map[string]*regexp.Regexp
 
+	// The exact host names in scope, without the names below them
+	hosts []string
+
 	// The data source configurations
 	datasrcConfigs map[string]*DataSourceConfig
 }
@@ -166,6 +211,10 @@ func NewConfig() *Config {
 		MinimumTTL:     1440,
 		ResolversQPS:   DefaultQueriesPerPublicResolver,
 		TrustedQPS:     DefaultQueriesPerBaselineResolver,
+		// The web request settings
+		HTTPRetries:         2,
+		HTTPRetryBackoff:    1,
+		HTTPHostConcurrency: 10,
 	}
 }
 
@@ -222,19 +271,47 @@ func (c *Config) CheckSettings() error {
 	return err
 }
 
-// LoadSettings parses settings from an .ini file and assigns them to the Config.
+// LoadSettings parses settings from an .ini, .yaml, .yml or .json file and assigns them to the Config.
+// The YAML and JSON files are validated, and a SettingsError reports unknown keys and type errors.
 func (c *Config) LoadSettings(path string) error {
+	cfg, err := loadSettingsFile(path)
+	if err != nil {
+		return err
+	}
+	if c.Profile != "" {
+		if err := applyProfile(cfg, c.Profile); err != nil {
+			return err
+		}
+	}
+	return c.applySettings(cfg)
+}
+
+func loadSettingsFile(path string) (*ini.File, error) {
+	if isStructuredFile(path) {
+		return loadStructuredFile(path)
+	}
+
 	cfg, err := ini.LoadSources(ini.LoadOptions{
 		Insensitive:  true,
 		AllowShadows: true,
 	}, path)
 	if err != nil {
-		return fmt.Errorf("failed to load the configuration file: %v", err)
+		return nil, fmt.Errorf("failed to load the configuration file: %v", err)
 	}
+	return cfg, nil
+}
+
+// Assigns the settings, in the layout of the INI configuration file, to the Config.
+func (c *Config) applySettings(cfg *ini.File) error {
 	// Get the easy ones out of the way using mapping
-	if err = cfg.MapTo(c); err != nil {
+	if err := cfg.MapTo(c); err != nil {
 		return fmt.Errorf("error mapping configuration settings to internal values: %v", err)
 	}
+	if c.Proxy != "" {
+		if _, err := amassnet.ParseProxy(c.Proxy); err != nil {
+			return fmt.Errorf("the proxy setting is invalid: %v", err)
+		}
+	}
 	// Attempt to load a special mode of operation specified by the user
 	if cfg.Section(ini.DefaultSection).HasKey("mode") {
 		mode := cfg.Section(ini.DefaultSection).Key("mode").String()
@@ -249,6 +326,7 @@ func (c *Config) LoadSettings(path string) error {
 	loads := []func(cfg *ini.File) error{
 		c.loadResolverSettings,
 		c.loadScopeSettings,
+		c.loadExclusionSettings,
 		c.loadAlterationSettings,
 		c.loadBruteForceSettings,
 		c.loadDatabaseSettings,
@@ -263,30 +341,98 @@ func (c *Config) LoadSettings(path string) error {
 	return nil
 }
 
-// AcquireConfig populates the Config struct provided by the Config argument.
+// AcquireConfig populates the Config struct provided by the Config argument. The profile selected by
+// the Config Profile field, or the AMASS_PROFILE environment variable, is layered over the settings
+// from the configuration file, and both are overridden by the AMASS_ environment variables. An error
+// is returned when neither a configuration file nor an environment setting was found.
 func AcquireConfig(dir, file string, cfg *Config) error {
-	var path, dircfg, syscfg string
+	path := LocateConfigFile(dir, file)
+
+	if cfg.Profile == "" {
+		cfg.Profile = strings.TrimSpace(os.Getenv(profileEnvironVar))
+	}
+
+	settings, found, err := layeredSettings(path, cfg.Profile, layerEnviron)
+	if err != nil {
+		return err
+	}
+	if path == "" && !found {
+		return errors.New("no configuration file or environment settings were found")
+	}
+	return cfg.applySettings(settings)
+}
+
+// The layers of settings, in order of precedence, loaded by AcquireConfig.
+const (
+	layerFile = iota
+	layerProfile
+	layerEnviron
+)
+
+// Loads the settings of the configuration file, and the layers over the file up to the last layer
+// requested. True is returned when the environment variables provided at least one setting.
+func layeredSettings(path, profile string, last int) (*ini.File, bool, error) {
+	settings, err := iniSettings(nil)
+	if err != nil {
+		return nil, false, err
+	}
+	if path != "" {
+		if settings, err = loadSettingsFile(path); err != nil {
+			return nil, false, err
+		}
+	}
+
+	if last >= layerProfile && profile != "" {
+		if err := applyProfile(settings, profile); err != nil {
+			return nil, false, err
+		}
+	}
+
+	var found bool
+	if last >= layerEnviron {
+		if found, err = environSettings(settings, os.Environ()); err != nil {
+			return nil, false, err
+		}
+	}
+	return settings, found, nil
+}
+
+// LocateConfigFile returns the path of the configuration file used by AcquireConfig. The file argument
+// takes precedence over the AMASS_CONFIG environment variable, followed by the configuration files
+// found in the output directory and the system configuration directory. An empty string is returned
+// when no configuration file was found.
+func LocateConfigFile(dir, file string) string {
+	var dircfg, syscfg string
 
 	d := OutputDirectory(dir)
 	if finfo, err := os.Stat(d); d != "" && !os.IsNotExist(err) && finfo.IsDir() {
-		dircfg = filepath.Join(d, defaultCfgFile)
+		dircfg = findConfigFile(d)
 	}
 
 	if runtime.GOOS != "windows" {
-		syscfg = filepath.Join(filepath.Join(systemCfgDir, outputDirName), defaultCfgFile)
+		syscfg = findConfigFile(filepath.Join(systemCfgDir, outputDirName))
 	}
 
 	if file != "" {
-		path = file
+		return file
 	} else if f, set := os.LookupEnv(cfgEnvironVar); set {
-		path = f
-	} else if _, err := os.Stat(dircfg); err == nil {
-		path = dircfg
-	} else if _, err := os.Stat(syscfg); err == nil {
-		path = syscfg
+		return f
+	} else if dircfg != "" {
+		return dircfg
 	}
+	return syscfg
+}
+
+// Returns the path of the first configuration file found in the directory.
+func findConfigFile(dir string) string {
+	for _, name := range defaultCfgFiles {
+		path := filepath.Join(dir, name)
 
-	return cfg.LoadSettings(path)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
 }
 
 // OutputDirectory returns the file path of the Amass output directory. A suitable
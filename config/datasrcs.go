@@ -6,26 +6,38 @@ package config
 
 import (
 	"fmt"
+	"sort"
 	"strings"
+	"sync"
 
+	amassnet "github.com/OWASP/Amass/v3/net"
 	"github.com/caffix/stringset"
 	"github.com/go-ini/ini"
 )
 
 // DataSourceConfig contains the configurations specific to a data source.
 type DataSourceConfig struct {
-	Name  string
-	TTL   int `ini:"ttl"`
+	Name string
+	TTL  int `ini:"ttl" comment:"The number of minutes that the responses of the data source are cached"`
+	// Path to the program implementing the data source as an out-of-process plugin
+	Plugin string `ini:"plugin" comment:"Path to an executable that implements the data source using the plugin protocol"`
+	// The proxy URL used for web and socket traffic generated by the data source
+	Proxy string `ini:"proxy" comment:"The proxy used for the traffic of this data source instead of the global proxy"`
+	lock  sync.Mutex
 	creds map[string]*Credentials
+	// The results of resolving the secret references of the credentials
+	resolved map[string]*resolvedCredentials
+	// The settings in the data source section that are specific to the data source
+	options map[string][]string
 }
 
 // Credentials contains values required for authenticating with web APIs.
 type Credentials struct {
 	Name     string
-	Username string `ini:"username"`
-	Password string `ini:"password"`
-	Key      string `ini:"apikey"`
-	Secret   string `ini:"secret"`
+	Username string `ini:"username" comment:"User for the data source account"`
+	Password string `ini:"password" comment:"Password for the user, or a secret reference such as exec:pass show amass/SOURCENAME"`
+	Key      string `ini:"apikey" comment:"The API key, or a secret reference such as file:/run/secrets/apikey"`
+	Secret   string `ini:"secret" comment:"An additional secret used with the API key, or a secret reference such as env:SOURCENAME_SECRET"`
 }
 
 // GetDataSourceConfig returns the DataSourceConfig associated with the data source name argument.
@@ -46,22 +58,117 @@ func (c *Config) GetDataSourceConfig(source string) *DataSourceConfig {
 	return c.datasrcConfigs[key]
 }
 
-// AddCredentials adds the Credentials provided to the configuration.
+// DataSourceProxy returns the proxy URL selected for traffic generated by the data source.
+// The data source proxy setting takes precedence over the global proxy setting.
+func (c *Config) DataSourceProxy(source string) string {
+	if dsc := c.GetDataSourceConfig(source); dsc != nil && dsc.Proxy != "" {
+		return dsc.Proxy
+	}
+	return c.Proxy
+}
+
+// DataSourceConfigs returns all the data source configurations, sorted by name.
+func (c *Config) DataSourceConfigs() []*DataSourceConfig {
+	c.Lock()
+	defer c.Unlock()
+
+	var dscs []*DataSourceConfig
+	for _, dsc := range c.datasrcConfigs {
+		dscs = append(dscs, dsc)
+	}
+
+	sort.Slice(dscs, func(i, j int) bool {
+		return dscs[i].Name < dscs[j].Name
+	})
+	return dscs
+}
+
+// SetOption assigns the values to the data source specific setting.
+func (dsc *DataSourceConfig) SetOption(name string, values ...string) {
+	if dsc.options == nil {
+		dsc.options = make(map[string][]string)
+	}
+
+	dsc.options[strings.ToLower(strings.TrimSpace(name))] = values
+}
+
+// Option returns the first value of the data source specific setting, or an empty string.
+func (dsc *DataSourceConfig) Option(name string) string {
+	if values := dsc.OptionValues(name); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// OptionValues returns all the values provided for the data source specific setting.
+func (dsc *DataSourceConfig) OptionValues(name string) []string {
+	return dsc.options[strings.ToLower(strings.TrimSpace(name))]
+}
+
+// AddCredentials adds the Credentials provided to the configuration. The credential values can be
+// secret references, such as file:/run/secrets/apikey, env:SHODAN_KEY or exec:pass show shodan,
+// which are resolved when the Credentials are selected.
 func (dsc *DataSourceConfig) AddCredentials(cred *Credentials) error {
 	if cred == nil || cred.Name == "" {
 		return fmt.Errorf("AddCredentials: The Credentials argument is invalid")
 	}
 
+	dsc.lock.Lock()
+	defer dsc.lock.Unlock()
+
 	if dsc.creds == nil {
 		dsc.creds = make(map[string]*Credentials)
 	}
 
 	dsc.creds[cred.Name] = cred
+	delete(dsc.resolved, cred.Name)
 	return nil
 }
 
 // GetCredentials returns randomly selected Credentials associated with the receiver configuration.
+// Nil is returned when the secret references of the selected Credentials cannot be resolved.
 func (dsc *DataSourceConfig) GetCredentials() *Credentials {
+	creds, _ := dsc.ResolveCredentials()
+	return creds
+}
+
+type resolvedCredentials struct {
+	once  sync.Once
+	creds *Credentials
+	err   error
+}
+
+// ResolveCredentials returns randomly selected Credentials associated with the receiver configuration,
+// after resolving the secret references. The results, including the failures, are kept for later
+// selections, so the secret commands are only run once for each set of Credentials.
+func (dsc *DataSourceConfig) ResolveCredentials() (*Credentials, error) {
+	creds := dsc.selectCredentials()
+	if creds == nil {
+		return nil, nil
+	}
+
+	dsc.lock.Lock()
+	if dsc.resolved == nil {
+		dsc.resolved = make(map[string]*resolvedCredentials)
+	}
+	r, found := dsc.resolved[creds.Name]
+	if !found {
+		r = new(resolvedCredentials)
+		dsc.resolved[creds.Name] = r
+	}
+	dsc.lock.Unlock()
+	// The secret commands can be slow, so the lock is not held while the references are resolved
+	r.once.Do(func() {
+		r.creds, r.err = creds.resolve()
+	})
+	return r.creds, r.err
+}
+
+// Returns randomly selected Credentials without resolving the secret references.
+func (dsc *DataSourceConfig) selectCredentials() *Credentials {
+	dsc.lock.Lock()
+	defer dsc.lock.Unlock()
+
 	if num := len(dsc.creds); num > 0 {
 		var creds []*Credentials
 		for _, c := range dsc.creds {
@@ -72,6 +179,37 @@ func (dsc *DataSourceConfig) GetCredentials() *Credentials {
 	return nil
 }
 
+// MissingCredentials returns the credential fields provided that do not have a value in the
+// receiver configuration. Recognized fields are username, password, key (or apikey) and secret.
+// The secret references are not resolved, so the secrets are not accessed to report the fields.
+func (dsc *DataSourceConfig) MissingCredentials(fields ...string) []string {
+	creds := dsc.selectCredentials()
+	if creds == nil {
+		creds = &Credentials{}
+	}
+
+	var missing []string
+	for _, field := range fields {
+		var value string
+
+		switch strings.ToLower(strings.TrimSpace(field)) {
+		case "username":
+			value = creds.Username
+		case "password":
+			value = creds.Password
+		case "key", "apikey":
+			value = creds.Key
+		case "secret":
+			value = creds.Secret
+		}
+
+		if value == "" {
+			missing = append(missing, field)
+		}
+	}
+	return missing
+}
+
 func (c *Config) loadDataSourceSettings(cfg *ini.File) error {
 	sec, err := cfg.GetSection("data_sources")
 	if err != nil {
@@ -85,7 +223,13 @@ func (c *Config) loadDataSourceSettings(cfg *ini.File) error {
 	}
 
 	for _, child := range sec.ChildSections() {
-		name := strings.Split(child.Name(), ".")[1]
+		parts := strings.Split(child.Name(), ".")
+		if len(parts) > 2 {
+			// The credential sets are loaded with the data source section
+			continue
+		}
+
+		name := parts[1]
 
 		if name == "disabled" {
 			// Load up all the disabled data source names
@@ -93,12 +237,26 @@ func (c *Config) loadDataSourceSettings(cfg *ini.File) error {
 			c.SourceFilter.Include = false
 			continue
 		}
+		if name == "enabled" {
+			// Only the enabled data sources will be used
+			c.SourceFilter.Sources = stringset.Deduplicate(child.Key("data_source").ValueWithShadows())
+			c.SourceFilter.Include = true
+			continue
+		}
 
 		dsc := c.GetDataSourceConfig(name)
 		// Parse the Database information and assign to the Config
 		if err := child.MapTo(dsc); err != nil {
 			continue
 		}
+		for _, key := range child.Keys() {
+			dsc.SetOption(key.Name(), key.ValueWithShadows()...)
+		}
+		if dsc.Proxy != "" {
+			if _, err := amassnet.ParseProxy(dsc.Proxy); err != nil {
+				return fmt.Errorf("the %s data source proxy setting is invalid: %v", name, err)
+			}
+		}
 
 		if c.MinimumTTL > dsc.TTL {
 			dsc.TTL = c.MinimumTTL
@@ -0,0 +1,235 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-ini/ini"
+)
+
+const (
+	// The prefix of the environment variables providing configuration settings
+	environPrefix = "AMASS_"
+	// The credential set name assigned to data source credentials from the environment
+	environCredentialSet = "environment"
+)
+
+// The location of an environment variable setting in the layout of the INI configuration file.
+type environSetting struct {
+	section string
+	key     string
+	kind    int
+	// The data source name is only set for credentials
+	source string
+}
+
+// Applies the AMASS_ environment variables to the settings, replacing the values loaded from the
+// configuration file. The variable names follow the configuration file layout, such as
+// AMASS_MAXIMUM_DNS_QUERIES, AMASS_SCOPE_PORT and AMASS_DATASOURCES_SHODAN_APIKEY. Lists are
+// separated by commas, and numeric suffixes, such as AMASS_DATASOURCES_SHODAN_APIKEY_2, provide
+// additional credential sets. The credentials from the environment replace the credentials
+// from the file for the data source. Unrecognized variables are ignored. True is returned
+// when at least one setting was applied.
+func environSettings(cfg *ini.File, environ []string) (bool, error) {
+	var found bool
+	replaced := make(map[string]struct{})
+
+	vars := append([]string(nil), environ...)
+	sort.Strings(vars)
+	for _, env := range vars {
+		name, value, ok := strings.Cut(env, "=")
+		if !ok || !strings.HasPrefix(name, environPrefix) || name == cfgEnvironVar || name == profileEnvironVar {
+			continue
+		}
+		if value = strings.TrimSpace(value); value == "" {
+			continue
+		}
+
+		setting, ok := parseEnvironName(strings.ToLower(strings.TrimPrefix(name, environPrefix)))
+		if !ok {
+			continue
+		}
+
+		values, err := environValues(setting.kind, value)
+		if err != nil {
+			return found, fmt.Errorf("the %s environment variable is invalid: %v", name, err)
+		}
+
+		if setting.section == "data_sources.enabled" || setting.section == "data_sources.disabled" {
+			// The include or exclude list replaces both lists of the configuration file
+			cfg.DeleteSection("data_sources.enabled")
+			cfg.DeleteSection("data_sources.disabled")
+		}
+		if setting.source != "" {
+			if _, done := replaced[setting.source]; !done {
+				replaced[setting.source] = struct{}{}
+				removeCredentials(cfg, "data_sources."+setting.source)
+			}
+		}
+		if err := setValues(cfg, setting.section, setting.key, values); err != nil {
+			return found, err
+		}
+		found = true
+	}
+	return found, nil
+}
+
+// Returns the location of the setting identified by the lowercase environment variable name without the prefix.
+func parseEnvironName(name string) (*environSetting, bool) {
+	field, rest, found := matchEnvironField(name, configSchema.fields)
+	if !found {
+		return nil, false
+	}
+
+	schema := configSchema.fields[field]
+	switch field {
+	case "scope":
+		sub, r, found := matchEnvironField(rest, schema.fields)
+		if found && sub == "excluded" {
+			key, r, found := matchEnvironField(r, schema.fields[sub].fields)
+			if !found || r != "" {
+				return nil, false
+			}
+			return &environSetting{section: "scope.excluded", key: key, kind: schema.fields[sub].fields[key].kind}, true
+		}
+		if !found || r != "" {
+			return nil, false
+		}
+
+		setting := &environSetting{section: "scope", key: sub, kind: schema.fields[sub].kind}
+		switch sub {
+		case "domains":
+			setting.section, setting.key = "scope.domains", "domain"
+		case "hosts":
+			setting.section, setting.key = "scope.hosts", "host"
+		case "blacklisted":
+			setting.section, setting.key = "scope.blacklisted", "subdomain"
+		}
+		return setting, true
+	case "bruteforce", "alterations":
+		sub, r, found := matchEnvironField(rest, schema.fields)
+		if !found || r != "" {
+			return nil, false
+		}
+		return &environSetting{section: field, key: sub, kind: schema.fields[sub].kind}, true
+	case "graphdbs":
+		db, r, found := strings.Cut(rest, "_")
+		if !found || db == "" {
+			return nil, false
+		}
+
+		key, r, found := matchEnvironField(r, databaseSchema.fields)
+		if !found || r != "" {
+			return nil, false
+		}
+		return &environSetting{section: "graphdbs." + db, key: key, kind: databaseSchema.fields[key].kind}, true
+	case "data_sources":
+		return parseEnvironDataSource(rest)
+	}
+
+	if rest != "" || schema.kind == kindMap {
+		return nil, false
+	}
+	if field == "resolvers" {
+		return &environSetting{section: "resolvers", key: "resolver", kind: schema.kind}, true
+	}
+	return &environSetting{section: ini.DefaultSection, key: field, kind: schema.kind}, true
+}
+
+func parseEnvironDataSource(name string) (*environSetting, bool) {
+	schema := configSchema.fields["data_sources"]
+
+	if field, rest, found := matchEnvironField(name, schema.fields); found && rest == "" {
+		if field == "enabled" || field == "disabled" {
+			return &environSetting{section: "data_sources." + field, key: "data_source", kind: kindStringList}, true
+		}
+		return &environSetting{section: "data_sources", key: field, kind: schema.fields[field].kind}, true
+	}
+
+	source, rest, found := strings.Cut(name, "_")
+	if !found || source == "" || rest == "" {
+		return nil, false
+	}
+
+	section := "data_sources." + source
+	if key, suffix, found := matchEnvironField(rest, credentialsSchema.fields); found {
+		set := environCredentialSet
+		if suffix != "" {
+			if n, err := strconv.Atoi(suffix); err != nil || n < 1 {
+				return nil, false
+			}
+			set += suffix
+		}
+		return &environSetting{section: section + "." + set, key: key, kind: kindString, source: source}, true
+	}
+	if key, r, found := matchEnvironField(rest, dataSourceSchema.fields); found && r == "" && key != "credentials" {
+		return &environSetting{section: section, key: key, kind: dataSourceSchema.fields[key].kind}, true
+	}
+	// Any other name is a data source option, such as the LocalFiles path
+	return &environSetting{section: section, key: rest, kind: kindOption}, true
+}
+
+// Returns the schema field starting the name, along with the remainder of the name. The fields can be
+// written with or without the underscores, such as DATA_SOURCES and DATASOURCES. The longest field wins.
+func matchEnvironField(name string, fields map[string]*settingSchema) (string, string, bool) {
+	var field, rest string
+
+	for f := range fields {
+		if len(f) <= len(field) {
+			continue
+		}
+
+		for _, form := range []string{f, strings.ReplaceAll(f, "_", "")} {
+			if name == form {
+				field, rest = f, ""
+				break
+			}
+			if strings.HasPrefix(name, form+"_") {
+				field, rest = f, name[len(form)+1:]
+				break
+			}
+		}
+	}
+	return field, rest, field != ""
+}
+
+// Returns the values of the environment variable after checking them against the setting type.
+func environValues(kind int, value string) ([]string, error) {
+	var values []string
+
+	switch kind {
+	case kindStringList, kindIntList, kindOption:
+		for _, v := range strings.Split(value, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
+			}
+		}
+	default:
+		values = []string{value}
+	}
+
+	for _, v := range values {
+		if err := checkValue(kind, v); err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+// Removes the credential sets provided by the configuration file for the data source.
+func removeCredentials(cfg *ini.File, section string) {
+	sec, err := cfg.GetSection(section)
+	if err != nil {
+		return
+	}
+
+	for _, child := range sec.ChildSections() {
+		cfg.DeleteSection(child.Name())
+	}
+}
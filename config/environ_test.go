@@ -0,0 +1,110 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAcquireConfigEnvironment(t *testing.T) {
+	iniCfg := `maximum_dns_queries = 500
+
+[scope]
+port = 8080
+
+[data_sources]
+minimum_ttl = 60
+
+[data_sources.Shodan]
+ttl = 120
+
+[data_sources.Shodan.Credentials]
+apikey = fromfile
+
+[data_sources.Censys]
+
+[data_sources.Censys.Credentials]
+apikey = censysid
+secret = censyssecret
+`
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.ini")
+	if err := os.WriteFile(path, []byte(iniCfg), 0600); err != nil {
+		t.Fatalf("Failed to write the configuration file: %v", err)
+	}
+
+	t.Setenv("AMASS_MAXIMUM_DNS_QUERIES", "1000")
+	t.Setenv("AMASS_SCOPE_DOMAINS", "owasp.org, example.com")
+	t.Setenv("AMASS_BRUTEFORCE_ENABLED", "true")
+	t.Setenv("AMASS_DATASOURCES_SHODAN_APIKEY", "fromenv")
+	t.Setenv("AMASS_DATASOURCES_SHODAN_APIKEY_2", "second")
+	t.Setenv("AMASS_DATA_SOURCES_LOCALFILES_PATH", "/src/a,/src/b")
+	t.Setenv("AMASS_GRAPHDBS_POSTGRES_URL", "postgres://localhost/amass")
+	t.Setenv("AMASS_UNKNOWN_SETTING", "ignored")
+
+	c := NewConfig()
+	if err := AcquireConfig("", path, c); err != nil {
+		t.Fatalf("AcquireConfig failed: %v", err)
+	}
+
+	if c.MaxDNSQueries != 1000 {
+		t.Errorf("The environment did not override the maximum DNS queries: %d", c.MaxDNSQueries)
+	}
+	if len(c.Ports) != 3 || c.Ports[2] != 8080 {
+		t.Errorf("The scope ports from the file were not kept: %v", c.Ports)
+	}
+	if domains := c.Domains(); len(domains) != 2 {
+		t.Errorf("The scope domains were not loaded from the environment: %v", domains)
+	}
+	if !c.BruteForcing {
+		t.Error("The brute forcing setting was not loaded from the environment")
+	}
+	if len(c.GraphDBs) != 1 || c.GraphDBs[0].URL != "postgres://localhost/amass" {
+		t.Errorf("The graph database was not loaded from the environment: %v", c.GraphDBs)
+	}
+
+	shodan := c.GetDataSourceConfig("Shodan")
+	if shodan.TTL != 120 {
+		t.Errorf("The Shodan TTL from the file was not kept: %d", shodan.TTL)
+	}
+	keys := make(map[string]bool)
+	for i := 0; i < 50; i++ {
+		keys[shodan.GetCredentials().Key] = true
+	}
+	if keys["fromfile"] || !keys["fromenv"] || !keys["second"] {
+		t.Errorf("The Shodan credentials from the environment did not replace the file credentials: %v", keys)
+	}
+	if creds := c.GetDataSourceConfig("Censys").GetCredentials(); creds == nil || creds.Secret != "censyssecret" {
+		t.Error("The Censys credentials from the file were not kept")
+	}
+	if paths := c.GetDataSourceConfig("LocalFiles").OptionValues("path"); len(paths) != 2 {
+		t.Errorf("The LocalFiles paths were not loaded from the environment: %v", paths)
+	}
+}
+
+func TestAcquireConfigEnvironmentOnly(t *testing.T) {
+	t.Setenv(cfgEnvironVar, "")
+	t.Setenv("AMASS_DATASOURCES_SHODAN_APIKEY", "fromenv")
+
+	c := NewConfig()
+	if err := AcquireConfig(t.TempDir(), "", c); err != nil {
+		t.Fatalf("AcquireConfig failed without a configuration file: %v", err)
+	}
+	if creds := c.GetDataSourceConfig("Shodan").GetCredentials(); creds == nil || creds.Key != "fromenv" {
+		t.Error("The Shodan credentials were not loaded from the environment")
+	}
+}
+
+func TestAcquireConfigEnvironmentErrors(t *testing.T) {
+	t.Setenv("AMASS_MAXIMUM_DNS_QUERIES", "many")
+
+	err := AcquireConfig(t.TempDir(), "", NewConfig())
+	if err == nil || !strings.Contains(err.Error(), "AMASS_MAXIMUM_DNS_QUERIES") {
+		t.Errorf("The invalid environment variable was not reported: %v", err)
+	}
+}
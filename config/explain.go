@@ -0,0 +1,282 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// The origins reported for the effective settings.
+const (
+	OriginDefault     = "default"
+	OriginFile        = "file"
+	OriginProfile     = "profile"
+	OriginEnvironment = "environment"
+	OriginFlag        = "flag"
+)
+
+// The value shown in place of a secret
+const maskedValue = "********"
+
+// Setting is an effective configuration setting, named using the layout of the configuration
+// file, along with the origin of the value, such as the file, a profile or a command-line flag.
+type Setting struct {
+	Key    string
+	Value  string
+	Origin string
+}
+
+// ExplainConfig populates the Config like AcquireConfig, with the Updater applied last when provided,
+// and returns the effective settings. The origin of each setting is the last layer that changed the
+// value. The secrets are masked, and the secret references are shown without being resolved.
+func ExplainConfig(dir, file string, cfg *Config, update Updater) ([]*Setting, error) {
+	path := LocateConfigFile(dir, file)
+
+	if cfg.Profile == "" {
+		cfg.Profile = strings.TrimSpace(os.Getenv(profileEnvironVar))
+	}
+
+	layers := []struct {
+		origin string
+		last   int
+	}{
+		{origin: OriginFile + " " + path, last: layerFile},
+		{origin: OriginProfile + " " + cfg.Profile, last: layerProfile},
+		{origin: OriginEnvironment, last: layerEnviron},
+	}
+
+	prev := NewConfig().settings()
+	origins := make(map[string]string)
+	for _, layer := range layers {
+		if (layer.last == layerFile && path == "") || (layer.last == layerProfile && cfg.Profile == "") {
+			continue
+		}
+
+		settings, _, err := layeredSettings(path, cfg.Profile, layer.last)
+		if err != nil {
+			return nil, err
+		}
+
+		c := NewConfig()
+		if layer.last == layerEnviron {
+			// The Config provided by the caller receives all the layers
+			c = cfg
+		}
+		if err := c.applySettings(settings); err != nil {
+			return nil, err
+		}
+
+		next := c.settings()
+		updateOrigins(origins, prev, next, layer.origin)
+		prev = next
+	}
+
+	if update != nil {
+		if err := cfg.UpdateConfig(update); err != nil {
+			return nil, err
+		}
+		updateOrigins(origins, prev, cfg.settings(), OriginFlag)
+	}
+
+	var results []*Setting
+	for _, s := range cfg.settingList() {
+		s.Origin = OriginDefault
+		if origin, found := origins[s.Key]; found {
+			s.Origin = origin
+		}
+		results = append(results, s)
+	}
+	return results, nil
+}
+
+// Assigns the origin to the settings that have a different value in the next layer.
+func updateOrigins(origins, prev, next map[string]string, origin string) {
+	for key, value := range next {
+		if v, found := prev[key]; !found || v != value {
+			origins[key] = origin
+		}
+	}
+	for key := range prev {
+		if _, found := next[key]; !found {
+			origins[key] = origin
+		}
+	}
+}
+
+func (c *Config) settings() map[string]string {
+	m := make(map[string]string)
+
+	for _, s := range c.settingList() {
+		m[s.Key] = s.Value
+	}
+	return m
+}
+
+// Returns the settings of the Config in the layout of the configuration file. The empty values are
+// omitted, and the secrets are masked.
+func (c *Config) settingList() []*Setting {
+	var list []*Setting
+	add := func(key, value string) {
+		if value != "" {
+			list = append(list, &Setting{Key: key, Value: value})
+		}
+	}
+	addList := func(key string, values []string) {
+		add(key, strings.Join(values, ", "))
+	}
+
+	mode := "normal"
+	if c.Passive {
+		mode = "passive"
+	} else if c.Active {
+		mode = "active"
+	}
+	add("mode", mode)
+	add("output_directory", c.Dir)
+	add("scripts_directory", c.ScriptsDirectory)
+	add("rdap_bootstrap", c.RDAPBootstrap)
+	add("proxy", redactURL(c.Proxy))
+	add("http_retries", strconv.Itoa(c.HTTPRetries))
+	add("http_retry_backoff", strconv.Itoa(c.HTTPRetryBackoff))
+	add("http_host_concurrency", strconv.Itoa(c.HTTPHostConcurrency))
+	add("maximum_dns_queries", strconv.Itoa(c.MaxDNSQueries))
+	add("resolvers_qps", strconv.Itoa(c.ResolversQPS))
+	add("trusted_resolvers_qps", strconv.Itoa(c.TrustedQPS))
+	add("timeout", strconv.Itoa(c.Timeout))
+	addList("resolvers", c.Resolvers)
+	addList("trusted_resolvers", c.TrustedResolvers)
+
+	var addrs, cidrs, asns, ports []string
+	for _, addr := range c.Addresses {
+		addrs = append(addrs, addr.String())
+	}
+	for _, cidr := range c.CIDRs {
+		cidrs = append(cidrs, cidr.String())
+	}
+	for _, asn := range c.ASNs {
+		asns = append(asns, strconv.Itoa(asn))
+	}
+	for _, port := range c.Ports {
+		ports = append(ports, strconv.Itoa(port))
+	}
+	addList("scope.address", addrs)
+	addList("scope.cidr", cidrs)
+	addList("scope.asn", asns)
+	addList("scope.port", ports)
+	addList("scope.domains", c.Domains())
+	addList("scope.hosts", c.Hosts())
+	addList("scope.blacklisted", c.Blacklist)
+
+	var excidrs, exasns []string
+	for _, cidr := range c.ExcludedCIDRs {
+		excidrs = append(excidrs, cidr.String())
+	}
+	for _, asn := range c.ExcludedASNs {
+		exasns = append(exasns, strconv.Itoa(asn))
+	}
+	addList("scope.excluded.regex", c.BlacklistRegexps)
+	addList("scope.excluded.cidr", excidrs)
+	addList("scope.excluded.asn", exasns)
+
+	for _, db := range c.GraphDBs {
+		prefix := "graphdbs." + db.System + "."
+
+		add(prefix+"primary", strconv.FormatBool(db.Primary))
+		add(prefix+"url", redactURL(db.URL))
+		add(prefix+"username", db.Username)
+		add(prefix+"password", maskSecret(db.Password))
+		add(prefix+"database", db.DBName)
+		add(prefix+"options", db.Options)
+	}
+
+	add("bruteforce.enabled", strconv.FormatBool(c.BruteForcing))
+	add("bruteforce.recursive", strconv.FormatBool(c.Recursive))
+	add("bruteforce.minimum_for_recursive", strconv.Itoa(c.MinForRecursive))
+	add("bruteforce.max_depth", strconv.Itoa(c.MaxDepth))
+	if len(c.Wordlist) > 0 {
+		add("bruteforce.wordlist", fmt.Sprintf("%d words", len(c.Wordlist)))
+	}
+
+	add("alterations.enabled", strconv.FormatBool(c.Alterations))
+	add("alterations.flip_words", strconv.FormatBool(c.FlipWords))
+	add("alterations.flip_numbers", strconv.FormatBool(c.FlipNumbers))
+	add("alterations.add_words", strconv.FormatBool(c.AddWords))
+	add("alterations.add_numbers", strconv.FormatBool(c.AddNumbers))
+	add("alterations.minimum_for_word_flip", strconv.Itoa(c.MinForWordFlip))
+	add("alterations.edit_distance", strconv.Itoa(c.EditDistance))
+	if len(c.AltWordlist) > 0 {
+		add("alterations.wordlist", fmt.Sprintf("%d words", len(c.AltWordlist)))
+	}
+
+	add("data_sources.minimum_ttl", strconv.Itoa(c.MinimumTTL))
+	if c.SourceFilter.Include {
+		addList("data_sources.enabled", c.SourceFilter.Sources)
+	} else {
+		addList("data_sources.disabled", c.SourceFilter.Sources)
+	}
+
+	for _, dsc := range c.DataSourceConfigs() {
+		prefix := "data_sources." + dsc.Name + "."
+
+		if dsc.TTL != 0 {
+			add(prefix+"ttl", strconv.Itoa(dsc.TTL))
+		}
+		add(prefix+"plugin", dsc.Plugin)
+		add(prefix+"proxy", redactURL(dsc.Proxy))
+
+		var options []string
+		for name := range dsc.options {
+			options = append(options, name)
+		}
+		sort.Strings(options)
+		for _, name := range options {
+			switch name {
+			case "ttl", "plugin", "proxy":
+				continue
+			}
+			addList(prefix+name, dsc.options[name])
+		}
+
+		dsc.lock.Lock()
+		var sets []string
+		for name := range dsc.creds {
+			sets = append(sets, name)
+		}
+		sort.Strings(sets)
+		for _, name := range sets {
+			cr := dsc.creds[name]
+			cprefix := prefix + name + "."
+
+			add(cprefix+"username", cr.Username)
+			add(cprefix+"password", maskSecret(cr.Password))
+			add(cprefix+"apikey", maskSecret(cr.Key))
+			add(cprefix+"secret", maskSecret(cr.Secret))
+		}
+		dsc.lock.Unlock()
+	}
+	return list
+}
+
+// Returns the secret reference, since it does not reveal the secret, or the masked value.
+func maskSecret(value string) string {
+	if value == "" || IsSecretReference(value) {
+		return value
+	}
+	return maskedValue
+}
+
+// Returns the URL with the password of the user information masked.
+func redactURL(value string) string {
+	u, err := url.Parse(value)
+	if err != nil || u.User == nil {
+		return value
+	}
+	return u.Redacted()
+}
@@ -13,12 +13,12 @@ import (
 // Database contains values required for connecting with graph databases.
 type Database struct {
 	System   string
-	Primary  bool   `ini:"primary"`
-	URL      string `ini:"url"`
-	Username string `ini:"username"`
-	Password string `ini:"password"`
-	DBName   string `ini:"database"`
-	Options  string `ini:"options"`
+	Primary  bool   `ini:"primary" comment:"Use this graph database instead of the local database for the enumerations"`
+	URL      string `ini:"url" comment:"The URL of the graph database, e.g. postgres://127.0.0.1:5432"`
+	Username string `ini:"username" comment:"User for the graph database"`
+	Password string `ini:"password" comment:"Password for the graph database user"`
+	DBName   string `ini:"database" comment:"The name of the database"`
+	Options  string `ini:"options" comment:"Additional connection options, e.g. sslmode=disable"`
 }
 
 func (c *Config) loadDatabaseSettings(cfg *ini.File) error {
@@ -0,0 +1,78 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-ini/ini"
+)
+
+const (
+	// The parent section of the named profiles in the configuration file
+	profilesSection = "profiles"
+	// The environment variable selecting the profile when the -profile flag is not provided
+	profileEnvironVar = "AMASS_PROFILE"
+)
+
+// Layers the named profile over the base settings. A profile is a section named profiles.NAME
+// providing default section keys, and the child sections, such as profiles.NAME.bruteforce,
+// providing the keys of the base sections. The profile keys replace the base keys, and a data
+// source include or exclude list in the profile replaces both lists of the base settings.
+func applyProfile(cfg *ini.File, name string) error {
+	prefix := profilesSection + "." + strings.ToLower(strings.TrimSpace(name))
+
+	var sections []*ini.Section
+	for _, sec := range cfg.Sections() {
+		if sec.Name() == prefix || strings.HasPrefix(sec.Name(), prefix+".") {
+			sections = append(sections, sec)
+		}
+	}
+	if len(sections) == 0 {
+		return fmt.Errorf("the %s profile was not found in the configuration (available profiles: %s)",
+			name, strings.Join(profileNames(cfg), ", "))
+	}
+
+	for _, sec := range sections {
+		if n := sec.Name(); n == prefix+".data_sources.enabled" || n == prefix+".data_sources.disabled" {
+			cfg.DeleteSection("data_sources.enabled")
+			cfg.DeleteSection("data_sources.disabled")
+			break
+		}
+	}
+
+	for _, sec := range sections {
+		target := strings.TrimPrefix(strings.TrimPrefix(sec.Name(), prefix), ".")
+		if target == "" {
+			target = ini.DefaultSection
+		}
+
+		for _, key := range sec.Keys() {
+			if err := setValues(cfg, target, key.Name(), key.ValueWithShadows()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Returns the names of the profiles provided by the settings.
+func profileNames(cfg *ini.File) []string {
+	var names []string
+	seen := make(map[string]struct{})
+
+	for _, sec := range cfg.Sections() {
+		parts := strings.Split(sec.Name(), ".")
+		if len(parts) < 2 || parts[0] != profilesSection {
+			continue
+		}
+		if _, found := seen[parts[1]]; !found {
+			seen[parts[1]] = struct{}{}
+			names = append(names, parts[1])
+		}
+	}
+	return names
+}
@@ -0,0 +1,139 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestProfiles(t *testing.T) {
+	iniCfg := `maximum_dns_queries = 500
+resolvers_qps = 10
+
+[resolvers]
+resolver = 8.8.8.8
+resolver = 1.1.1.1
+
+[bruteforce]
+enabled = true
+
+[data_sources]
+minimum_ttl = 60
+
+[data_sources.disabled]
+data_source = Ask
+
+[profiles.stealth]
+mode = passive
+maximum_dns_queries = 50
+timeout = 30
+
+[profiles.stealth.resolvers]
+resolver = 9.9.9.9
+
+[profiles.stealth.bruteforce]
+enabled = false
+
+[profiles.stealth.data_sources.enabled]
+data_source = crtsh
+data_source = AlienVault
+
+[profiles.internal.resolvers]
+resolver = 10.0.0.53
+`
+	yamlCfg := `maximum_dns_queries: 500
+resolvers_qps: 10
+resolvers: [8.8.8.8, 1.1.1.1]
+bruteforce:
+  enabled: true
+data_sources:
+  minimum_ttl: 60
+  disabled: [Ask]
+profiles:
+  stealth:
+    mode: passive
+    maximum_dns_queries: 50
+    timeout: 30
+    resolvers: [9.9.9.9]
+    bruteforce:
+      enabled: false
+    data_sources:
+      enabled: [crtsh, AlienVault]
+  internal:
+    resolvers: [10.0.0.53]
+`
+
+	dir := t.TempDir()
+	for name, content := range map[string]string{"config.ini": iniCfg, "config.yaml": yamlCfg} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+
+		c := NewConfig()
+		if err := c.LoadSettings(path); err != nil {
+			t.Fatalf("%s: failed to load the base settings: %v", name, err)
+		}
+		if c.Passive || c.MaxDNSQueries != 500 || len(c.Resolvers) != 2 || !c.BruteForcing || c.SourceFilter.Include {
+			t.Errorf("%s: the profile settings were applied without selecting the profile", name)
+		}
+
+		c = NewConfig()
+		c.Profile = "Stealth"
+		if err := c.LoadSettings(path); err != nil {
+			t.Fatalf("%s: failed to load the stealth profile: %v", name, err)
+		}
+		if !c.Passive || c.MaxDNSQueries != 50 || c.Timeout != 30 || c.BruteForcing {
+			t.Errorf("%s: the stealth profile settings were not applied", name)
+		}
+		if c.ResolversQPS != 10 || c.MinimumTTL != 60 {
+			t.Errorf("%s: the base settings were not kept by the stealth profile", name)
+		}
+		if len(c.Resolvers) != 1 || c.Resolvers[0] != "9.9.9.9" {
+			t.Errorf("%s: the stealth profile did not replace the resolvers: %v", name, c.Resolvers)
+		}
+		if !c.SourceFilter.Include || len(c.SourceFilter.Sources) != 2 {
+			t.Errorf("%s: the stealth profile did not replace the data source list: %v", name, c.SourceFilter)
+		}
+
+		c = NewConfig()
+		c.Profile = "internal"
+		if err := c.LoadSettings(path); err != nil {
+			t.Fatalf("%s: failed to load the internal profile: %v", name, err)
+		}
+		if len(c.Resolvers) != 1 || c.Resolvers[0] != "10.0.0.53" || !c.BruteForcing || c.SourceFilter.Include {
+			t.Errorf("%s: the internal profile was not layered over the base settings", name)
+		}
+
+		c = NewConfig()
+		c.Profile = "missing"
+		if err := c.LoadSettings(path); err == nil || !strings.Contains(err.Error(), "stealth") {
+			t.Errorf("%s: the missing profile was not reported with the available profiles: %v", name, err)
+		}
+	}
+}
+
+func TestAcquireConfigProfile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.ini")
+	if err := os.WriteFile(path, []byte("[data_sources]\n\n[profiles.stealth]\nmaximum_dns_queries = 50\ntimeout = 30\n"), 0600); err != nil {
+		t.Fatalf("Failed to write the configuration file: %v", err)
+	}
+	t.Setenv(profileEnvironVar, "stealth")
+	t.Setenv("AMASS_TIMEOUT", "60")
+
+	c := NewConfig()
+	if err := AcquireConfig("", path, c); err != nil {
+		t.Fatalf("AcquireConfig failed: %v", err)
+	}
+	if c.Profile != "stealth" || c.MaxDNSQueries != 50 {
+		t.Errorf("The profile selected by the environment was not applied")
+	}
+	if c.Timeout != 60 {
+		t.Errorf("The environment did not override the profile timeout: %d", c.Timeout)
+	}
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/OWASP/Amass/v3/net/dns"
 	"github.com/caffix/stringset"
 	"github.com/go-ini/ini"
+	"golang.org/x/net/publicsuffix"
 )
 
 // DomainRegex returns the Regexp object for the domain name identified by the parameter.
@@ -36,12 +37,14 @@ func (c *Config) AddDomains(domains ...string) {
 }
 
 // AddDomain appends the domain name provided in the parameter to the list in the configuration.
+// The domain can be a sub-zone, such as shop.example.com, and the wildcard notation used by bug
+// bounty programs, such as *.shop.example.com, is accepted.
 func (c *Config) AddDomain(domain string) {
 	c.Lock()
 	defer c.Unlock()
 
 	// Check that the domain string is not empty
-	d := strings.TrimSpace(domain)
+	d := strings.TrimPrefix(strings.TrimSpace(domain), "*.")
 	if d == "" {
 		return
 	}
@@ -81,7 +84,72 @@ func (c *Config) Domains() []string {
 	return c.domains
 }
 
-// IsDomainInScope returns true if the DNS name in the parameter ends with a domain in the config list.
+// AddHosts appends the host names provided in the parameter to the exact hosts in the configuration.
+func (c *Config) AddHosts(hosts ...string) {
+	for _, h := range hosts {
+		c.AddHost(h)
+	}
+}
+
+// AddHost appends the host name provided in the parameter to the exact hosts in the configuration.
+// Only the host itself is in scope, and the names below the host are not investigated.
+func (c *Config) AddHost(host string) {
+	c.Lock()
+	defer c.Unlock()
+
+	h := strings.Trim(strings.ToLower(strings.TrimSpace(host)), ".")
+	if h == "" || !strings.Contains(h, ".") {
+		return
+	}
+
+	c.hosts = stringset.Deduplicate(append(c.hosts, h))
+}
+
+// Hosts returns the list of exact host names currently in the configuration.
+func (c *Config) Hosts() []string {
+	c.Lock()
+	defer c.Unlock()
+
+	return c.hosts
+}
+
+// IsExactHost returns true if the DNS name in the parameter is an exact host in the config list.
+func (c *Config) IsExactHost(name string) bool {
+	n := strings.ToLower(strings.TrimSpace(name))
+
+	for _, h := range c.Hosts() {
+		if n == h {
+			return true
+		}
+	}
+	return false
+}
+
+// IsScopeContext returns true if the DNS name in the parameter is out of scope, but shares the registered
+// domain with a sub-zone or an exact host in scope. These names in the parent zones provide context for
+// the enumeration, and are recorded without being investigated.
+func (c *Config) IsScopeContext(name string) bool {
+	n := strings.ToLower(strings.TrimSpace(name))
+	if n == "" || c.IsDomainInScope(n) || c.Blacklisted(n) {
+		return false
+	}
+
+	registered, err := publicsuffix.EffectiveTLDPlusOne(n)
+	if err != nil {
+		return false
+	}
+
+	for _, entry := range append(c.Domains(), c.Hosts()...) {
+		// Root domain names do not have parent zones within the registered domain
+		if entry != registered && hasPathSuffix(entry, registered) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsDomainInScope returns true if the DNS name in the parameter ends with a domain in the config list,
+// or is an exact host in the config list.
 func (c *Config) IsDomainInScope(name string) bool {
 	var discovered bool
 
@@ -93,6 +161,7 @@ func (c *Config) IsDomainInScope(name string) bool {
 }
 
 // WhichDomain returns the domain in the config list that the DNS name in the parameter ends with.
+// The exact hosts in the config list are returned when the DNS name matches the host.
 func (c *Config) WhichDomain(name string) string {
 	n := strings.ToLower(strings.TrimSpace(name))
 
@@ -101,6 +170,11 @@ func (c *Config) WhichDomain(name string) string {
 			return d
 		}
 	}
+	for _, h := range c.Hosts() {
+		if n == h {
+			return h
+		}
+	}
 	return ""
 }
 
@@ -118,10 +192,10 @@ func hasPathSuffix(path, suffix string) bool {
 }
 
 // IsAddressInScope returns true if the addr parameter matches provided network scope and when
-// no network scope has been set.
+// no network scope has been set. Addresses in the excluded netblocks are never in scope.
 func (c *Config) IsAddressInScope(addr string) bool {
 	ip := net.ParseIP(addr)
-	if ip == nil {
+	if ip == nil || c.IsAddressExcluded(addr) {
 		return false
 	}
 
@@ -143,6 +217,31 @@ func (c *Config) IsAddressInScope(addr string) bool {
 	return false
 }
 
+// IsAddressExcluded returns true if the addr parameter falls within an excluded netblock.
+func (c *Config) IsAddressExcluded(addr string) bool {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return false
+	}
+
+	for _, cidr := range c.ExcludedCIDRs {
+		if cidr.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsASNExcluded returns true if the autonomous system number is out of scope.
+func (c *Config) IsASNExcluded(asn int) bool {
+	for _, a := range c.ExcludedASNs {
+		if a == asn {
+			return true
+		}
+	}
+	return false
+}
+
 // BlacklistSubdomain adds a subdomain name to the config blacklist.
 func (c *Config) BlacklistSubdomain(name string) {
 	c.blacklistLock.Lock()
@@ -163,14 +262,53 @@ func (c *Config) Blacklisted(name string) bool {
 	n := strings.ToLower(strings.TrimSpace(name))
 
 	for _, bl := range c.Blacklist {
-		if hasPathSuffix(n, bl) {
+		if !strings.Contains(bl, "*") {
+			if hasPathSuffix(n, bl) {
+				return true
+			}
+		} else if re := c.blacklistRegexp(bl, true); re != nil && re.MatchString(n) {
 			return true
 		}
 	}
 
+	for _, expr := range c.BlacklistRegexps {
+		if re := c.blacklistRegexp(expr, false); re != nil && re.MatchString(n) {
+			return true
+		}
+	}
 	return false
 }
 
+// Returns the compiled blacklist pattern or regular expression. The caller must hold the blacklist lock.
+func (c *Config) blacklistRegexp(expr string, pattern bool) *regexp.Regexp {
+	if re, found := c.blacklistRes[expr]; found {
+		return re
+	}
+
+	var re *regexp.Regexp
+	if pattern {
+		re = globRegexp(expr)
+	} else {
+		re, _ = regexp.Compile(expr)
+	}
+
+	if c.blacklistRes == nil {
+		c.blacklistRes = make(map[string]*regexp.Regexp)
+	}
+	c.blacklistRes[expr] = re
+	return re
+}
+
+// Returns the regular expression for the name pattern, where the * wildcard matches any characters,
+// including the dots. Like the other blacklist entries, the subdomains of the matching names also match.
+func globRegexp(pattern string) *regexp.Regexp {
+	p := strings.ToLower(strings.TrimSpace(pattern))
+	p = strings.ReplaceAll(regexp.QuoteMeta(p), `\*`, ".*")
+
+	re, _ := regexp.Compile(`(^|\.)` + p + `$`)
+	return re
+}
+
 func (c *Config) loadScopeSettings(cfg *ini.File) error {
 	scope, err := cfg.GetSection("scope")
 	if err != nil {
@@ -218,6 +356,11 @@ func (c *Config) loadScopeSettings(cfg *ini.File) error {
 		}
 	}
 
+	// Load up the exact host names
+	if hosts, err := cfg.GetSection("scope.hosts"); err == nil {
+		c.AddHosts(hosts.Key("host").ValueWithShadows()...)
+	}
+
 	// Load up all the blacklisted subdomain names
 	if blacklisted, err := cfg.GetSection("scope.blacklisted"); err == nil {
 		c.Blacklist = stringset.Deduplicate(blacklisted.Key("subdomain").ValueWithShadows())
@@ -226,6 +369,39 @@ func (c *Config) loadScopeSettings(cfg *ini.File) error {
 	return nil
 }
 
+func (c *Config) loadExclusionSettings(cfg *ini.File) error {
+	excluded, err := cfg.GetSection("scope.excluded")
+	if err != nil {
+		return nil
+	}
+
+	if excluded.HasKey("regex") {
+		for _, expr := range excluded.Key("regex").ValueWithShadows() {
+			if _, err := regexp.Compile(expr); err != nil {
+				return fmt.Errorf("the excluded regular expression %s is invalid: %v", expr, err)
+			}
+			c.BlacklistRegexps = append(c.BlacklistRegexps, expr)
+		}
+	}
+
+	if excluded.HasKey("cidr") {
+		for _, cidr := range excluded.Key("cidr").ValueWithShadows() {
+			_, ipnet, err := net.ParseCIDR(strings.TrimSpace(cidr))
+			if err != nil {
+				return err
+			}
+			c.ExcludedCIDRs = append(c.ExcludedCIDRs, ipnet)
+		}
+	}
+
+	if excluded.HasKey("asn") {
+		for _, asn := range excluded.Key("asn").ValueWithShadows() {
+			c.ExcludedASNs = uniqueIntAppend(c.ExcludedASNs, asn)
+		}
+	}
+	return nil
+}
+
 type parseIPs []net.IP
 
 func (p *parseIPs) String() string {
@@ -382,3 +382,114 @@ func TestLoadScopeSettings(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadExclusionSettings(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     string
+		wantErr bool
+	}{
+		{name: "failure - invalid regular expression", cfg: "[scope.excluded]\nregex = dev-(\n", wantErr: true},
+		{name: "failure - invalid cidr", cfg: "[scope.excluded]\ncidr = (invalid value)\n", wantErr: true},
+		{name: "success - valid exclusions", cfg: "[scope.excluded]\nregex = ^dev-[0-9]+\\.\ncidr = 192.168.0.0/16\nasn = 13335\nasn = 13335\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			iniFile, err := ini.ShadowLoad([]byte(tt.cfg))
+			if err != nil {
+				t.Fatalf("Failed to load the settings: %v", err)
+			}
+
+			c := new(Config)
+			if err := c.loadExclusionSettings(iniFile); (err != nil) != tt.wantErr {
+				t.Errorf("Config.loadExclusionSettings() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && (len(c.BlacklistRegexps) != 1 || len(c.ExcludedCIDRs) != 1 || len(c.ExcludedASNs) != 1) {
+				t.Errorf("Config.loadExclusionSettings() failed to load the exclusions")
+			}
+		})
+	}
+}
+
+func TestConfigExclusions(t *testing.T) {
+	c := NewConfig()
+	c.AddDomain("example.com")
+	c.Blacklist = []string{"internal.example.com", "*.corp.example.com", "dev-*.example.com"}
+	c.BlacklistRegexps = []string{`^staging[0-9]+\.example\.com$`}
+	_, ipnet, _ := net.ParseCIDR("192.168.0.0/16")
+	c.ExcludedCIDRs = []*net.IPNet{ipnet}
+	c.ExcludedASNs = []int{13335}
+
+	for name, expected := range map[string]bool{
+		"internal.example.com":      true,
+		"a.internal.example.com":    true,
+		"corp.example.com":          false,
+		"www.corp.example.com":      true,
+		"a.b.corp.example.com":      true,
+		"dev-api.example.com":       true,
+		"www.dev-api.example.com":   true,
+		"api-dev.example.com":       false,
+		"staging12.example.com":     true,
+		"www.staging12.example.com": false,
+		"www.example.com":           false,
+	} {
+		if got := c.Blacklisted(name); got != expected {
+			t.Errorf("Blacklisted(%s) returned %t, expected %t", name, got, expected)
+		}
+	}
+
+	if !c.IsAddressExcluded("192.168.1.1") || c.IsAddressExcluded("10.0.0.1") {
+		t.Errorf("IsAddressExcluded did not match the excluded netblocks")
+	}
+	if c.IsAddressInScope("192.168.1.1") || !c.IsAddressInScope("10.0.0.1") {
+		t.Errorf("IsAddressInScope did not enforce the excluded netblocks")
+	}
+	if !c.IsASNExcluded(13335) || c.IsASNExcluded(26808) {
+		t.Errorf("IsASNExcluded did not match the excluded ASNs")
+	}
+}
+
+func TestConfigSubZonesAndHosts(t *testing.T) {
+	c := NewConfig()
+	c.AddDomain("*.shop.example.com")
+	c.AddHosts("API.example.org", "nolabels")
+
+	if domains := c.Domains(); !reflect.DeepEqual(domains, []string{"shop.example.com"}) {
+		t.Errorf("AddDomain did not accept the wildcard sub-zone: %v", domains)
+	}
+	if hosts := c.Hosts(); !reflect.DeepEqual(hosts, []string{"api.example.org"}) {
+		t.Errorf("AddHosts provided the hosts %v", hosts)
+	}
+
+	for name, expected := range map[string]string{
+		"shop.example.com":     "shop.example.com",
+		"www.shop.example.com": "shop.example.com",
+		"www.example.com":      "",
+		"api.example.org":      "api.example.org",
+		"v1.api.example.org":   "",
+	} {
+		if got := c.WhichDomain(name); got != expected {
+			t.Errorf("WhichDomain(%s) returned %s, expected %s", name, got, expected)
+		}
+	}
+
+	c.BlacklistSubdomain("internal.example.com")
+	for name, expected := range map[string]bool{
+		"example.com":          true,
+		"www.example.com":      true,
+		"v1.api.example.org":   true,
+		"example.org":          true,
+		"www.shop.example.com": false,
+		"api.example.org":      false,
+		"internal.example.com": false,
+		"www.example.net":      false,
+	} {
+		if got := c.IsScopeContext(name); got != expected {
+			t.Errorf("IsScopeContext(%s) returned %t, expected %t", name, got, expected)
+		}
+	}
+
+	if !c.IsExactHost("api.example.org") || c.IsExactHost("shop.example.com") {
+		t.Errorf("IsExactHost did not match the exact hosts")
+	}
+}
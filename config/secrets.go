@@ -0,0 +1,169 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// The prefixes of the secret references accepted by the data source credential settings.
+const (
+	secretFilePrefix = "file:"
+	secretEnvPrefix  = "env:"
+	secretExecPrefix = "exec:"
+)
+
+// The longest time a secret command is allowed to run
+const secretExecTimeout = 30 * time.Second
+
+// IsSecretReference returns true when the credential value references a secret stored elsewhere.
+func IsSecretReference(value string) bool {
+	for _, prefix := range []string{secretFilePrefix, secretEnvPrefix, secretExecPrefix} {
+		if strings.HasPrefix(value, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the secret referenced by the credential value, or the value itself when it is not a reference.
+// The file: reference reads the secret from a file, env: reads an environment variable, and exec: runs
+// a command, such as a password manager, and reads the secret from the standard output. The command is
+// not run by a shell, but the arguments are quoted and escaped as in a shell. The errors never include
+// the secret, or the arguments and output of the command.
+func resolveSecret(value string) (string, error) {
+	switch {
+	case strings.HasPrefix(value, secretFilePrefix):
+		path := strings.TrimSpace(strings.TrimPrefix(value, secretFilePrefix))
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("failed to read the secret file: %v", err)
+		}
+		return strings.TrimSpace(string(data)), nil
+	case strings.HasPrefix(value, secretEnvPrefix):
+		name := strings.TrimSpace(strings.TrimPrefix(value, secretEnvPrefix))
+
+		secret, found := os.LookupEnv(name)
+		if !found {
+			return "", fmt.Errorf("the %s environment variable is not set", name)
+		}
+		return strings.TrimSpace(secret), nil
+	case strings.HasPrefix(value, secretExecPrefix):
+		args, err := splitCommand(strings.TrimPrefix(value, secretExecPrefix))
+		if err != nil {
+			return "", err
+		}
+		if len(args) == 0 {
+			return "", errors.New("the secret command is empty")
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), secretExecTimeout)
+		defer cancel()
+
+		out, err := exec.CommandContext(ctx, args[0], args[1:]...).Output()
+		if err != nil {
+			return "", fmt.Errorf("the %s secret command failed: %v", args[0], err)
+		}
+		return strings.TrimSpace(string(out)), nil
+	}
+	return value, nil
+}
+
+// Splits the command line into arguments using the quoting rules of a POSIX shell: single quotes
+// preserve every character, while a backslash escapes the next character outside of quotes and
+// the double quote, backslash, dollar sign and backtick inside of double quotes.
+func splitCommand(line string) ([]string, error) {
+	var args []string
+	var cur strings.Builder
+	var inArg, escaped bool
+	var quote rune
+
+	for _, c := range line {
+		switch {
+		case escaped:
+			if quote == '"' && !strings.ContainsRune("\"\\$`", c) {
+				cur.WriteRune('\\')
+			}
+			cur.WriteRune(c)
+			escaped = false
+		case quote == '\'':
+			if c == '\'' {
+				quote = 0
+			} else {
+				cur.WriteRune(c)
+			}
+		case c == '\\':
+			escaped, inArg = true, true
+		case quote == '"':
+			if c == '"' {
+				quote = 0
+			} else {
+				cur.WriteRune(c)
+			}
+		case c == '\'' || c == '"':
+			quote, inArg = c, true
+		case c == ' ' || c == '\t' || c == '\n':
+			if inArg {
+				args = append(args, cur.String())
+				cur.Reset()
+				inArg = false
+			}
+		default:
+			cur.WriteRune(c)
+			inArg = true
+		}
+	}
+
+	if escaped || quote != 0 {
+		return nil, errors.New("the secret command has an unterminated quote or escape")
+	}
+	if inArg {
+		args = append(args, cur.String())
+	}
+	return args, nil
+}
+
+// Returns a copy of the credentials with the secret references replaced by the secrets.
+func (c *Credentials) resolve() (*Credentials, error) {
+	r := &Credentials{Name: c.Name}
+
+	for _, field := range []struct {
+		name  string
+		value string
+		dst   *string
+	}{
+		{name: "username", value: c.Username, dst: &r.Username},
+		{name: "password", value: c.Password, dst: &r.Password},
+		{name: "apikey", value: c.Key, dst: &r.Key},
+		{name: "secret", value: c.Secret, dst: &r.Secret},
+	} {
+		secret, err := resolveSecret(field.value)
+		if err != nil {
+			return nil, fmt.Errorf("the %s of the %s credentials: %v", field.name, c.Name, err)
+		}
+		*field.dst = secret
+	}
+	return r, nil
+}
+
+// String implements the Stringer interface, and only reveals the credential values that are not secret.
+func (c *Credentials) String() string {
+	mask := func(value string) string {
+		if value == "" {
+			return ""
+		}
+		return "********"
+	}
+
+	return fmt.Sprintf("%s{username: %s, password: %s, apikey: %s, secret: %s}",
+		c.Name, c.Username, mask(c.Password), mask(c.Key), mask(c.Secret))
+}
@@ -0,0 +1,153 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestResolveCredentials(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "apikey")
+	if err := os.WriteFile(path, []byte("filesecret\n"), 0600); err != nil {
+		t.Fatalf("Failed to write the secret file: %v", err)
+	}
+	t.Setenv("AMASS_TEST_SECRET", "envsecret")
+
+	creds := &Credentials{
+		Name:     "account1",
+		Username: "user",
+		Key:      "file:" + path,
+		Secret:   "env:AMASS_TEST_SECRET",
+		Password: "cleartext",
+	}
+	if runtime.GOOS != "windows" {
+		creds.Password = "exec:echo execsecret"
+	}
+
+	dsc := NewConfig().GetDataSourceConfig("test")
+	if err := dsc.AddCredentials(creds); err != nil {
+		t.Fatalf("AddCredentials returned an error: %v", err)
+	}
+	// The secrets are only resolved once the credentials are selected
+	if creds.Key != "file:"+path {
+		t.Errorf("The secret reference was resolved when the credentials were added")
+	}
+
+	r, err := dsc.ResolveCredentials()
+	if err != nil {
+		t.Fatalf("ResolveCredentials returned an error: %v", err)
+	}
+	if r.Username != "user" || r.Key != "filesecret" || r.Secret != "envsecret" {
+		t.Errorf("The secret references were not resolved: %s, %s, %s", r.Username, r.Key, r.Secret)
+	}
+	if runtime.GOOS != "windows" && r.Password != "execsecret" {
+		t.Errorf("The exec secret reference was not resolved: %s", r.Password)
+	}
+
+	// The resolved credentials are kept for later selections
+	if err := os.Remove(path); err != nil {
+		t.Fatalf("Failed to remove the secret file: %v", err)
+	}
+	if r := dsc.GetCredentials(); r == nil || r.Key != "filesecret" {
+		t.Errorf("The resolved credentials were not kept")
+	}
+
+	for _, s := range []string{r.String(), fmt.Sprintf("%v", r)} {
+		if strings.Contains(s, "filesecret") || strings.Contains(s, "envsecret") || strings.Contains(s, "execsecret") {
+			t.Errorf("The resolved secrets were revealed by the string representation: %s", s)
+		}
+	}
+}
+
+func TestResolveCredentialsErrors(t *testing.T) {
+	t.Setenv("AMASS_TEST_SECRET", "")
+	os.Unsetenv("AMASS_TEST_SECRET")
+
+	for _, value := range []string{
+		"file:" + filepath.Join(t.TempDir(), "missing"),
+		"env:AMASS_TEST_SECRET",
+		"exec:",
+		"exec:amass-missing-secret-command --token hunter2",
+	} {
+		dsc := NewConfig().GetDataSourceConfig("test")
+		if err := dsc.AddCredentials(&Credentials{Name: "account1", Key: value}); err != nil {
+			t.Fatalf("AddCredentials returned an error: %v", err)
+		}
+
+		if missing := dsc.MissingCredentials("apikey"); len(missing) != 0 {
+			t.Errorf("MissingCredentials reported the secret reference %s as missing", value)
+		}
+		if creds := dsc.GetCredentials(); creds != nil {
+			t.Errorf("GetCredentials returned credentials for the unresolved reference %s", value)
+		}
+
+		_, err := dsc.ResolveCredentials()
+		if err == nil {
+			t.Errorf("ResolveCredentials did not fail for the reference %s", value)
+		} else if strings.Contains(err.Error(), "hunter2") {
+			t.Errorf("The error revealed the arguments of the secret command: %v", err)
+		}
+	}
+}
+
+func TestResolveCredentialsFailureKept(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test uses a POSIX shell")
+	}
+
+	count := filepath.Join(t.TempDir(), "count")
+	dsc := NewConfig().GetDataSourceConfig("test")
+	if err := dsc.AddCredentials(&Credentials{
+		Name: "account1",
+		Key:  "exec:sh -c 'echo run >> \"$0\"; exit 1' " + count,
+	}); err != nil {
+		t.Fatalf("AddCredentials returned an error: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := dsc.ResolveCredentials(); err == nil {
+			t.Fatalf("ResolveCredentials did not fail for the failing secret command")
+		}
+	}
+	if data, err := os.ReadFile(count); err != nil || strings.Count(string(data), "run") != 1 {
+		t.Errorf("The failing secret command was not run exactly once: %q, %v", data, err)
+	}
+}
+
+func TestSplitCommand(t *testing.T) {
+	tests := []struct {
+		line string
+		want []string
+	}{
+		{"pass show amass/shodan", []string{"pass", "show", "amass/shodan"}},
+		{"  vault  kv get\t-field=apikey ", []string{"vault", "kv", "get", "-field=apikey"}},
+		{`op read "op://Private/Amass Shodan/credential"`, []string{"op", "read", "op://Private/Amass Shodan/credential"}},
+		{`sh -c 'echo "$HOME" \n'`, []string{"sh", "-c", `echo "$HOME" \n`}},
+		{`cat /secrets/api\ key "a\"b\c" ''`, []string{"cat", "/secrets/api key", `a"b\c`, ""}},
+		{"", nil},
+	}
+
+	for _, test := range tests {
+		got, err := splitCommand(test.line)
+		if err != nil {
+			t.Errorf("splitCommand(%q) returned an error: %v", test.line, err)
+			continue
+		}
+		if fmt.Sprintf("%q", got) != fmt.Sprintf("%q", test.want) {
+			t.Errorf("splitCommand(%q) returned %q, expected %q", test.line, got, test.want)
+		}
+	}
+
+	for _, line := range []string{`pass show 'amass`, `pass show "amass`, `pass show amass\`} {
+		if _, err := splitCommand(line); err == nil {
+			t.Errorf("splitCommand(%q) did not return an error", line)
+		}
+	}
+}
@@ -0,0 +1,510 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/OWASP/Amass/v3/stringutil"
+	"github.com/go-ini/ini"
+	"gopkg.in/yaml.v3"
+)
+
+// The kinds of values accepted by the structured configuration file formats.
+const (
+	kindString = iota
+	kindInt
+	kindBool
+	kindStringList
+	kindIntList
+	kindMap
+	kindOption
+)
+
+// SettingsError reports the problems found while validating a configuration file.
+type SettingsError struct {
+	Path   string
+	Issues []string
+}
+
+// Error implements the error interface.
+func (e *SettingsError) Error() string {
+	return fmt.Sprintf("the configuration file %s is invalid:\n\t%s", e.Path, strings.Join(e.Issues, "\n\t"))
+}
+
+// Describes the structure of a YAML or JSON configuration file and the INI section each value maps to.
+type settingSchema struct {
+	kind int
+	// The fixed keys of a map
+	fields map[string]*settingSchema
+	// The schema of the map keys chosen by the user, such as data source names
+	names *settingSchema
+	// The schema of the map keys not found in fields, such as data source options
+	other *settingSchema
+	// Keys that are not accepted, with the reason reported to the user
+	invalid map[string]string
+}
+
+var (
+	databaseSchema = &settingSchema{kind: kindMap, fields: map[string]*settingSchema{
+		"primary":  {kind: kindBool},
+		"url":      {kind: kindString},
+		"username": {kind: kindString},
+		"password": {kind: kindString},
+		"database": {kind: kindString},
+		"options":  {kind: kindString},
+	}}
+	credentialsSchema = &settingSchema{kind: kindMap, fields: map[string]*settingSchema{
+		"apikey":   {kind: kindString},
+		"secret":   {kind: kindString},
+		"username": {kind: kindString},
+		"password": {kind: kindString},
+	}}
+	dataSourceSchema = &settingSchema{
+		kind: kindMap,
+		fields: map[string]*settingSchema{
+			"ttl":         {kind: kindInt},
+			"plugin":      {kind: kindString},
+			"proxy":       {kind: kindString},
+			"credentials": {kind: kindMap, names: credentialsSchema},
+		},
+		// Data source specific settings
+		other: &settingSchema{kind: kindOption},
+		invalid: map[string]string{
+			"apikey":   "credentials must be provided in the credentials map of the data source",
+			"secret":   "credentials must be provided in the credentials map of the data source",
+			"username": "credentials must be provided in the credentials map of the data source",
+			"password": "credentials must be provided in the credentials map of the data source",
+		},
+	}
+	configSchema = &settingSchema{kind: kindMap, fields: map[string]*settingSchema{
+		"mode":                  {kind: kindString},
+		"output_directory":      {kind: kindString},
+		"scripts_directory":     {kind: kindString},
+		"rdap_bootstrap":        {kind: kindString},
+		"proxy":                 {kind: kindString},
+		"http_retries":          {kind: kindInt},
+		"http_retry_backoff":    {kind: kindInt},
+		"http_host_concurrency": {kind: kindInt},
+		"maximum_dns_queries":   {kind: kindInt},
+		"resolvers_qps":         {kind: kindInt},
+		"trusted_resolvers_qps": {kind: kindInt},
+		"timeout":               {kind: kindInt},
+		"resolvers":             {kind: kindStringList},
+		"scope": {kind: kindMap, fields: map[string]*settingSchema{
+			"address":     {kind: kindStringList},
+			"cidr":        {kind: kindStringList},
+			"asn":         {kind: kindIntList},
+			"port":        {kind: kindIntList},
+			"domains":     {kind: kindStringList},
+			"hosts":       {kind: kindStringList},
+			"blacklisted": {kind: kindStringList},
+			"excluded": {kind: kindMap, fields: map[string]*settingSchema{
+				"regex": {kind: kindStringList},
+				"cidr":  {kind: kindStringList},
+				"asn":   {kind: kindIntList},
+			}},
+		}},
+		"graphdbs": {kind: kindMap, names: databaseSchema},
+		"bruteforce": {kind: kindMap, fields: map[string]*settingSchema{
+			"enabled":               {kind: kindBool},
+			"recursive":             {kind: kindBool},
+			"minimum_for_recursive": {kind: kindInt},
+			"max_depth":             {kind: kindInt},
+			"wordlist_file":         {kind: kindStringList},
+		}},
+		"alterations": {kind: kindMap, fields: map[string]*settingSchema{
+			"enabled":               {kind: kindBool},
+			"edit_distance":         {kind: kindInt},
+			"flip_words":            {kind: kindBool},
+			"flip_numbers":          {kind: kindBool},
+			"add_words":             {kind: kindBool},
+			"add_numbers":           {kind: kindBool},
+			"minimum_for_word_flip": {kind: kindInt},
+			"wordlist_file":         {kind: kindStringList},
+		}},
+		"data_sources": {
+			kind: kindMap,
+			fields: map[string]*settingSchema{
+				"minimum_ttl": {kind: kindInt},
+				"enabled":     {kind: kindStringList},
+				"disabled":    {kind: kindStringList},
+			},
+			names: dataSourceSchema,
+		},
+	}}
+)
+
+func init() {
+	// The profiles provide the same settings as the base configuration, except other profiles
+	profile := &settingSchema{kind: kindMap, fields: make(map[string]*settingSchema)}
+	for name, field := range configSchema.fields {
+		profile.fields[name] = field
+	}
+	configSchema.fields[profilesSection] = &settingSchema{kind: kindMap, names: profile}
+}
+
+// Returns true when the configuration file path has a YAML or JSON file extension.
+func isStructuredFile(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml", ".json":
+		return true
+	}
+	return false
+}
+
+// Parses and validates the YAML or JSON configuration file, and returns the settings
+// in the layout of the INI configuration file, so the same loaders can be used.
+func loadStructuredFile(path string) (*ini.File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the configuration file: %v", err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, &SettingsError{Path: path, Issues: []string{err.Error()}}
+	}
+
+	if doc.Kind == 0 || len(doc.Content) == 0 {
+		// The file is empty or only contains comments
+		return iniSettings(nil)
+	}
+
+	root := doc.Content[0]
+
+	if issues := validateSettings(root, configSchema, ""); len(issues) > 0 {
+		return nil, &SettingsError{Path: path, Issues: issues}
+	}
+	return iniSettings(root)
+}
+
+// Checks the node against the schema and returns the problems found, including the line numbers.
+func validateSettings(node *yaml.Node, schema *settingSchema, path string) []string {
+	issue := func(format string, args ...interface{}) []string {
+		return []string{fmt.Sprintf("line %d: %s", node.Line, fmt.Sprintf(format, args...))}
+	}
+	name := path
+	if name == "" {
+		name = "the configuration"
+	}
+
+	switch schema.kind {
+	case kindString:
+		if node.Kind != yaml.ScalarNode || node.Tag == "!!null" {
+			return issue("%s must be a string", name)
+		}
+	case kindInt:
+		if node.Kind != yaml.ScalarNode || node.Tag != "!!int" {
+			return issue("%s must be an integer", name)
+		}
+	case kindBool:
+		if node.Kind != yaml.ScalarNode || node.Tag != "!!bool" {
+			return issue("%s must be true or false", name)
+		}
+	case kindStringList, kindIntList, kindOption:
+		elem := &settingSchema{kind: kindString}
+		if schema.kind == kindIntList {
+			elem.kind = kindInt
+		}
+		if node.Kind == yaml.ScalarNode {
+			return validateSettings(node, elem, path)
+		}
+		if node.Kind != yaml.SequenceNode {
+			return issue("%s must be a value or a list of values", name)
+		}
+
+		var issues []string
+		for _, n := range node.Content {
+			issues = append(issues, validateSettings(n, elem, path)...)
+		}
+		return issues
+	case kindMap:
+		if node.Kind == yaml.ScalarNode && node.Tag == "!!null" {
+			// An empty map, such as a data source without settings
+			return nil
+		}
+		if node.Kind != yaml.MappingNode {
+			return issue("%s must be a map of settings", name)
+		}
+
+		var issues []string
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			k := strings.ToLower(key.Value)
+			kpath := k
+			if path != "" {
+				kpath = path + "." + key.Value
+			}
+
+			var s *settingSchema
+			if reason, found := schema.invalid[k]; found {
+				issues = append(issues, fmt.Sprintf("line %d: %s: %s", key.Line, kpath, reason))
+				continue
+			} else if f, found := schema.fields[k]; found {
+				s = f
+			} else if schema.names != nil {
+				s = schema.names
+			} else if schema.other != nil {
+				s = schema.other
+			}
+
+			if s == nil {
+				msg := fmt.Sprintf("line %d: unknown key %s", key.Line, kpath)
+				if suggestion := closestSettingKey(k, schema.fields); suggestion != "" {
+					msg += fmt.Sprintf(" (did you mean %s?)", suggestion)
+				}
+				issues = append(issues, msg)
+				continue
+			}
+			issues = append(issues, validateSettings(value, s, kpath)...)
+		}
+		return issues
+	}
+	return nil
+}
+
+// Returns the known key most similar to the unknown key, if any are close enough.
+func closestSettingKey(key string, fields map[string]*settingSchema) string {
+	var keys []string
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	best, dist := "", 3
+	for _, k := range keys {
+		if d := stringutil.Levenshtein(key, k); d < dist {
+			best, dist = k, d
+		}
+	}
+	return best
+}
+
+// Builds the INI representation of the validated settings.
+func iniSettings(root *yaml.Node) (*ini.File, error) {
+	cfg := ini.Empty(ini.LoadOptions{
+		Insensitive:  true,
+		AllowShadows: true,
+	})
+	// The data sources section is always expected by the loaders
+	if _, err := cfg.NewSection("data_sources"); err != nil {
+		return nil, err
+	}
+	if root == nil {
+		return cfg, nil
+	}
+
+	for key, value := range mapEntries(root) {
+		var err error
+
+		switch key {
+		case "resolvers":
+			err = setKey(cfg, "resolvers", "resolver", value)
+		case "scope":
+			err = iniScope(cfg, value)
+		case "graphdbs":
+			err = iniSubsections(cfg, "graphdbs", value)
+		case "bruteforce", "alterations":
+			err = iniSection(cfg, key, value)
+		case "data_sources":
+			err = iniDataSources(cfg, value)
+		case "profiles":
+			err = iniProfiles(cfg, value)
+		default:
+			err = setKey(cfg, ini.DefaultSection, key, value)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return cfg, nil
+}
+
+// Converts each profile like the base settings, and moves the sections under the profile section.
+func iniProfiles(cfg *ini.File, node *yaml.Node) error {
+	for name, value := range mapEntries(node) {
+		profile, err := iniSettings(value)
+		if err != nil {
+			return err
+		}
+
+		for _, sec := range profile.Sections() {
+			section := profilesSection + "." + name
+			if sec.Name() != ini.DefaultSection {
+				section += "." + sec.Name()
+			}
+			if _, err := cfg.NewSection(section); err != nil {
+				return err
+			}
+
+			for _, key := range sec.Keys() {
+				if err := setValues(cfg, section, key.Name(), key.ValueWithShadows()); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func iniScope(cfg *ini.File, node *yaml.Node) error {
+	if _, err := cfg.NewSection("scope"); err != nil {
+		return err
+	}
+
+	for key, value := range mapEntries(node) {
+		var err error
+
+		switch key {
+		case "domains":
+			err = setKey(cfg, "scope.domains", "domain", value)
+		case "hosts":
+			err = setKey(cfg, "scope.hosts", "host", value)
+		case "blacklisted":
+			err = setKey(cfg, "scope.blacklisted", "subdomain", value)
+		case "excluded":
+			for k, v := range mapEntries(value) {
+				if err = setKey(cfg, "scope.excluded", k, v); err != nil {
+					break
+				}
+			}
+		default:
+			err = setKey(cfg, "scope", key, value)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func iniDataSources(cfg *ini.File, node *yaml.Node) error {
+	for key, value := range mapEntries(node) {
+		var err error
+
+		switch key {
+		case "minimum_ttl":
+			err = setKey(cfg, "data_sources", key, value)
+		case "enabled", "disabled":
+			err = setKey(cfg, "data_sources."+key, "data_source", value)
+		default:
+			err = iniDataSource(cfg, "data_sources."+key, value)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func iniDataSource(cfg *ini.File, section string, node *yaml.Node) error {
+	if _, err := cfg.NewSection(section); err != nil {
+		return err
+	}
+
+	for key, value := range mapEntries(node) {
+		if key == "credentials" {
+			if err := iniSubsections(cfg, section, value); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := setKey(cfg, section, key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Creates a child section for each named map, such as the graph databases and credential sets.
+func iniSubsections(cfg *ini.File, parent string, node *yaml.Node) error {
+	if _, err := cfg.NewSection(parent); err != nil {
+		return err
+	}
+
+	for name, value := range mapEntries(node) {
+		if err := iniSection(cfg, parent+"."+name, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func iniSection(cfg *ini.File, section string, node *yaml.Node) error {
+	if _, err := cfg.NewSection(section); err != nil {
+		return err
+	}
+
+	for key, value := range mapEntries(node) {
+		if err := setKey(cfg, section, key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Sets the key in the section, using shadows for lists of values.
+func setKey(cfg *ini.File, section, key string, node *yaml.Node) error {
+	var values []string
+
+	if node.Kind == yaml.SequenceNode {
+		for _, n := range node.Content {
+			values = append(values, n.Value)
+		}
+	} else {
+		values = append(values, node.Value)
+	}
+	return setValues(cfg, section, key, values)
+}
+
+// Replaces any existing value of the key in the section, using shadows for lists of values.
+// The section and the parent sections are created when missing, since the loaders only find
+// the child sections through the parent sections.
+func setValues(cfg *ini.File, section, key string, values []string) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	parts := strings.Split(section, ".")
+	for i := 1; i < len(parts); i++ {
+		if _, err := cfg.NewSection(strings.Join(parts[:i], ".")); err != nil {
+			return err
+		}
+	}
+
+	sec, err := cfg.GetSection(section)
+	if err != nil {
+		if sec, err = cfg.NewSection(section); err != nil {
+			return err
+		}
+	}
+
+	sec.DeleteKey(key)
+	k, err := sec.NewKey(key, values[0])
+	if err != nil {
+		return err
+	}
+	for _, v := range values[1:] {
+		if err := k.AddShadow(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Returns the entries of the mapping node with the keys in lowercase.
+func mapEntries(node *yaml.Node) map[string]*yaml.Node {
+	entries := make(map[string]*yaml.Node)
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		entries[strings.ToLower(node.Content[i].Value)] = node.Content[i+1]
+	}
+	return entries
+}
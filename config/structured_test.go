@@ -0,0 +1,167 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadStructuredSettings(t *testing.T) {
+	yamlCfg := `mode: passive
+maximum_dns_queries: 500
+resolvers: [8.8.8.8, 1.1.1.1]
+scope:
+  port: [80, 8443]
+  domains:
+    - owasp.org
+  blacklisted: internal.owasp.org
+graphdbs:
+  postgres:
+    primary: true
+    url: postgres://localhost/amass
+bruteforce:
+  enabled: false
+data_sources:
+  minimum_ttl: 60
+  disabled: [Ask]
+  Shodan:
+    ttl: 120
+    credentials:
+      Primary:
+        apikey: abc123
+  CTLogs:
+    path: [/data/a.json, /data/b.json]
+  AlienVault:
+`
+	jsonCfg := `{
+  "mode": "passive",
+  "maximum_dns_queries": 500,
+  "resolvers": ["8.8.8.8", "1.1.1.1"],
+  "scope": {"port": [80, 8443], "domains": ["owasp.org"], "blacklisted": "internal.owasp.org"},
+  "graphdbs": {"postgres": {"primary": true, "url": "postgres://localhost/amass"}},
+  "bruteforce": {"enabled": false},
+  "data_sources": {
+    "minimum_ttl": 60,
+    "disabled": ["Ask"],
+    "Shodan": {"ttl": 120, "credentials": {"Primary": {"apikey": "abc123"}}},
+    "CTLogs": {"path": ["/data/a.json", "/data/b.json"]},
+    "AlienVault": {}
+  }
+}`
+
+	dir := t.TempDir()
+	for name, content := range map[string]string{"config.yaml": yamlCfg, "config.json": jsonCfg} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+
+		c := NewConfig()
+		if err := c.LoadSettings(path); err != nil {
+			t.Fatalf("%s: failed to load the settings: %v", name, err)
+		}
+		if !c.Passive || c.MaxDNSQueries != 500 || len(c.Resolvers) != 2 || c.BruteForcing {
+			t.Errorf("%s: the general settings were not loaded", name)
+		}
+		if len(c.Ports) != 3 || c.Ports[2] != 8443 || !c.IsDomainInScope("www.owasp.org") || !c.Blacklisted("internal.owasp.org") {
+			t.Errorf("%s: the scope settings were not loaded", name)
+		}
+		if len(c.GraphDBs) != 1 || c.GraphDBs[0].System != "postgres" || !c.GraphDBs[0].Primary {
+			t.Errorf("%s: the graph database settings were not loaded", name)
+		}
+		if c.MinimumTTL != 60 || c.SourceFilter.Include || len(c.SourceFilter.Sources) != 1 {
+			t.Errorf("%s: the data source settings were not loaded", name)
+		}
+
+		dsc := c.GetDataSourceConfig("Shodan")
+		if creds := dsc.GetCredentials(); dsc.TTL != 120 || creds == nil || creds.Key != "abc123" {
+			t.Errorf("%s: the Shodan settings were not loaded", name)
+		}
+		if paths := c.GetDataSourceConfig("CTLogs").OptionValues("path"); len(paths) != 2 {
+			t.Errorf("%s: the CTLogs options were not loaded: %v", name, paths)
+		}
+	}
+}
+
+func TestLoadStructuredSettingsEmpty(t *testing.T) {
+	dir := t.TempDir()
+
+	for name, content := range map[string]string{
+		"empty.yaml":    "",
+		"comments.yaml": "# The default settings are used\n",
+		"empty.json":    "",
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write the file: %v", err)
+		}
+
+		c := NewConfig()
+		if err := c.LoadSettings(path); err != nil {
+			t.Errorf("%s: failed to load the empty configuration file: %v", name, err)
+		} else if c.MaxDNSQueries != NewConfig().MaxDNSQueries {
+			t.Errorf("%s: the default settings were changed", name)
+		}
+	}
+}
+
+func TestLoadStructuredSettingsErrors(t *testing.T) {
+	content := `maximum_dns_queries: many
+resolver: [8.8.8.8]
+scope:
+  domains: owasp.org
+  ports: [80]
+data_sources:
+  Shodan:
+    apikey: abc123
+    ttl: [1]
+  CTLogs:
+    path: {file: a.json}
+`
+	path := filepath.Join(t.TempDir(), "config.yml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write the file: %v", err)
+	}
+
+	err := NewConfig().LoadSettings(path)
+	var serr *SettingsError
+	if !errors.As(err, &serr) {
+		t.Fatalf("expected a SettingsError, got %v", err)
+	}
+
+	expected := []string{
+		"line 1: maximum_dns_queries must be an integer",
+		"line 2: unknown key resolver (did you mean resolvers?)",
+		"line 5: unknown key scope.ports (did you mean port?)",
+		"line 8: data_sources.Shodan.apikey: credentials must be provided",
+		"line 9: data_sources.Shodan.ttl must be an integer",
+		"line 11: data_sources.CTLogs.path must be a value or a list of values",
+	}
+	if len(serr.Issues) != len(expected) {
+		t.Errorf("expected %d issues, got %d: %v", len(expected), len(serr.Issues), serr.Issues)
+	}
+	for _, e := range expected {
+		var found bool
+		for _, issue := range serr.Issues {
+			if strings.HasPrefix(issue, e) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("the issue %q was not reported: %v", e, serr.Issues)
+		}
+	}
+}
+
+func TestLoadExampleYAML(t *testing.T) {
+	if err := NewConfig().LoadSettings("../examples/config.yaml"); err != nil {
+		t.Errorf("the example YAML configuration failed to load: %v", err)
+	}
+}
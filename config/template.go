@@ -0,0 +1,210 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+const templateHeader = `# Amass configuration file generated by 'amass config init'.
+# Every setting is commented out and shows the default value or an example.
+# Remove the leading '#' from the section and the key to change a setting.
+
+`
+
+type templateKey struct {
+	name    string
+	comment string
+	value   string
+}
+
+type templateSection struct {
+	name    string
+	comment string
+	keys    []templateKey
+}
+
+// Template returns a configuration file in the INI format with all the settings commented out.
+// The default section and the graph database, data source and credential set sections are derived
+// from the struct tags, with the values provided by the default configuration.
+func Template() string {
+	var b strings.Builder
+
+	b.WriteString(templateHeader)
+	keys := []templateKey{{
+		name:    "mode",
+		comment: "Use passive for collecting results without DNS resolution, or active for also pulling TLS certificates and attempting zone transfers",
+		value:   "active",
+	}}
+	writeTemplateKeys(&b, append(keys, structTemplateKeys(NewConfig(), nil)...))
+
+	for _, sec := range templateSections() {
+		b.WriteString("\n# " + sec.comment + "\n")
+		b.WriteString("#[" + sec.name + "]\n")
+		writeTemplateKeys(&b, sec.keys)
+	}
+	return b.String()
+}
+
+func writeTemplateKeys(b *strings.Builder, keys []templateKey) {
+	for _, k := range keys {
+		if k.comment != "" {
+			b.WriteString("# " + k.comment + "\n")
+		}
+		b.WriteString(strings.TrimSpace("#"+k.name+" = "+k.value) + "\n")
+	}
+}
+
+// Returns the keys of the struct fields that have an ini tag, using the comment tags for the descriptions.
+// The examples replace the values of the struct for the keys provided.
+func structTemplateKeys(s interface{}, examples map[string]string) []templateKey {
+	var keys []templateKey
+
+	v := reflect.Indirect(reflect.ValueOf(s))
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Type().Field(i)
+
+		name := f.Tag.Get("ini")
+		if name == "" || name == "-" {
+			continue
+		}
+
+		value := fmt.Sprint(v.Field(i).Interface())
+		if example, found := examples[name]; found {
+			value = example
+		}
+		keys = append(keys, templateKey{
+			name:    name,
+			comment: f.Tag.Get("comment"),
+			value:   value,
+		})
+	}
+	return keys
+}
+
+func templateSections() []templateSection {
+	c := NewConfig()
+
+	return []templateSection{
+		{
+			name:    "resolvers",
+			comment: "DNS resolvers used globally by the amass package",
+			keys:    []templateKey{{name: "resolver", value: "8.8.8.8"}, {name: "resolver", value: "1.1.1.1"}},
+		},
+		{
+			name:    "scope",
+			comment: "The network infrastructure settings expand scope, not restrict the scope",
+			keys: []templateKey{
+				{name: "address", comment: "Single IP address or range (e.g. a.b.c.10-245)", value: "192.168.1.1"},
+				{name: "cidr", value: "192.168.1.0/24"},
+				{name: "asn", value: "26808"},
+				{name: "port", value: "80"},
+				{name: "port", value: "443"},
+			},
+		},
+		{
+			name:    "scope.domains",
+			comment: "Root domain names used in the enumeration",
+			keys: []templateKey{
+				{name: "domain", value: "example.com"},
+				{name: "domain", comment: "A sub-zone limits the scope to the names below it, and the names in the parent zones are only recorded", value: "shop.example.org"},
+			},
+		},
+		{
+			name:    "scope.hosts",
+			comment: "Exact host names in scope, without the names below them",
+			keys:    []templateKey{{name: "host", value: "api.example.net"}},
+		},
+		{
+			name:    "scope.blacklisted",
+			comment: "Subdomains that are out of scope",
+			keys: []templateKey{
+				{name: "subdomain", value: "internal.example.com"},
+				{name: "subdomain", comment: "The * wildcard matches any characters in the names", value: "dev-*.example.com"},
+			},
+		},
+		{
+			name:    "scope.excluded",
+			comment: "Names matching the regular expressions, and the netblocks and ASNs that are out of scope",
+			keys: []templateKey{
+				{name: "regex", value: `^staging[0-9]+\.example\.com$`},
+				{name: "cidr", value: "203.0.113.0/24"},
+				{name: "asn", value: "13335"},
+			},
+		},
+		{
+			name:    "graphdbs.postgres",
+			comment: "Graph databases used in addition to the local database",
+			keys: structTemplateKeys(&Database{}, map[string]string{
+				"url":      "postgres://127.0.0.1:5432",
+				"database": "amass",
+				"options":  "sslmode=disable",
+			}),
+		},
+		{
+			name:    "bruteforce",
+			comment: "Settings related to DNS name brute forcing",
+			keys: []templateKey{
+				{name: "enabled", value: fmt.Sprint(c.BruteForcing)},
+				{name: "recursive", value: fmt.Sprint(c.Recursive)},
+				{name: "minimum_for_recursive", comment: "Number of discoveries made in a subdomain before performing recursive brute forcing", value: fmt.Sprint(c.MinForRecursive)},
+				{name: "max_depth", comment: "The maximum number of labels added by recursive brute forcing, or zero for no limit", value: fmt.Sprint(c.MaxDepth)},
+				{name: "wordlist_file", comment: "Multiple lists can be used", value: "/usr/share/wordlists/all.txt"},
+			},
+		},
+		{
+			name:    "alterations",
+			comment: "Settings related to the permutation of resolved names",
+			keys: []templateKey{
+				{name: "enabled", value: fmt.Sprint(c.Alterations)},
+				{name: "edit_distance", comment: "The number of edit operations performed on a name during fuzzy label searching, or zero to disable the feature", value: fmt.Sprint(c.EditDistance)},
+				{name: "flip_words", value: fmt.Sprint(c.FlipWords)},
+				{name: "flip_numbers", value: fmt.Sprint(c.FlipNumbers)},
+				{name: "add_words", value: fmt.Sprint(c.AddWords)},
+				{name: "add_numbers", value: fmt.Sprint(c.AddNumbers)},
+				{name: "minimum_for_word_flip", value: fmt.Sprint(c.MinForWordFlip)},
+				{name: "wordlist_file", comment: "Multiple lists can be used", value: "/usr/share/wordlists/all.txt"},
+			},
+		},
+		{
+			name:    "data_sources",
+			comment: "Settings related to the data sources",
+			keys: []templateKey{
+				{name: "minimum_ttl", comment: "The minimum time-to-live, in minutes, applied to all data source caching", value: fmt.Sprint(c.MinimumTTL)},
+			},
+		},
+		{
+			name:    "data_sources.disabled",
+			comment: "Data sources that should be disabled",
+			keys:    []templateKey{{name: "data_source", value: "Ask"}},
+		},
+		{
+			name:    "data_sources.enabled",
+			comment: "Or the only data sources that should be used",
+			keys:    []templateKey{{name: "data_source", value: "crtsh"}},
+		},
+		{
+			name:    "data_sources.SOURCENAME",
+			comment: "The SOURCENAME must match the name in the data source implementation",
+			keys: structTemplateKeys(&DataSourceConfig{}, map[string]string{
+				"ttl":    "4320",
+				"plugin": "/path/to/executable",
+				"proxy":  "http://127.0.0.1:8080",
+			}),
+		},
+		{
+			name:    "data_sources.SOURCENAME.CredentialSetID",
+			comment: "A set of SOURCENAME credentials, and multiple sets will be randomly selected",
+			keys:    structTemplateKeys(&Credentials{}, nil),
+		},
+		{
+			name:    "profiles.NAME",
+			comment: "A named profile layered over the settings when selected using the -profile flag, and profiles.NAME.SECTION provides the keys of the other sections",
+			keys:    []templateKey{{name: "mode", value: "passive"}, {name: "timeout", value: "60"}},
+		},
+	}
+}
@@ -0,0 +1,257 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package datasrcs
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/OWASP/Amass/v3/datasrcs/scripting"
+	"github.com/caffix/netmap"
+	"github.com/google/uuid"
+)
+
+const cachedResponseType = "response"
+
+// CacheEntry is a data source response cached in the graph database.
+type CacheEntry struct {
+	Source    string    `json:"source"`
+	Query     string    `json:"query"`
+	Timestamp time.Time `json:"timestamp"`
+	Response  []byte    `json:"response"`
+}
+
+// Age returns the amount of time since the response was cached.
+func (e *CacheEntry) Age() time.Duration {
+	return time.Since(e.Timestamp)
+}
+
+// CacheFilter selects cache entries by data source, age and query.
+type CacheFilter struct {
+	// Names of the data sources, which match all sources when empty
+	Sources []string
+	// Only entries that are at least this old are selected when the value is positive
+	OlderThan time.Duration
+	// Only entries with queries (URLs) matching the regular expression are selected
+	Pattern *regexp.Regexp
+}
+
+// Match returns true if the entry is selected by the filter.
+func (f *CacheFilter) Match(e *CacheEntry) bool {
+	if f == nil {
+		return true
+	}
+	if !f.sourceMatch(e.Source) {
+		return false
+	}
+	if f.OlderThan > 0 && e.Age() < f.OlderThan {
+		return false
+	}
+	if f.Pattern != nil && !f.Pattern.MatchString(e.Query) {
+		return false
+	}
+	return true
+}
+
+func (f *CacheFilter) sourceMatch(source string) bool {
+	if f == nil || len(f.Sources) == 0 {
+		return true
+	}
+
+	for _, src := range f.Sources {
+		if strings.EqualFold(src, source) {
+			return true
+		}
+	}
+	return false
+}
+
+type cachedNode struct {
+	entry *CacheEntry
+	edge  *netmap.Edge
+}
+
+// CacheEntries returns the cached data source responses in the graph database selected by the filter.
+func CacheEntries(ctx context.Context, g *netmap.Graph, filter *CacheFilter) ([]*CacheEntry, error) {
+	nodes, err := cachedNodes(ctx, g, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []*CacheEntry
+	for _, n := range nodes {
+		entries = append(entries, n.entry)
+	}
+	return entries, nil
+}
+
+// PurgeCache removes the cached responses selected by the filter and returns the number of entries removed.
+func PurgeCache(ctx context.Context, g *netmap.Graph, filter *CacheFilter) (int, error) {
+	nodes, err := cachedNodes(ctx, g, filter)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	for _, n := range nodes {
+		if err := g.DeleteNode(ctx, n.edge.To); err != nil {
+			return count, fmt.Errorf("failed to remove the cached response for %s: %v", n.entry.Query, err)
+		}
+		// The edge from the data source node is not removed with the response node
+		_ = g.DeleteEdge(ctx, n.edge)
+		count++
+	}
+	return count, nil
+}
+
+// ExportCache writes the cached responses selected by the filter to the writer as JSON lines.
+func ExportCache(ctx context.Context, g *netmap.Graph, filter *CacheFilter, w io.Writer) (int, error) {
+	entries, err := CacheEntries(ctx, g, filter)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	enc := json.NewEncoder(w)
+	for _, e := range entries {
+		if err := enc.Encode(e); err != nil {
+			return count, fmt.Errorf("failed to export the cached response for %s: %v", e.Query, err)
+		}
+		count++
+	}
+	return count, nil
+}
+
+// ImportCache reads JSON lines written by ExportCache and caches the responses selected by the filter.
+// The imported responses keep their original timestamps, so the data sources honor their cache TTLs.
+func ImportCache(ctx context.Context, g *netmap.Graph, filter *CacheFilter, r io.Reader) (int, error) {
+	var count int
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	for scanner.Scan() {
+		var e CacheEntry
+
+		select {
+		case <-ctx.Done():
+			return count, ctx.Err()
+		default:
+		}
+
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			return count, fmt.Errorf("failed to decode the cache entry: %v", err)
+		}
+		if e.Source == "" || e.Query == "" || len(e.Response) == 0 || !filter.Match(&e) {
+			continue
+		}
+		if e.Timestamp.IsZero() {
+			e.Timestamp = time.Now()
+		}
+
+		if err := importCacheEntry(ctx, g, &e); err != nil {
+			return count, fmt.Errorf("failed to import the cached response for %s: %v", e.Query, err)
+		}
+		count++
+	}
+	return count, scanner.Err()
+}
+
+// Writes the response node with a unique identifier, since the graph database names the nodes
+// of new responses using the data source and the current second.
+func importCacheEntry(ctx context.Context, g *netmap.Graph, e *CacheEntry) error {
+	src, err := g.UpsertSource(ctx, e.Source)
+	if err != nil {
+		return err
+	}
+	// Remove previously cached responses for the same query
+	if edges, err := g.ReadOutEdges(ctx, src, e.Query); err == nil {
+		for _, edge := range edges {
+			if err := g.DeleteNode(ctx, edge.To); err == nil {
+				_ = g.DeleteEdge(ctx, edge)
+			}
+		}
+	}
+
+	node, err := g.UpsertNode(ctx, e.Source+"-response-"+uuid.New().String(), cachedResponseType)
+	if err != nil {
+		return err
+	}
+	if err := g.UpsertProperty(ctx, node, "timestamp", e.Timestamp.Format(time.RFC3339Nano)); err != nil {
+		return err
+	}
+	if err := g.UpsertProperty(ctx, node, "response", string(e.Response)); err != nil {
+		return err
+	}
+	return g.UpsertEdge(ctx, &netmap.Edge{
+		Predicate: e.Query,
+		From:      src,
+		To:        node,
+	})
+}
+
+func cachedNodes(ctx context.Context, g *netmap.Graph, filter *CacheFilter) ([]*cachedNode, error) {
+	srcs, err := g.AllNodesOfType(ctx, netmap.TypeSource)
+	if err != nil {
+		// The graph does not contain any data sources
+		return nil, nil
+	}
+
+	var results []*cachedNode
+	for _, src := range srcs {
+		source := g.NodeToID(src)
+		if !filter.sourceMatch(source) {
+			continue
+		}
+
+		edges, err := g.ReadOutEdges(ctx, src)
+		if err != nil {
+			continue
+		}
+
+		for _, edge := range edges {
+			if _, err := g.ReadNode(ctx, g.NodeToID(edge.To), cachedResponseType); err != nil {
+				continue
+			}
+
+			e := &CacheEntry{
+				Source: source,
+				Query:  edge.Predicate,
+			}
+			if p, err := g.ReadProperties(ctx, edge.To, "timestamp"); err == nil && len(p) > 0 {
+				if ts, ok := scripting.CacheTimestamp(p[0].Value); ok {
+					e.Timestamp = ts
+				}
+			}
+			if p, err := g.ReadProperties(ctx, edge.To, "response"); err == nil && len(p) > 0 {
+				if v, ok := p[0].Value.Native().(string); ok {
+					e.Response = []byte(v)
+				}
+			}
+
+			if filter.Match(e) {
+				results = append(results, &cachedNode{entry: e, edge: edge})
+			}
+		}
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].entry.Source != results[j].entry.Source {
+			return results[i].entry.Source < results[j].entry.Source
+		}
+		return results[i].entry.Query < results[j].entry.Query
+	})
+	return results, nil
+}
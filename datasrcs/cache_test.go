@@ -0,0 +1,130 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package datasrcs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/caffix/netmap"
+)
+
+func TestCacheManagement(t *testing.T) {
+	ctx := context.Background()
+	g := netmap.NewGraph(netmap.NewCayleyGraphMemory())
+	defer g.Close()
+
+	responses := map[string]string{
+		"https://api.first.com/owasp.org":  "first",
+		"https://api.second.com/owasp.org": "second",
+		"https://api.second.com/amass.org": "third",
+	}
+
+	var input bytes.Buffer
+	enc := json.NewEncoder(&input)
+	for url, resp := range responses {
+		src := "First"
+		if strings.Contains(url, "second") {
+			src = "Second"
+		}
+		_ = enc.Encode(&CacheEntry{Source: src, Query: url, Response: []byte(resp)})
+	}
+	if num, err := ImportCache(ctx, g, nil, &input); err != nil || num != 3 {
+		t.Fatalf("ImportCache returned %d entries and error %v", num, err)
+	}
+
+	entries, err := CacheEntries(ctx, g, nil)
+	if err != nil || len(entries) != 3 {
+		t.Fatalf("CacheEntries returned %d entries and error %v", len(entries), err)
+	}
+	for _, e := range entries {
+		if string(e.Response) != responses[e.Query] || e.Timestamp.IsZero() {
+			t.Errorf("CacheEntries returned an unexpected entry: %v", e)
+		}
+	}
+
+	filters := []struct {
+		filter   *CacheFilter
+		expected int
+	}{
+		{&CacheFilter{Sources: []string{"second"}}, 2},
+		{&CacheFilter{Pattern: regexp.MustCompile(`owasp\.org$`)}, 2},
+		{&CacheFilter{Sources: []string{"Second"}, Pattern: regexp.MustCompile(`amass`)}, 1},
+		{&CacheFilter{OlderThan: time.Hour}, 0},
+	}
+	for _, f := range filters {
+		if entries, _ := CacheEntries(ctx, g, f.filter); len(entries) != f.expected {
+			t.Errorf("the filter %v selected %d entries, expected %d", f.filter, len(entries), f.expected)
+		}
+	}
+
+	var buf bytes.Buffer
+	if num, err := ExportCache(ctx, g, &CacheFilter{Sources: []string{"Second"}}, &buf); err != nil || num != 2 {
+		t.Errorf("ExportCache returned %d entries and error %v", num, err)
+	}
+
+	if num, err := PurgeCache(ctx, g, &CacheFilter{Sources: []string{"Second"}}); err != nil || num != 2 {
+		t.Errorf("PurgeCache returned %d entries and error %v", num, err)
+	}
+	if entries, _ := CacheEntries(ctx, g, nil); len(entries) != 1 || entries[0].Source != "First" {
+		t.Errorf("PurgeCache failed to remove the selected entries: %v", entries)
+	}
+	if _, err := g.GetSourceData(ctx, "Second", "https://api.second.com/owasp.org", 60); err == nil {
+		t.Error("the purged response was still returned by the graph database")
+	}
+
+	if num, err := ImportCache(ctx, g, nil, &buf); err != nil || num != 2 {
+		t.Errorf("ImportCache returned %d entries and error %v", num, err)
+	}
+	if entries, _ := CacheEntries(ctx, g, &CacheFilter{Sources: []string{"Second"}}); len(entries) != 2 ||
+		string(entries[0].Response) != "third" || string(entries[1].Response) != "second" {
+		t.Errorf("the imported responses were not available: %v", entries)
+	}
+}
+
+func TestImportCacheTimestamps(t *testing.T) {
+	ctx := context.Background()
+	g := netmap.NewGraph(netmap.NewCayleyGraphMemory())
+	defer g.Close()
+
+	var input bytes.Buffer
+	enc := json.NewEncoder(&input)
+	ts := time.Now().Add(-48 * time.Hour).Truncate(time.Second)
+	for i := 0; i < 5; i++ {
+		_ = enc.Encode(&CacheEntry{
+			Source:    "First",
+			Query:     fmt.Sprintf("https://api.first.com/%d.owasp.org", i),
+			Timestamp: ts,
+			Response:  []byte(fmt.Sprintf("response %d", i)),
+		})
+	}
+
+	start := time.Now()
+	if num, err := ImportCache(ctx, g, nil, &input); err != nil || num != 5 {
+		t.Fatalf("ImportCache returned %d entries and error %v", num, err)
+	}
+	if time.Since(start) > time.Second {
+		t.Errorf("ImportCache took %v to import the responses of a single data source", time.Since(start))
+	}
+
+	entries, err := CacheEntries(ctx, g, &CacheFilter{OlderThan: 24 * time.Hour})
+	if err != nil || len(entries) != 5 {
+		t.Fatalf("CacheEntries returned %d entries and error %v", len(entries), err)
+	}
+	for i, e := range entries {
+		if !e.Timestamp.Equal(ts) {
+			t.Errorf("the imported response for %s has the timestamp %v, expected %v", e.Query, e.Timestamp, ts)
+		}
+		if want := fmt.Sprintf("response %d", i); string(e.Response) != want {
+			t.Errorf("the imported response for %s is %s, expected %s", e.Query, e.Response, want)
+		}
+	}
+}
@@ -0,0 +1,338 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package datasrcs
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/OWASP/Amass/v3/datasrcs/scripting"
+	"github.com/OWASP/Amass/v3/net/http"
+	"github.com/OWASP/Amass/v3/requests"
+	"github.com/OWASP/Amass/v3/systems"
+	"github.com/caffix/service"
+	"github.com/caffix/stringset"
+)
+
+const (
+	// The maximum number of entries requested from the CT log server at once
+	ctEntriesBatch = 256
+	// The default number of the newest log entries read from the CT log server
+	ctDefaultMaxEntries = 100000
+	// RFC 6962 log entry types
+	ctX509Entry    = 0
+	ctPrecertEntry = 1
+)
+
+// CTLogs is the Service that extracts names from Certificate Transparency log entries, read
+// from local RFC 6962 get-entries dump files or a CT log server, such as an internal mirror.
+type CTLogs struct {
+	service.BaseService
+
+	SourceType string
+	sys        systems.System
+	once       sync.Once
+	paths      []string
+	url        string
+	start      int64
+	max        int64
+}
+
+type ctEntries struct {
+	Entries []*ctEntry `json:"entries"`
+}
+
+type ctEntry struct {
+	LeafInput []byte `json:"leaf_input"`
+	ExtraData []byte `json:"extra_data"`
+}
+
+type ctTreeHead struct {
+	TreeSize int64 `json:"tree_size"`
+}
+
+// NewCTLogs returns the object initialized, but not yet started.
+func NewCTLogs(sys systems.System) *CTLogs {
+	c := &CTLogs{
+		SourceType: requests.CERT,
+		sys:        sys,
+		start:      -1,
+		max:        ctDefaultMaxEntries,
+	}
+
+	go c.requests()
+	c.BaseService = *service.NewBaseService(c, "CTLogs")
+	return c
+}
+
+// Description implements the Service interface.
+func (c *CTLogs) Description() string {
+	return c.SourceType
+}
+
+// Metadata implements the MetadataSource interface.
+func (c *CTLogs) Metadata() *scripting.Metadata {
+	return &scripting.Metadata{
+		Homepage:   "https://www.rfc-editor.org/rfc/rfc6962",
+		Categories: []string{requests.CERT},
+	}
+}
+
+// OnStart implements the Service interface.
+func (c *CTLogs) OnStart() error {
+	dsc := c.sys.Config().GetDataSourceConfig(c.String())
+	if dsc == nil {
+		return fmt.Errorf("%s: the data source configuration is not available", c.String())
+	}
+
+	c.paths = dsc.OptionValues("path")
+	c.url = strings.TrimSpace(dsc.Option("url"))
+	if len(c.paths) == 0 && c.url == "" {
+		return fmt.Errorf("%s: neither a path nor a URL was configured", c.String())
+	}
+	if c.url != "" && !strings.HasSuffix(c.url, "/") {
+		c.url += "/"
+	}
+
+	if v := dsc.Option("start"); v != "" {
+		start, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || start < 0 {
+			return fmt.Errorf("%s: the start setting must be a log entry index", c.String())
+		}
+		c.start = start
+	}
+	if v := dsc.Option("max_entries"); v != "" {
+		max, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || max <= 0 {
+			return fmt.Errorf("%s: the max_entries setting must be a positive number", c.String())
+		}
+		c.max = max
+	}
+	return nil
+}
+
+func (c *CTLogs) requests() {
+	// The scan is cancelled when the service is stopped
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	for {
+		select {
+		case <-c.Done():
+			return
+		case in := <-c.Input():
+			switch req := in.(type) {
+			case *requests.DNSRequest:
+				if req.Domain != "" {
+					// The log entries are only read once for all the domains in scope,
+					// while the requests continue to be drained from the input
+					c.once.Do(func() { go c.scan(ctx) })
+				}
+			}
+		}
+	}
+}
+
+func (c *CTLogs) scan(ctx context.Context) {
+	cfg := c.sys.Config()
+	filter := stringset.New()
+	defer filter.Close()
+
+	for _, pattern := range c.paths {
+		files, err := filepath.Glob(pattern)
+		if err != nil {
+			cfg.Log.Printf("%s: %s: %v", c.String(), pattern, err)
+			continue
+		}
+
+		for _, file := range files {
+			if err := c.readDumpFile(ctx, file, filter); err != nil {
+				cfg.Log.Printf("%s: %s: %v", c.String(), file, err)
+			}
+		}
+	}
+
+	if c.url != "" {
+		if err := c.readLog(ctx, filter); err != nil {
+			cfg.Log.Printf("%s: %s: %v", c.String(), c.url, err)
+		}
+	}
+}
+
+// Reads a file containing one or more get-entries responses.
+func (c *CTLogs) readDumpFile(ctx context.Context, path string, filter *stringset.Set) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	dec := json.NewDecoder(f)
+	for {
+		var resp ctEntries
+
+		if err := dec.Decode(&resp); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		for _, entry := range resp.Entries {
+			select {
+			case <-ctx.Done():
+				return nil
+			default:
+			}
+			c.processEntry(ctx, entry, filter)
+		}
+	}
+}
+
+// Reads the log entries from the CT log server using the get-sth and get-entries methods.
+func (c *CTLogs) readLog(ctx context.Context, filter *stringset.Set) error {
+	cfg := c.sys.Config()
+	proxy := cfg.DataSourceProxy(c.String())
+
+	resp, err := http.RequestWebPage(ctx, &http.Request{URL: c.url + "ct/v1/get-sth", Proxy: proxy})
+	if err != nil {
+		return err
+	} else if resp.StatusCode != 200 {
+		return fmt.Errorf("the get-sth request returned: %s", resp.Status)
+	}
+
+	var sth ctTreeHead
+	if err := json.Unmarshal([]byte(resp.Body), &sth); err != nil {
+		return err
+	}
+
+	start := c.start
+	if start < 0 {
+		start = sth.TreeSize - c.max
+	}
+	if start < 0 {
+		start = 0
+	}
+	end := sth.TreeSize
+	if end > start+c.max {
+		end = start + c.max
+	}
+
+	for start < end {
+		select {
+		case <-ctx.Done():
+			return nil
+		default:
+		}
+
+		last := start + ctEntriesBatch - 1
+		if last >= end {
+			last = end - 1
+		}
+
+		c.CheckRateLimit()
+		u := fmt.Sprintf("%sct/v1/get-entries?start=%d&end=%d", c.url, start, last)
+		resp, err := http.RequestWebPage(ctx, &http.Request{URL: u, Proxy: proxy})
+		if err != nil {
+			return err
+		} else if resp.StatusCode != 200 {
+			return fmt.Errorf("the get-entries request returned: %s", resp.Status)
+		}
+
+		var entries ctEntries
+		if err := json.Unmarshal([]byte(resp.Body), &entries); err != nil {
+			return err
+		}
+		// The log server can return fewer entries than requested
+		if len(entries.Entries) == 0 {
+			return errors.New("the get-entries request returned no entries")
+		}
+
+		for _, entry := range entries.Entries {
+			c.processEntry(ctx, entry, filter)
+		}
+		start += int64(len(entries.Entries))
+	}
+	return nil
+}
+
+func (c *CTLogs) processEntry(ctx context.Context, entry *ctEntry, filter *stringset.Set) {
+	cert, err := ctEntryCertificate(entry)
+	if err != nil {
+		return
+	}
+
+	cfg := c.sys.Config()
+	for _, name := range http.NamesFromCert(cert) {
+		n := http.CleanName(name)
+		if n == "" || filter.Has(n) {
+			continue
+		}
+		filter.Insert(n)
+
+		if domain := cfg.WhichDomain(n); domain != "" {
+			select {
+			case <-ctx.Done():
+				return
+			case <-c.Done():
+				return
+			case c.Output() <- &requests.DNSRequest{
+				Name:   n,
+				Domain: domain,
+				Tag:    c.SourceType,
+				Source: c.String(),
+			}:
+			}
+		}
+	}
+}
+
+// Decodes the leaf certificate or precertificate of the RFC 6962 log entry.
+func ctEntryCertificate(entry *ctEntry) (*x509.Certificate, error) {
+	leaf := entry.LeafInput
+	// MerkleTreeLeaf: version, leaf type, timestamp and the log entry type
+	if len(leaf) < 12 || leaf[0] != 0 || leaf[1] != 0 {
+		return nil, errors.New("the leaf input is not a v1 timestamped entry")
+	}
+
+	var data []byte
+	switch binary.BigEndian.Uint16(leaf[10:12]) {
+	case ctX509Entry:
+		data = leaf[12:]
+	case ctPrecertEntry:
+		// The PrecertChainEntry in the extra data begins with the complete precertificate
+		data = entry.ExtraData
+	default:
+		return nil, errors.New("the log entry type is not supported")
+	}
+
+	der, err := ctOpaque24(data)
+	if err != nil {
+		return nil, err
+	}
+	return x509.ParseCertificate(der)
+}
+
+// Returns the opaque value prefixed by a 24-bit length.
+func ctOpaque24(data []byte) ([]byte, error) {
+	if len(data) < 3 {
+		return nil, errors.New("the certificate length is missing")
+	}
+
+	l := int(data[0])<<16 | int(data[1])<<8 | int(data[2])
+	if l == 0 || len(data) < 3+l {
+		return nil, errors.New("the certificate length is invalid")
+	}
+	return data[3 : 3+l], nil
+}
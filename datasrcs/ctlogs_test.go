@@ -0,0 +1,227 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package datasrcs
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/OWASP/Amass/v3/config"
+	"github.com/OWASP/Amass/v3/requests"
+	"github.com/caffix/stringset"
+)
+
+func ctTestCertificate(t *testing.T, names ...string) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate the key: %v", err)
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: names[0]},
+		DNSNames:     names,
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create the certificate: %v", err)
+	}
+	return der
+}
+
+func ctOpaque(data []byte) []byte {
+	l := len(data)
+	return append([]byte{byte(l >> 16), byte(l >> 8), byte(l)}, data...)
+}
+
+func ctTestEntry(der []byte, precert bool) *ctEntry {
+	leaf := []byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0}
+
+	if !precert {
+		leaf = append(leaf, 0, ctX509Entry)
+		leaf = append(leaf, ctOpaque(der)...)
+		return &ctEntry{LeafInput: append(leaf, 0, 0)}
+	}
+
+	leaf = append(leaf, 0, ctPrecertEntry)
+	leaf = append(leaf, make([]byte, 32)...)
+	leaf = append(leaf, ctOpaque([]byte("tbs"))...)
+	return &ctEntry{
+		LeafInput: append(leaf, 0, 0),
+		// The precertificate followed by an empty chain
+		ExtraData: append(ctOpaque(der), 0, 0, 0),
+	}
+}
+
+func TestCTLogs(t *testing.T) {
+	dumped := []*ctEntry{
+		ctTestEntry(ctTestCertificate(t, "www.owasp.org", "www.out-of-scope.com"), false),
+		ctTestEntry(ctTestCertificate(t, "pre.owasp.org"), true),
+	}
+	served := []*ctEntry{
+		ctTestEntry(ctTestCertificate(t, "www.owasp.org"), false),
+		ctTestEntry(ctTestCertificate(t, "api.owasp.org"), false),
+		ctTestEntry(ctTestCertificate(t, "*.dev.owasp.org"), true),
+	}
+
+	path := filepath.Join(t.TempDir(), "entries.json")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("failed to create the dump file: %v", err)
+	}
+	enc := json.NewEncoder(f)
+	// Each response in the dump file provides one entry
+	for _, entry := range dumped {
+		_ = enc.Encode(&ctEntries{Entries: []*ctEntry{entry}})
+	}
+	f.Close()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/log/ct/v1/get-sth":
+			fmt.Fprintf(w, `{"tree_size":%d}`, len(served))
+		case "/log/ct/v1/get-entries":
+			start, _ := strconv.Atoi(r.URL.Query().Get("start"))
+			end, _ := strconv.Atoi(r.URL.Query().Get("end"))
+			// Return at most two entries to exercise the paging
+			if end-start > 1 {
+				end = start + 1
+			}
+			_ = json.NewEncoder(w).Encode(&ctEntries{Entries: served[start : end+1]})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	cfg := config.NewConfig()
+	cfg.AddDomain("owasp.org")
+	dsc := cfg.GetDataSourceConfig("CTLogs")
+	dsc.SetOption("path", path)
+	dsc.SetOption("url", ts.URL+"/log")
+
+	sys := pluginMockSystem(cfg)
+	defer func() { _ = sys.Shutdown() }()
+
+	c := NewCTLogs(sys)
+	if err := c.Start(); err != nil {
+		t.Fatalf("failed to start the data source: %v", err)
+	}
+	defer func() { _ = c.Stop() }()
+
+	c.Input() <- &requests.DNSRequest{Name: "owasp.org", Domain: "owasp.org"}
+
+	want := stringset.New("www.owasp.org", "pre.owasp.org", "api.owasp.org", "dev.owasp.org")
+	defer want.Close()
+	got := stringset.New()
+	defer got.Close()
+
+	timer := time.NewTimer(10 * time.Second)
+	defer timer.Stop()
+loop:
+	for got.Len() < want.Len() {
+		select {
+		case <-timer.C:
+			break loop
+		case out := <-c.Output():
+			req := out.(*requests.DNSRequest)
+			if req.Domain != "owasp.org" || req.Tag != requests.CERT || req.Source != "CTLogs" {
+				t.Errorf("the data source returned an unexpected request: %v", req)
+			}
+			if got.Has(req.Name) {
+				t.Errorf("the data source returned %s more than once", req.Name)
+			}
+			got.Insert(req.Name)
+		}
+	}
+
+	want.Subtract(got)
+	if want.Len() != 0 {
+		t.Errorf("the data source failed to return the names: %v", want.Slice())
+	}
+	if got.Has("www.out-of-scope.com") {
+		t.Errorf("the data source returned an out of scope name")
+	}
+}
+
+func TestCTLogsStartError(t *testing.T) {
+	cfg := config.NewConfig()
+	sys := pluginMockSystem(cfg)
+	defer func() { _ = sys.Shutdown() }()
+
+	if err := NewCTLogs(sys).Start(); err == nil {
+		t.Errorf("the data source started without a path or URL")
+	}
+
+	dsc := cfg.GetDataSourceConfig("CTLogs")
+	dsc.SetOption("url", "http://127.0.0.1/")
+	dsc.SetOption("max_entries", "-5")
+	if err := NewCTLogs(sys).Start(); err == nil {
+		t.Errorf("the data source started with an invalid max_entries setting")
+	}
+}
+
+func TestCTLogsStop(t *testing.T) {
+	received := make(chan struct{})
+	cancelled := make(chan struct{})
+	// The log server does not respond until the request is cancelled
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(received)
+		<-r.Context().Done()
+		close(cancelled)
+	}))
+	defer ts.Close()
+
+	cfg := config.NewConfig()
+	cfg.AddDomain("owasp.org")
+	cfg.AddDomain("owasp.com")
+	cfg.GetDataSourceConfig("CTLogs").SetOption("url", ts.URL)
+
+	sys := pluginMockSystem(cfg)
+	defer func() { _ = sys.Shutdown() }()
+
+	c := NewCTLogs(sys)
+	if err := c.Start(); err != nil {
+		t.Fatalf("failed to start the data source: %v", err)
+	}
+
+	timer := time.NewTimer(5 * time.Second)
+	defer timer.Stop()
+	for _, domain := range []string{"owasp.org", "owasp.com"} {
+		select {
+		case <-timer.C:
+			t.Fatalf("the data source stopped reading the input during the scan")
+		case c.Input() <- &requests.DNSRequest{Name: domain, Domain: domain}:
+		}
+	}
+
+	select {
+	case <-timer.C:
+		t.Fatal("the data source did not request the log entries")
+	case <-received:
+	}
+	_ = c.Stop()
+	select {
+	case <-timer.C:
+		t.Error("the scan was not cancelled when the data source was stopped")
+	case <-cancelled:
+	}
+}
@@ -0,0 +1,390 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package datasrcs
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/OWASP/Amass/v3/datasrcs/scripting"
+	amassdns "github.com/OWASP/Amass/v3/net/dns"
+	"github.com/OWASP/Amass/v3/net/http"
+	"github.com/OWASP/Amass/v3/requests"
+	"github.com/OWASP/Amass/v3/systems"
+	"github.com/caffix/service"
+	"github.com/caffix/stringset"
+)
+
+const (
+	// The default size of the largest file or blob scanned for names
+	localDefaultMaxFileSize = 10 * 1024 * 1024
+	// The maximum number of locations recorded for each name
+	localMaxProvenance = 10
+	// The number of bytes checked for the NUL character to detect binary content
+	localBinaryCheckSize = 8000
+)
+
+// LocalFiles is the Service that extracts names from the files in local directories, such as
+// source code and configuration dumps, and the history of cloned git repositories.
+type LocalFiles struct {
+	service.BaseService
+
+	SourceType string
+	sys        systems.System
+	subre      *regexp.Regexp
+	once       sync.Once
+	paths      []string
+	history    bool
+	maxSize    int64
+	lock       sync.Mutex
+	provenance map[string][]string
+}
+
+// NewLocalFiles returns the object initialized, but not yet started.
+func NewLocalFiles(sys systems.System) *LocalFiles {
+	l := &LocalFiles{
+		SourceType: requests.EXTERNAL,
+		sys:        sys,
+		subre:      amassdns.AnySubdomainRegex(),
+		history:    true,
+		maxSize:    localDefaultMaxFileSize,
+		provenance: make(map[string][]string),
+	}
+
+	go l.requests()
+	l.BaseService = *service.NewBaseService(l, "LocalFiles")
+	return l
+}
+
+// Description implements the Service interface.
+func (l *LocalFiles) Description() string {
+	return l.SourceType
+}
+
+// Metadata implements the MetadataSource interface.
+func (l *LocalFiles) Metadata() *scripting.Metadata {
+	return &scripting.Metadata{
+		Categories: []string{requests.EXTERNAL},
+	}
+}
+
+// OnStart implements the Service interface.
+func (l *LocalFiles) OnStart() error {
+	dsc := l.sys.Config().GetDataSourceConfig(l.String())
+	if dsc == nil {
+		return fmt.Errorf("%s: the data source configuration is not available", l.String())
+	}
+
+	for _, path := range dsc.OptionValues("path") {
+		if path = strings.TrimSpace(path); path != "" {
+			l.paths = append(l.paths, path)
+		}
+	}
+	if len(l.paths) == 0 {
+		return fmt.Errorf("%s: no path was configured", l.String())
+	}
+
+	if v := dsc.Option("git_history"); v != "" {
+		history, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("%s: the git_history setting must be true or false", l.String())
+		}
+		l.history = history
+	}
+	if v := dsc.Option("max_file_size"); v != "" {
+		max, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || max <= 0 {
+			return fmt.Errorf("%s: the max_file_size setting must be a positive number", l.String())
+		}
+		l.maxSize = max
+	}
+	return nil
+}
+
+// Provenance implements the ProvenanceSource interface.
+func (l *LocalFiles) Provenance() map[string][]string {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	results := make(map[string][]string, len(l.provenance))
+	for name, locations := range l.provenance {
+		results[name] = append([]string(nil), locations...)
+	}
+	return results
+}
+
+func (l *LocalFiles) requests() {
+	// The scan is cancelled when the service is stopped
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	for {
+		select {
+		case <-l.Done():
+			return
+		case in := <-l.Input():
+			switch req := in.(type) {
+			case *requests.DNSRequest:
+				if req.Domain != "" {
+					// The files are only scanned once for all the domains in scope,
+					// while the requests continue to be drained from the input
+					l.once.Do(func() { go l.scan(ctx) })
+				}
+			}
+		}
+	}
+}
+
+func (l *LocalFiles) scan(ctx context.Context) {
+	cfg := l.sys.Config()
+	filter := stringset.New()
+	defer filter.Close()
+
+	for _, root := range l.paths {
+		if err := l.walk(ctx, root, filter); err != nil {
+			cfg.Log.Printf("%s: %s: %v", l.String(), root, err)
+		}
+		if !l.history {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(root, ".git")); err != nil {
+			continue
+		}
+		if err := l.scanHistory(ctx, root, filter); err != nil {
+			cfg.Log.Printf("%s: %s: %v", l.String(), root, err)
+		}
+	}
+}
+
+// Walks the directory tree, or the single file, and extracts the names from each text file.
+func (l *LocalFiles) walk(ctx context.Context, root string, filter *stringset.Set) error {
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		select {
+		case <-ctx.Done():
+			return filepath.SkipDir
+		default:
+		}
+
+		if err != nil {
+			// Continue the walk after unreadable files and directories
+			return nil
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		if info, err := d.Info(); err != nil || info.Size() > l.maxSize {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+
+		l.extract(ctx, data, path, filter)
+		return nil
+	})
+}
+
+// Extracts the names from every blob committed to the git repository, since names can
+// remain in the history long after being removed from the working tree.
+func (l *LocalFiles) scanHistory(ctx context.Context, root string, filter *stringset.Set) error {
+	blobs, err := l.historyBlobs(ctx, root)
+	if err != nil || len(blobs) == 0 {
+		return err
+	}
+
+	cmd := exec.CommandContext(ctx, "git", "-C", root, "cat-file", "--batch")
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	go func() {
+		defer stdin.Close()
+
+		for _, b := range blobs {
+			if _, err := fmt.Fprintln(stdin, b.hash); err != nil {
+				return
+			}
+		}
+	}()
+
+	r := bufio.NewReader(stdout)
+	for _, b := range blobs {
+		select {
+		case <-ctx.Done():
+			_ = cmd.Process.Kill()
+			return nil
+		default:
+		}
+		// The header line is the object hash, type and size
+		header, err := r.ReadString('\n')
+		if err != nil {
+			break
+		}
+
+		fields := strings.Fields(header)
+		if len(fields) != 3 {
+			// The object is missing from the repository
+			continue
+		}
+		size, err := strconv.ParseInt(fields[2], 10, 64)
+		if err != nil {
+			break
+		}
+
+		if fields[1] != "blob" || size > l.maxSize {
+			if _, err := io.CopyN(io.Discard, r, size+1); err != nil {
+				break
+			}
+			continue
+		}
+
+		data := make([]byte, size+1)
+		if _, err := io.ReadFull(r, data); err != nil {
+			break
+		}
+		l.extract(ctx, data[:size], fmt.Sprintf("%s@%s", filepath.Join(root, b.path), b.commit), filter)
+	}
+
+	_, _ = io.Copy(io.Discard, r)
+	return cmd.Wait()
+}
+
+type historyBlob struct {
+	hash   string
+	path   string
+	commit string
+}
+
+// Returns the blobs added or modified by the commits of every branch and tag, along with
+// the path and the abbreviated hash of the commit that first introduced the content.
+func (l *LocalFiles) historyBlobs(ctx context.Context, root string) ([]*historyBlob, error) {
+	cmd := exec.CommandContext(ctx, "git", "-C", root, "log", "--all", "--reverse",
+		"--format=commit %H", "--raw", "--no-abbrev", "--no-renames", "--no-color")
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+
+	var commit string
+	var blobs []*historyBlob
+	seen := make(map[string]struct{})
+	for _, line := range bytes.Split(out, []byte("\n")) {
+		s := string(line)
+
+		if strings.HasPrefix(s, "commit ") {
+			commit = strings.TrimPrefix(s, "commit ")
+			if len(commit) > 12 {
+				commit = commit[:12]
+			}
+			continue
+		}
+		// The raw output lines are ":<old mode> <new mode> <old hash> <new hash> <status>\t<path>"
+		if !strings.HasPrefix(s, ":") {
+			continue
+		}
+		parts := strings.SplitN(s, "\t", 2)
+		fields := strings.Fields(parts[0])
+		if len(parts) != 2 || len(fields) != 5 {
+			continue
+		}
+
+		hash := fields[3]
+		if strings.Trim(hash, "0") == "" {
+			// The file was deleted by the commit
+			continue
+		}
+		if _, found := seen[hash]; found {
+			continue
+		}
+		seen[hash] = struct{}{}
+
+		blobs = append(blobs, &historyBlob{
+			hash:   hash,
+			path:   parts[1],
+			commit: commit,
+		})
+	}
+	return blobs, nil
+}
+
+func (l *LocalFiles) extract(ctx context.Context, data []byte, location string, filter *stringset.Set) {
+	check := data
+	if len(check) > localBinaryCheckSize {
+		check = check[:localBinaryCheckSize]
+	}
+	if bytes.IndexByte(check, 0) != -1 {
+		return
+	}
+
+	cfg := l.sys.Config()
+	for _, match := range l.subre.FindAll(data, -1) {
+		name := http.CleanName(string(match))
+		if name == "" || !cfg.IsDomainInScope(name) {
+			continue
+		}
+
+		l.addProvenance(name, location)
+		if filter.Has(name) {
+			continue
+		}
+		filter.Insert(name)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-l.Done():
+			return
+		case l.Output() <- &requests.DNSRequest{
+			Name:   name,
+			Domain: cfg.WhichDomain(name),
+			Tag:    l.SourceType,
+			Source: l.String(),
+		}:
+		}
+	}
+}
+
+func (l *LocalFiles) addProvenance(name, location string) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	locations := l.provenance[name]
+	if len(locations) >= localMaxProvenance {
+		return
+	}
+	for _, loc := range locations {
+		if loc == location {
+			return
+		}
+	}
+	l.provenance[name] = append(locations, location)
+}
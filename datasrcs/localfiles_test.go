@@ -0,0 +1,164 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package datasrcs
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/OWASP/Amass/v3/config"
+	"github.com/OWASP/Amass/v3/requests"
+	"github.com/caffix/stringset"
+)
+
+func localGit(t *testing.T, dir string, args ...string) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=amass", "GIT_AUTHOR_EMAIL=amass@owasp.org",
+		"GIT_COMMITTER_NAME=amass", "GIT_COMMITTER_EMAIL=amass@owasp.org")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %s failed: %v: %s", strings.Join(args, " "), err, out)
+	}
+}
+
+func TestLocalFiles(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("the git command is not available")
+	}
+
+	dir := t.TempDir()
+	write := func(name, content string) {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create the directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	localGit(t, dir, "init", "-q")
+	write("config/app.yml", "api_host: old.owasp.org\n")
+	localGit(t, dir, "add", "-A")
+	localGit(t, dir, "commit", "-q", "-m", "initial")
+	// The name only remains in the repository history
+	write("config/app.yml", "api_host: api.owasp.org\nexternal: www.example.com\n")
+	write("src/main.js", `fetch("https://www.owasp.org/index.html")`)
+	write("bin/blob.dat", "\x00\x01internal.owasp.org")
+	localGit(t, dir, "add", "-A")
+	localGit(t, dir, "commit", "-q", "-m", "update")
+
+	cfg := config.NewConfig()
+	cfg.AddDomain("owasp.org")
+	cfg.GetDataSourceConfig("LocalFiles").SetOption("path", dir)
+
+	sys := pluginMockSystem(cfg)
+	defer func() { _ = sys.Shutdown() }()
+
+	l := NewLocalFiles(sys)
+	if err := l.Start(); err != nil {
+		t.Fatalf("failed to start the data source: %v", err)
+	}
+	defer func() { _ = l.Stop() }()
+
+	l.Input() <- &requests.DNSRequest{Name: "owasp.org", Domain: "owasp.org"}
+
+	want := stringset.New("api.owasp.org", "www.owasp.org", "old.owasp.org")
+	defer want.Close()
+	got := stringset.New()
+	defer got.Close()
+
+	timer := time.NewTimer(10 * time.Second)
+	defer timer.Stop()
+loop:
+	for got.Len() < want.Len() {
+		select {
+		case <-timer.C:
+			break loop
+		case out := <-l.Output():
+			req := out.(*requests.DNSRequest)
+			if req.Domain != "owasp.org" || req.Source != "LocalFiles" {
+				t.Errorf("the data source returned an unexpected request: %v", req)
+			}
+			got.Insert(req.Name)
+		}
+	}
+
+	want.Subtract(got)
+	if want.Len() != 0 {
+		t.Errorf("the data source failed to return the names: %v", want.Slice())
+	}
+	if got.Has("www.example.com") || got.Has("internal.owasp.org") {
+		t.Errorf("the data source returned names from out of scope or binary content: %v", got.Slice())
+	}
+
+	prov := l.Provenance()
+	if locs := prov["www.owasp.org"]; len(locs) == 0 || locs[0] != filepath.Join(dir, "src", "main.js") {
+		t.Errorf("unexpected provenance for www.owasp.org: %v", locs)
+	}
+	if locs := prov["old.owasp.org"]; len(locs) != 1 || !strings.HasPrefix(locs[0], filepath.Join(dir, "config", "app.yml")+"@") {
+		t.Errorf("unexpected provenance for old.owasp.org: %v", locs)
+	}
+}
+
+func TestLocalFilesInput(t *testing.T) {
+	dir := t.TempDir()
+	// More names than the output of the data source can buffer
+	var content string
+	for i := 0; i < 50; i++ {
+		content += fmt.Sprintf("host%d.owasp.org\n", i)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "hosts.txt"), []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write the file: %v", err)
+	}
+
+	cfg := config.NewConfig()
+	cfg.AddDomain("owasp.org")
+	cfg.AddDomain("owasp.com")
+	dsc := cfg.GetDataSourceConfig("LocalFiles")
+	dsc.SetOption("path", dir)
+	dsc.SetOption("git_history", "false")
+
+	sys := pluginMockSystem(cfg)
+	defer func() { _ = sys.Shutdown() }()
+
+	l := NewLocalFiles(sys)
+	if err := l.Start(); err != nil {
+		t.Fatalf("failed to start the data source: %v", err)
+	}
+	defer func() { _ = l.Stop() }()
+
+	timer := time.NewTimer(5 * time.Second)
+	defer timer.Stop()
+	// The output is not read, so the scan blocks while the input continues to be drained
+	for _, domain := range []string{"owasp.org", "owasp.com", "owasp.org"} {
+		select {
+		case <-timer.C:
+			t.Fatalf("the data source stopped reading the input during the scan")
+		case l.Input() <- &requests.DNSRequest{Name: domain, Domain: domain}:
+		}
+	}
+}
+
+func TestLocalFilesStartError(t *testing.T) {
+	cfg := config.NewConfig()
+	sys := pluginMockSystem(cfg)
+	defer func() { _ = sys.Shutdown() }()
+
+	if err := NewLocalFiles(sys).Start(); err == nil {
+		t.Errorf("the data source started without a path")
+	}
+
+	dsc := cfg.GetDataSourceConfig("LocalFiles")
+	dsc.SetOption("path", t.TempDir())
+	dsc.SetOption("git_history", "sometimes")
+	if err := NewLocalFiles(sys).Start(); err == nil {
+		t.Errorf("the data source started with an invalid git_history setting")
+	}
+}
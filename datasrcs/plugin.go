@@ -0,0 +1,364 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package datasrcs
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/OWASP/Amass/v3/config"
+	amassnet "github.com/OWASP/Amass/v3/net"
+	"github.com/OWASP/Amass/v3/requests"
+	"github.com/OWASP/Amass/v3/systems"
+	"github.com/caffix/service"
+)
+
+// Message types used by the plugin protocol.
+const (
+	PluginStart = "start"
+	PluginReady = "ready"
+	PluginError = "error"
+	PluginStop  = "stop"
+	PluginDNS   = "dns"
+	PluginAddr  = "addr"
+	PluginASN   = "asn"
+	PluginWhois = "whois"
+	PluginLog   = "log"
+)
+
+const pluginStartTimeout = 30 * time.Second
+
+// PluginMessage is a single line of the JSON lines protocol spoken over the stdin and stdout
+// of an out-of-process data source.
+type PluginMessage struct {
+	Type    string                 `json:"type"`
+	Config  *PluginConfig          `json:"config,omitempty"`
+	DNS     *requests.DNSRequest   `json:"dns,omitempty"`
+	Addr    *requests.AddrRequest  `json:"addr,omitempty"`
+	ASN     *requests.ASNRequest   `json:"asn,omitempty"`
+	Whois   *requests.WhoisRequest `json:"whois,omitempty"`
+	Tag     string                 `json:"tag,omitempty"`
+	Message string                 `json:"message,omitempty"`
+}
+
+// PluginConfig provides the plugin with the settings for the enumeration in the start message.
+type PluginConfig struct {
+	Name        string              `json:"name"`
+	Mode        string              `json:"mode"`
+	Domains     []string            `json:"domains"`
+	TTL         int                 `json:"ttl"`
+	Credentials *config.Credentials `json:"credentials,omitempty"`
+	Proxy       string              `json:"proxy,omitempty"`
+}
+
+// Plugin is the Service that handles access to a data source implemented by an external program.
+type Plugin struct {
+	service.BaseService
+
+	SourceType string
+	sys        systems.System
+	path       string
+	cmd        *exec.Cmd
+	writeLock  sync.Mutex
+	enc        *json.Encoder
+	stdin      io.WriteCloser
+	ready      chan error
+	ctx        context.Context
+	cancel     context.CancelFunc
+}
+
+// NewPlugin returns the object initialized, but not yet started.
+func NewPlugin(name, path string, sys systems.System) *Plugin {
+	p := &Plugin{
+		SourceType: requests.EXTERNAL,
+		sys:        sys,
+		path:       path,
+		ready:      make(chan error, 1),
+	}
+
+	p.ctx, p.cancel = context.WithCancel(context.Background())
+	p.BaseService = *service.NewBaseService(p, name)
+	return p
+}
+
+// Description implements the Service interface.
+func (p *Plugin) Description() string {
+	return p.SourceType
+}
+
+// OnStart implements the Service interface.
+func (p *Plugin) OnStart() error {
+	p.cmd = exec.CommandContext(p.ctx, p.path)
+
+	stdin, err := p.cmd.StdinPipe()
+	if err != nil {
+		return fmt.Errorf("%s: failed to obtain the plugin stdin: %v", p.String(), err)
+	}
+	stdout, err := p.cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("%s: failed to obtain the plugin stdout: %v", p.String(), err)
+	}
+	if err := p.cmd.Start(); err != nil {
+		return fmt.Errorf("%s: failed to execute the plugin %s: %v", p.String(), p.path, err)
+	}
+
+	p.stdin = stdin
+	p.enc = json.NewEncoder(stdin)
+	go p.responses(stdout)
+
+	if err := p.send(&PluginMessage{Type: PluginStart, Config: p.pluginConfig()}); err != nil {
+		p.cancel()
+		return err
+	}
+
+	t := time.NewTimer(pluginStartTimeout)
+	defer t.Stop()
+
+	select {
+	case err = <-p.ready:
+	case <-t.C:
+		err = fmt.Errorf("%s: the plugin did not become ready", p.String())
+	}
+	if err != nil {
+		p.sys.Config().Log.Print(err.Error())
+		p.cancel()
+		return err
+	}
+
+	go p.requests()
+	return nil
+}
+
+// OnStop implements the Service interface.
+func (p *Plugin) OnStop() error {
+	if p.stdin == nil {
+		p.cancel()
+		return nil
+	}
+
+	_ = p.send(&PluginMessage{Type: PluginStop})
+	_ = p.stdin.Close()
+
+	done := make(chan struct{})
+	go func() {
+		_ = p.cmd.Wait()
+		close(done)
+	}()
+
+	t := time.NewTimer(5 * time.Second)
+	defer t.Stop()
+
+	select {
+	case <-done:
+	case <-t.C:
+	}
+	p.cancel()
+	return nil
+}
+
+func (p *Plugin) pluginConfig() *PluginConfig {
+	cfg := p.sys.Config()
+
+	mode := "normal"
+	if cfg.Active {
+		mode = "active"
+	} else if cfg.Passive {
+		mode = "passive"
+	}
+
+	pc := &PluginConfig{
+		Name:    p.String(),
+		Mode:    mode,
+		Domains: cfg.Domains(),
+		Proxy:   cfg.DataSourceProxy(p.String()),
+	}
+	if dsc := cfg.GetDataSourceConfig(p.String()); dsc != nil {
+		pc.TTL = dsc.TTL
+		creds, err := dsc.ResolveCredentials()
+		if err != nil {
+			cfg.Log.Printf("%s: failed to resolve the credentials: %v", p.String(), err)
+		}
+		pc.Credentials = creds
+	}
+	return pc
+}
+
+func (p *Plugin) send(msg *PluginMessage) error {
+	p.writeLock.Lock()
+	defer p.writeLock.Unlock()
+
+	if p.enc == nil {
+		return errors.New("the plugin has not been started")
+	}
+	return p.enc.Encode(msg)
+}
+
+func (p *Plugin) requests() {
+	for {
+		select {
+		case <-p.Done():
+			return
+		case <-p.ctx.Done():
+			return
+		case in := <-p.Input():
+			var msg *PluginMessage
+
+			switch req := in.(type) {
+			case *requests.DNSRequest:
+				if req != nil && req.Domain != "" {
+					msg = &PluginMessage{Type: PluginDNS, DNS: req}
+				}
+			case *requests.AddrRequest:
+				if req != nil && req.Address != "" {
+					msg = &PluginMessage{Type: PluginAddr, Addr: req}
+				}
+			case *requests.ASNRequest:
+				if req != nil && (req.Address != "" || req.ASN != 0) {
+					msg = &PluginMessage{Type: PluginASN, ASN: req}
+				}
+			case *requests.WhoisRequest:
+				if req != nil && req.Domain != "" {
+					msg = &PluginMessage{Type: PluginWhois, Whois: req}
+				}
+			}
+
+			if msg != nil {
+				p.CheckRateLimit()
+				if err := p.send(msg); err != nil {
+					p.sys.Config().Log.Printf("%s: failed to send the request: %v", p.String(), err)
+				}
+			}
+		}
+	}
+}
+
+func (p *Plugin) responses(r io.Reader) {
+	var started bool
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 10*1024*1024)
+
+	for scanner.Scan() {
+		var msg PluginMessage
+
+		if err := json.Unmarshal(scanner.Bytes(), &msg); err != nil {
+			p.sys.Config().Log.Printf("%s: failed to decode the plugin message: %v", p.String(), err)
+			continue
+		}
+
+		switch msg.Type {
+		case PluginReady:
+			if !started {
+				started = true
+				if msg.Tag != "" {
+					p.SourceType = pluginTag(msg.Tag)
+				}
+				p.ready <- nil
+			}
+		case PluginError:
+			if !started {
+				started = true
+				p.ready <- fmt.Errorf("%s: %s", p.String(), msg.Message)
+			} else {
+				p.sys.Config().Log.Printf("%s: %s", p.String(), msg.Message)
+			}
+		case PluginLog:
+			p.sys.Config().Log.Printf("%s: %s", p.String(), msg.Message)
+		default:
+			p.handleResponse(&msg)
+		}
+	}
+
+	if !started {
+		p.ready <- fmt.Errorf("%s: the plugin exited before becoming ready", p.String())
+	}
+}
+
+func (p *Plugin) handleResponse(msg *PluginMessage) {
+	cfg := p.sys.Config()
+
+	var out interface{}
+	switch msg.Type {
+	case PluginDNS:
+		if req := msg.DNS; req != nil {
+			if domain := cfg.WhichDomain(req.Name); domain != "" {
+				req.Domain = domain
+				req.Tag = p.tag(req.Tag)
+				req.Source = p.String()
+				out = req
+			}
+		}
+	case PluginAddr:
+		if req := msg.Addr; req != nil {
+			ip := net.ParseIP(req.Address)
+			if ip == nil {
+				return
+			}
+			if reserved, _ := amassnet.IsReservedAddress(ip.String()); reserved {
+				return
+			}
+			if domain := cfg.WhichDomain(req.Domain); domain != "" {
+				req.Address = ip.String()
+				req.Domain = domain
+				req.Tag = p.tag(req.Tag)
+				req.Source = p.String()
+				out = req
+			}
+		}
+	case PluginASN:
+		if req := msg.ASN; req != nil && req.ASN != 0 && req.Prefix != "" {
+			req.Tag = p.tag(req.Tag)
+			req.Source = p.String()
+			if req.AllocationDate.IsZero() {
+				req.AllocationDate = time.Now()
+			}
+			if len(req.Netblocks) == 0 {
+				req.Netblocks = []string{req.Prefix}
+			}
+			if req.Valid() {
+				p.sys.Cache().Update(req)
+			}
+		}
+	case PluginWhois:
+		if req := msg.Whois; req != nil && req.Domain != "" && len(req.NewDomains) > 0 {
+			req.Tag = p.tag(req.Tag)
+			req.Source = p.String()
+			out = req
+		}
+	}
+
+	if out != nil {
+		select {
+		case <-p.ctx.Done():
+		case <-p.Done():
+		case p.Output() <- out:
+		}
+	}
+}
+
+func (p *Plugin) tag(t string) string {
+	if t == "" {
+		return p.SourceType
+	}
+	return pluginTag(t)
+}
+
+// Returns the data source type claimed by the plugin, or EXTERNAL when the type is trusted facing
+// DNS wildcards, since the names provided by the plugins cannot skip the wildcard filtering.
+func pluginTag(t string) string {
+	switch t = strings.ToLower(strings.TrimSpace(t)); t {
+	case requests.API, requests.SCRAPE, requests.EXTERNAL:
+		return t
+	}
+	return requests.EXTERNAL
+}
@@ -0,0 +1,194 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package datasrcs
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/OWASP/Amass/v3/config"
+	"github.com/OWASP/Amass/v3/requests"
+	"github.com/OWASP/Amass/v3/systems"
+	"github.com/caffix/netmap"
+	"github.com/caffix/resolve"
+	"github.com/caffix/service"
+)
+
+const helperEnvVar = "AMASS_TEST_PLUGIN_HELPER"
+
+// TestPluginHelperProcess is not a real test, but the plugin executed by the other tests.
+func TestPluginHelperProcess(t *testing.T) {
+	if os.Getenv(helperEnvVar) != "1" {
+		return
+	}
+	defer os.Exit(0)
+
+	enc := json.NewEncoder(os.Stdout)
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		var msg PluginMessage
+
+		if err := json.Unmarshal(scanner.Bytes(), &msg); err != nil {
+			continue
+		}
+
+		switch msg.Type {
+		case PluginStart:
+			if msg.Config == nil || msg.Config.Credentials == nil || msg.Config.Credentials.Key != "secret" {
+				_ = enc.Encode(&PluginMessage{Type: PluginError, Message: "missing API key"})
+				continue
+			}
+			_ = enc.Encode(&PluginMessage{Type: PluginReady, Tag: requests.API})
+		case PluginDNS:
+			_ = enc.Encode(&PluginMessage{Type: PluginLog, Message: "querying " + msg.DNS.Domain})
+			_ = enc.Encode(&PluginMessage{Type: PluginDNS, DNS: &requests.DNSRequest{Name: "www." + msg.DNS.Domain}})
+			_ = enc.Encode(&PluginMessage{Type: PluginDNS, DNS: &requests.DNSRequest{Name: "www.out-of-scope.com"}})
+			_ = enc.Encode(&PluginMessage{Type: PluginAddr, Addr: &requests.AddrRequest{
+				Address: "72.237.4.113",
+				Domain:  msg.DNS.Domain,
+			}})
+		case PluginStop:
+			return
+		}
+	}
+}
+
+func pluginPath(t *testing.T) string {
+	if runtime.GOOS == "windows" {
+		t.Skip("the plugin tests require a POSIX shell")
+	}
+
+	path := filepath.Join(t.TempDir(), "plugin.sh")
+	script := fmt.Sprintf("#!/bin/sh\nexec %s -test.run=TestPluginHelperProcess\n", os.Args[0])
+	if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+		t.Fatalf("failed to write the plugin script: %v", err)
+	}
+
+	t.Setenv(helperEnvVar, "1")
+	return path
+}
+
+func pluginMockSystem(cfg *config.Config) *systems.SimpleSystem {
+	return &systems.SimpleSystem{
+		Cfg:      cfg,
+		Pool:     resolve.NewResolvers(),
+		Trusted:  resolve.NewResolvers(),
+		Graph:    netmap.NewGraph(netmap.NewCayleyGraphMemory()),
+		ASNCache: requests.NewASNCache(),
+	}
+}
+
+func TestPlugin(t *testing.T) {
+	path := pluginPath(t)
+
+	cfg := config.NewConfig()
+	cfg.AddDomain("owasp.org")
+	dsc := cfg.GetDataSourceConfig("testplugin")
+	dsc.Plugin = path
+	_ = dsc.AddCredentials(&config.Credentials{Name: "account", Key: "secret"})
+
+	sys := pluginMockSystem(cfg)
+	var p service.Service
+	for _, src := range GetAllSources(sys) {
+		if src.String() == "testplugin" {
+			p = src
+		}
+	}
+	if p == nil {
+		t.Fatal("GetAllSources did not return the configured plugin")
+	}
+	if err := sys.AddAndStart(p); err != nil {
+		t.Fatalf("failed to start the plugin: %v", err)
+	}
+	defer func() { _ = sys.Shutdown() }()
+
+	if p.Description() != requests.API {
+		t.Errorf("the plugin did not set the data source type, got %s", p.Description())
+	}
+
+	p.Input() <- &requests.DNSRequest{Domain: "owasp.org"}
+
+	timer := time.NewTimer(15 * time.Second)
+	defer timer.Stop()
+
+	for _, want := range []string{"www.owasp.org", "72.237.4.113"} {
+		select {
+		case <-timer.C:
+			t.Fatal("the test timed out")
+		case out := <-p.Output():
+			switch req := out.(type) {
+			case *requests.DNSRequest:
+				if req.Name != want || req.Domain != "owasp.org" || req.Source != "testplugin" || req.Tag != requests.API {
+					t.Errorf("the plugin returned an unexpected DNS request: %v", req)
+				}
+			case *requests.AddrRequest:
+				if req.Address != want || req.Domain != "owasp.org" || req.Source != "testplugin" {
+					t.Errorf("the plugin returned an unexpected address request: %v", req)
+				}
+			}
+		}
+	}
+}
+
+func TestPluginTag(t *testing.T) {
+	tests := []struct {
+		tag, expected string
+	}{
+		{requests.API, requests.API},
+		{" Scrape ", requests.SCRAPE},
+		{requests.EXTERNAL, requests.EXTERNAL},
+		// The trusted data source types cannot be claimed by the plugins
+		{requests.ARCHIVE, requests.EXTERNAL},
+		{requests.CERT, requests.EXTERNAL},
+		{requests.DNS, requests.EXTERNAL},
+		{"unknown", requests.EXTERNAL},
+	}
+
+	for _, test := range tests {
+		if got := pluginTag(test.tag); got != test.expected {
+			t.Errorf("pluginTag(%q) returned %s, expected %s", test.tag, got, test.expected)
+		}
+	}
+
+	p := NewPlugin("testplugin", "", pluginMockSystem(config.NewConfig()))
+	if got := p.tag(""); got != requests.EXTERNAL {
+		t.Errorf("the plugin returned the data source type %s for an untagged request", got)
+	}
+	if got := p.tag(requests.CERT); got != requests.EXTERNAL {
+		t.Errorf("the plugin allowed a request to claim the data source type %s", got)
+	}
+}
+
+func TestPluginStartError(t *testing.T) {
+	path := pluginPath(t)
+
+	cfg := config.NewConfig()
+	sys := pluginMockSystem(cfg)
+	defer func() { _ = sys.Shutdown() }()
+
+	if err := NewPlugin("testplugin", path, sys).Start(); err == nil {
+		t.Error("the plugin started without the required credentials")
+	}
+	if err := NewPlugin("missing", filepath.Join(t.TempDir(), "missing"), sys).Start(); err == nil {
+		t.Error("the plugin started without an executable")
+	}
+}
+
+func TestRegisterSource(t *testing.T) {
+	ctor := func(sys systems.System) service.Service { return NewRADb(sys) }
+
+	if err := RegisterSource("", ctor); err == nil {
+		t.Error("RegisterSource accepted an empty data source name")
+	}
+	if err := RegisterSource("radb", ctor); err == nil {
+		t.Error("RegisterSource accepted a data source name that was already registered")
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/OWASP/Amass/v3/datasrcs/scripting"
 	amassnet "github.com/OWASP/Amass/v3/net"
 	"github.com/OWASP/Amass/v3/net/http"
 	"github.com/OWASP/Amass/v3/requests"
@@ -54,6 +55,15 @@ func (r *RADb) Description() string {
 	return r.SourceType
 }
 
+// Metadata implements the MetadataSource interface.
+func (r *RADb) Metadata() *scripting.Metadata {
+	return &scripting.Metadata{
+		Homepage:   "https://www.radb.net",
+		RateLimit:  1,
+		Categories: []string{requests.API},
+	}
+}
+
 // OnStart implements the Service interface.
 func (r *RADb) OnStart() error {
 	msg := resolve.QueryMsg(radbWhoisURL, dns.TypeA)
@@ -121,6 +131,7 @@ func (r *RADb) executeASNAddrQuery(ctx context.Context, addr string) {
 	resp, err := http.RequestWebPage(ctx, &http.Request{
 		URL:    url,
 		Header: headers,
+		Proxy:  r.sys.Config().DataSourceProxy(r.String()),
 	})
 	if err != nil || resp.StatusCode < 200 || resp.StatusCode >= 400 {
 		r.sys.Config().Log.Printf("%s: %s: %v", r.String(), url, err)
@@ -178,6 +189,7 @@ func (r *RADb) executeASNQuery(ctx context.Context, asn int, addr, prefix string
 	resp, err := http.RequestWebPage(ctx, &http.Request{
 		URL:    url,
 		Header: headers,
+		Proxy:  r.sys.Config().DataSourceProxy(r.String()),
 	})
 	if err != nil || resp.StatusCode < 200 || resp.StatusCode >= 400 {
 		r.sys.Config().Log.Printf("%s: %s: %v", r.String(), url, err)
@@ -260,6 +272,7 @@ func (r *RADb) netblocks(ctx context.Context, asn int) *stringset.Set {
 	resp, err := http.RequestWebPage(ctx, &http.Request{
 		URL:    url,
 		Header: headers,
+		Proxy:  r.sys.Config().DataSourceProxy(r.String()),
 	})
 	if err != nil || resp.StatusCode < 200 || resp.StatusCode >= 400 {
 		r.sys.Config().Log.Printf("%s: %s: %v", r.String(), url, err)
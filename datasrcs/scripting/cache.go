@@ -9,17 +9,25 @@ import (
 	"context"
 	"encoding/gob"
 	"fmt"
+	"sync/atomic"
 	"time"
 
 	"github.com/OWASP/Amass/v3/net/http"
+	"github.com/caffix/netmap"
+	"github.com/cayleygraph/quad"
 )
 
+// CacheStats returns the number of cached responses used and the number of cache lookups that missed.
+func (s *Script) CacheStats() (int64, int64) {
+	return atomic.LoadInt64(&s.hits), atomic.LoadInt64(&s.misses)
+}
+
 func (s *Script) getCachedResponse(ctx context.Context, url string, ttl int) (*http.Response, error) {
 	for _, db := range s.sys.GraphDatabases() {
 		tCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
 		defer cancel()
 
-		if data, err := db.GetSourceData(tCtx, s.String(), url, ttl); err == nil {
+		if data, err := cachedSourceData(tCtx, db, s.String(), url, ttl); err == nil {
 			resp := &http.Response{}
 			b := bytes.Buffer{}
 			b.Write([]byte(data))
@@ -33,6 +41,50 @@ func (s *Script) getCachedResponse(ctx context.Context, url string, ttl int) (*h
 	return nil, fmt.Errorf("failed to obtain a cached response for %s", url)
 }
 
+// CacheTimestamp returns the time that the response cached in the graph database was obtained.
+// Imported responses keep their original timestamps in RFC 3339 format.
+func CacheTimestamp(v quad.Value) (time.Time, bool) {
+	if v == nil {
+		return time.Time{}, false
+	}
+
+	switch n := v.Native().(type) {
+	case time.Time:
+		return n, true
+	case string:
+		if ts, err := time.Parse(time.RFC3339Nano, n); err == nil {
+			return ts, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// Returns the response from the data source cached for the query within the last ttl minutes.
+func cachedSourceData(ctx context.Context, db *netmap.Graph, source, query string, ttl int) (string, error) {
+	var edges []*netmap.Edge
+
+	if node, err := db.ReadNode(ctx, source, netmap.TypeSource); err == nil {
+		edges, _ = db.ReadOutEdges(ctx, node, query)
+	}
+
+	for _, edge := range edges {
+		p, err := db.ReadProperties(ctx, edge.To, "timestamp")
+		if err != nil || len(p) == 0 {
+			continue
+		}
+		if ts, ok := CacheTimestamp(p[0].Value); !ok || time.Since(ts) > time.Duration(ttl)*time.Minute {
+			continue
+		}
+
+		if p, err := db.ReadProperties(ctx, edge.To, "response"); err == nil && len(p) > 0 {
+			if data, ok := p[0].Value.Native().(string); ok && data != "" {
+				return data, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("no response from %s was cached for %s", source, query)
+}
+
 func (s *Script) setCachedResponse(ctx context.Context, url string, resp *http.Response) error {
 	b := bytes.Buffer{}
 	e := gob.NewEncoder(&b)
@@ -0,0 +1,55 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package scripting
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/caffix/netmap"
+)
+
+func TestCachedSourceData(t *testing.T) {
+	ctx := context.Background()
+	g := netmap.NewGraph(netmap.NewCayleyGraphMemory())
+	defer g.Close()
+
+	if err := g.CacheSourceData(ctx, "First", "https://api.first.com/owasp.org", "first"); err != nil {
+		t.Fatal(err)
+	}
+	// Imported responses keep their original timestamps as properties of the response nodes
+	src, _ := g.UpsertSource(ctx, "Second")
+	for query, ts := range map[string]time.Time{
+		"https://api.second.com/owasp.org": time.Now().Add(-30 * time.Minute),
+		"https://api.second.com/amass.org": time.Now().Add(-2 * time.Hour),
+	} {
+		node, _ := g.UpsertNode(ctx, "Second-response-"+query, "response")
+		_ = g.UpsertProperty(ctx, node, "timestamp", ts.Format(time.RFC3339Nano))
+		_ = g.UpsertProperty(ctx, node, "response", query)
+		_ = g.UpsertEdge(ctx, &netmap.Edge{Predicate: query, From: src, To: node})
+	}
+
+	tests := []struct {
+		source string
+		query  string
+		ttl    int
+		want   string
+	}{
+		{"First", "https://api.first.com/owasp.org", 60, "first"},
+		{"Second", "https://api.second.com/owasp.org", 60, "https://api.second.com/owasp.org"},
+		{"Second", "https://api.second.com/amass.org", 60, ""},
+		{"Second", "https://api.second.com/amass.org", 180, "https://api.second.com/amass.org"},
+		{"Third", "https://api.third.com/owasp.org", 60, ""},
+	}
+	for _, test := range tests {
+		data, err := cachedSourceData(ctx, g, test.source, test.query, test.ttl)
+		if test.want == "" && err == nil {
+			t.Errorf("cachedSourceData returned an expired or missing response for %s", test.query)
+		} else if test.want != "" && (err != nil || data != test.want) {
+			t.Errorf("cachedSourceData returned %s and error %v for %s, expected %s", data, err, test.query, test.want)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package scripting
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"fmt"
+	"net"
+
+	"github.com/OWASP/Amass/v3/net/dns"
+	"github.com/OWASP/Amass/v3/net/http"
+	"github.com/OWASP/Amass/v3/systems"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// Wrapper so that scripts can perform a TLS handshake and obtain the certificate details.
+func (s *Script) certInfo(L *lua.LState) int {
+	ctx, err := extractContext(L.CheckUserData(1))
+	host := L.CheckString(2)
+	port := int(L.CheckNumber(3))
+	sni := L.OptString(4, "")
+	if err != nil || host == "" || port <= 0 {
+		L.Push(lua.LNil)
+		L.Push(lua.LString("Proper parameters were not provided"))
+		return 2
+	}
+	if s.sys.Config().Blacklisted(host) || systems.IsAddressExcluded(s.sys, host) {
+		L.Push(lua.LNil)
+		L.Push(lua.LString("The host is out of scope"))
+		return 2
+	}
+
+	conn, err := http.TLSConnWithServerName(ctx, host, port, certServerName(host, sni))
+	if err != nil {
+		L.Push(lua.LNil)
+		L.Push(lua.LString(fmt.Sprintf("Failed to complete the TLS handshake: %v", err)))
+		return 2
+	}
+	defer conn.Close()
+
+	state := conn.ConnectionState()
+	if len(state.PeerCertificates) == 0 {
+		L.Push(lua.LNil)
+		L.Push(lua.LString("The server did not provide a certificate"))
+		return 2
+	}
+
+	r := L.NewTable()
+	r.RawSetString("version", lua.LNumber(state.Version))
+	r.RawSetString("server_name", lua.LString(state.ServerName))
+
+	chain := L.NewTable()
+	for _, cert := range state.PeerCertificates {
+		chain.Append(certToTable(L, cert))
+	}
+	r.RawSetString("certificates", chain)
+
+	names := L.NewTable()
+	for _, name := range http.NamesFromCert(state.PeerCertificates[0]) {
+		names.Append(lua.LString(name))
+	}
+	r.RawSetString("names", names)
+
+	L.Push(r)
+	L.Push(lua.LNil)
+	return 2
+}
+
+// Returns the server name sent during the handshake, which defaults to the host when it is a DNS
+// name, since most servers present a default certificate to clients without a server name.
+func certServerName(host, sni string) string {
+	if sni == "" && net.ParseIP(host) == nil {
+		return host
+	}
+	return sni
+}
+
+func certToTable(L *lua.LState, cert *x509.Certificate) *lua.LTable {
+	c := L.NewTable()
+
+	c.RawSetString("version", lua.LNumber(cert.Version))
+	c.RawSetString("subject", pkixNameToTable(L, cert.Subject))
+	c.RawSetString("issuer", pkixNameToTable(L, cert.Issuer))
+	c.RawSetString("common_name", lua.LString(dns.RemoveAsteriskLabel(cert.Subject.CommonName)))
+	c.RawSetString("serial", lua.LString(cert.SerialNumber.Text(16)))
+	c.RawSetString("not_before", lua.LNumber(cert.NotBefore.Unix()))
+	c.RawSetString("not_after", lua.LNumber(cert.NotAfter.Unix()))
+	c.RawSetString("is_ca", lua.LBool(cert.IsCA))
+	c.RawSetString("signature_algorithm", lua.LString(cert.SignatureAlgorithm.String()))
+
+	fingerprint := sha256.Sum256(cert.Raw)
+	c.RawSetString("sha256_fingerprint", lua.LString(hex.EncodeToString(fingerprint[:])))
+
+	san := L.NewTable()
+	for _, name := range cert.DNSNames {
+		san.Append(lua.LString(dns.RemoveAsteriskLabel(name)))
+	}
+	c.RawSetString("subject_alternate_names", san)
+
+	addrs := L.NewTable()
+	for _, ip := range cert.IPAddresses {
+		addrs.Append(lua.LString(ip.String()))
+	}
+	c.RawSetString("ip_addresses", addrs)
+
+	emails := L.NewTable()
+	for _, email := range cert.EmailAddresses {
+		emails.Append(lua.LString(email))
+	}
+	c.RawSetString("email_addresses", emails)
+	return c
+}
+
+func pkixNameToTable(L *lua.LState, name pkix.Name) *lua.LTable {
+	tb := L.NewTable()
+
+	tb.RawSetString("string", lua.LString(name.String()))
+	tb.RawSetString("common_name", lua.LString(name.CommonName))
+	tb.RawSetString("serial_number", lua.LString(name.SerialNumber))
+
+	fields := map[string][]string{
+		"country":             name.Country,
+		"organization":        name.Organization,
+		"organizational_unit": name.OrganizationalUnit,
+		"locality":            name.Locality,
+		"province":            name.Province,
+	}
+	for k, values := range fields {
+		list := L.NewTable()
+
+		for _, v := range values {
+			list.Append(lua.LString(v))
+		}
+		tb.RawSetString(k, list)
+	}
+	return tb
+}
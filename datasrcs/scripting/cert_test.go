@@ -0,0 +1,84 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package scripting
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/OWASP/Amass/v3/requests"
+)
+
+func TestCertInfo(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	u, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatalf("failed to parse the test server URL: %v", err)
+	}
+
+	script, sys := setupMockScriptEnv(fmt.Sprintf(`
+		name="cert_info"
+		type="testing"
+
+		function vertical(ctx, domain)
+			local info, err = cert_info(ctx, "127.0.0.1", %s, domain)
+			if (err ~= nil and err ~= "") then
+				log(ctx, err)
+				return
+			end
+
+			local leaf = info.certificates[1]
+			if (#(leaf.sha256_fingerprint) == 64 and leaf.serial ~= "" and
+				leaf.not_after > leaf.not_before and #(leaf.ip_addresses) > 0) then
+				for _, name in pairs(leaf.subject_alternate_names) do
+					new_name(ctx, name)
+				end
+			end
+		end
+	`, u.Port()))
+	if script == nil || sys == nil {
+		t.Fatal("failed to initialize the scripting environment")
+	}
+	defer func() { _ = sys.Shutdown() }()
+
+	sys.Config().AddDomain("example.com")
+	script.Input() <- &requests.DNSRequest{Domain: "example.com"}
+
+	timer := time.NewTimer(15 * time.Second)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		t.Error("the test timed out")
+	case msg := <-script.Output():
+		if ans, ok := msg.(*requests.DNSRequest); !ok || ans.Name != "example.com" {
+			t.Errorf("failed to obtain the certificate details: %v", msg)
+		}
+	}
+}
+
+func TestCertServerName(t *testing.T) {
+	tests := []struct {
+		host, sni, expected string
+	}{
+		{"www.example.com", "", "www.example.com"},
+		{"www.example.com", "api.example.com", "api.example.com"},
+		{"192.168.1.1", "", ""},
+		{"192.168.1.1", "www.example.com", "www.example.com"},
+		{"2001:db8::1", "", ""},
+	}
+
+	for _, test := range tests {
+		if got := certServerName(test.host, test.sni); got != test.expected {
+			t.Errorf("certServerName(%q, %q) returned %q, expected %q", test.host, test.sni, got, test.expected)
+		}
+	}
+}
@@ -118,7 +118,11 @@ func (s *Script) dataSourceConfig(L *lua.LState) int {
 		tb.RawSetString("ttl", lua.LNumber(cfg.TTL))
 	}
 
-	if creds := cfg.GetCredentials(); creds != nil {
+	creds, err := cfg.ResolveCredentials()
+	if err != nil {
+		s.sys.Config().Log.Printf("%s: failed to resolve the credentials: %v", s.String(), err)
+	}
+	if creds != nil {
 		c := L.NewTable()
 
 		c.RawSetString("name", lua.LString(creds.Name))
@@ -146,7 +150,7 @@ func (s *Script) inScope(L *lua.LState) int {
 	result := lua.LFalse
 
 	if _, err := extractContext(L.CheckUserData(1)); err == nil {
-		if sub := L.CheckString(2); sub != "" && s.sys.Config().IsDomainInScope(sub) {
+		if sub := L.CheckString(2); sub != "" && s.sys.Config().IsDomainInScope(sub) && !s.sys.Config().Blacklisted(sub) {
 			result = lua.LTrue
 		}
 	}
@@ -16,6 +16,7 @@ import (
 	amassnet "github.com/OWASP/Amass/v3/net"
 	amassdns "github.com/OWASP/Amass/v3/net/dns"
 	"github.com/OWASP/Amass/v3/requests"
+	"github.com/OWASP/Amass/v3/systems"
 	"github.com/caffix/resolve"
 	"github.com/miekg/dns"
 	bf "github.com/tylertreat/BoomFilters"
@@ -163,6 +164,11 @@ func (s *Script) reverseSweep(L *lua.LState) int {
 		L.Push(lua.LString("failed to obtain the IP address"))
 		return 1
 	}
+	// Netblocks excluded from the scope are never swept
+	if systems.IsAddressExcluded(s.sys, addr) {
+		L.Push(lua.LNil)
+		return 1
+	}
 
 	size := defaultSweepSize
 	if s.sys.Config().Active {
@@ -200,7 +206,7 @@ func (s *Script) reverseSweep(L *lua.LState) int {
 		}
 
 		sweepLock.Lock()
-		if a := ip.String(); !sweepFilter.TestAndAdd([]byte(a)) {
+		if a := ip.String(); !s.sys.Config().IsAddressExcluded(a) && !sweepFilter.TestAndAdd([]byte(a)) {
 			count++
 			go s.getPTR(ctx, a, ch)
 		}
@@ -8,6 +8,8 @@ import (
 	"context"
 	"net/url"
 	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/OWASP/Amass/v3/net/dns"
 	"github.com/OWASP/Amass/v3/net/http"
@@ -185,8 +187,10 @@ func (s *Script) req(ctx context.Context, url, data string, hdr http.Header, aut
 	dsc := cfg.GetDataSourceConfig(s.String())
 	if dsc != nil && dsc.TTL > 0 {
 		if r, err := s.getCachedResponse(ctx, url+data, dsc.TTL); err == nil {
+			atomic.AddInt64(&s.hits, 1)
 			return r, nil
 		}
+		atomic.AddInt64(&s.misses, 1)
 	}
 
 	method := "GET"
@@ -195,13 +199,16 @@ func (s *Script) req(ctx context.Context, url, data string, hdr http.Header, aut
 	}
 
 	numRateLimitChecks(s, s.seconds)
+	start := time.Now()
 	resp, err := http.RequestWebPage(ctx, &http.Request{
 		URL:    url,
 		Method: method,
 		Header: hdr,
 		Body:   data,
 		Auth:   auth,
+		Proxy:  s.proxy(url),
 	})
+	s.recordRequest(time.Since(start), err != nil || resp.StatusCode >= 400)
 	if err != nil {
 		if cfg.Verbose {
 			cfg.Log.Printf("%s: %s: %v", s.String(), url, err)
@@ -222,9 +229,22 @@ func (s *Script) crawl(L *lua.LState) int {
 
 	u := L.CheckString(2)
 	if u != "" {
-		max := L.CheckInt(3)
+		opts := &http.CrawlOptions{
+			Scope:    cfg.Domains(),
+			MaxLinks: L.CheckInt(3),
+			Proxy:    s.proxy(u),
+		}
+		// The optional table provides the depth and byte budgets for the crawl
+		if tb := L.OptTable(4, nil); tb != nil {
+			if depth, found := getNumberField(L, tb, "depth"); found {
+				opts.MaxDepth = int(depth)
+			}
+			if bytes, found := getNumberField(L, tb, "bytes"); found {
+				opts.MaxBytes = int64(bytes)
+			}
+		}
 
-		err = http.Crawl(ctx, u, cfg.Domains(), max, func(req *http.Request, resp *http.Response) {
+		err = http.CrawlWithOptions(ctx, u, opts, func(req *http.Request, resp *http.Response) {
 			if u, err := url.Parse(req.URL); err == nil {
 				s.genNewName(ctx, http.CleanName(u.Hostname()))
 			}
@@ -240,6 +260,8 @@ func (s *Script) crawl(L *lua.LState) int {
 					k == "Content-Security-Policy-Report-Only" ||
 					k == "X-Content-Security-Policy" || k == "X-Webkit-CSP" {
 					s.internalSendNamesWithSrc(ctx, v, s.Description(), "CSP Header")
+				} else if k == "Access-Control-Allow-Origin" {
+					s.internalSendNamesWithSrc(ctx, v, s.Description(), "CORS Header")
 				}
 			}
 		})
@@ -250,3 +272,18 @@ func (s *Script) crawl(L *lua.LState) int {
 	}
 	return 0
 }
+
+// Returns the proxy URL selected for traffic generated by the script and records it in the verbose log.
+func (s *Script) proxy(target string) string {
+	cfg := s.sys.Config()
+
+	p := cfg.DataSourceProxy(s.String())
+	if p != "" && cfg.Verbose {
+		redacted := p
+		if u, err := url.Parse(p); err == nil {
+			redacted = u.Redacted()
+		}
+		cfg.Log.Printf("%s: %s: using the proxy at %s", s.String(), target, redacted)
+	}
+	return p
+}
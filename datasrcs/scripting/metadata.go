@@ -0,0 +1,64 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package scripting
+
+import (
+	"errors"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+// Metadata contains the optional details declared by a data source, such as the
+// 'metadata' global table of a script.
+type Metadata struct {
+	// The credential fields required by the data source (username, password, key, secret)
+	Credentials []string
+	Homepage    string
+	// The number of seconds between requests sent to the data source
+	RateLimit  int
+	Categories []string
+	Version    string
+}
+
+// Metadata returns the details declared by the script, with the rate limit reflecting
+// the value currently in effect.
+func (s *Script) Metadata() *Metadata {
+	m := &Metadata{}
+	if s.metadata != nil {
+		*m = *s.metadata
+	}
+	if s.seconds > 0 {
+		m.RateLimit = s.seconds
+	}
+	return m
+}
+
+// Acquires the optional script metadata by accessing the global table.
+func (s *Script) scriptMetadata() (*Metadata, error) {
+	L := s.luaState
+	m := &Metadata{}
+
+	lv := L.GetGlobal("metadata")
+	if lv.Type() == lua.LTNil {
+		return m, nil
+	}
+	tb, ok := lv.(*lua.LTable)
+	if !ok {
+		return nil, errors.New("the script global 'metadata' is not a table")
+	}
+
+	m.Credentials = getStringSliceField(L, tb, "credentials")
+	m.Categories = getStringSliceField(L, tb, "categories")
+	if str, found := getStringField(L, tb, "homepage"); found {
+		m.Homepage = str
+	}
+	if str, found := getStringField(L, tb, "version"); found {
+		m.Version = str
+	}
+	if num, found := getNumberField(L, tb, "rate_limit"); found {
+		m.RateLimit = int(num)
+	}
+	return m, nil
+}
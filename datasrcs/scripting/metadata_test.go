@@ -0,0 +1,90 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package scripting
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/OWASP/Amass/v3/config"
+	"github.com/OWASP/Amass/v3/resources"
+)
+
+func TestScriptMetadata(t *testing.T) {
+	sys := newMockSystem(config.NewConfig())
+	defer func() { _ = sys.Shutdown() }()
+
+	s := NewScript(`
+		name="metadata"
+		type="testing"
+		metadata = {
+			['credentials']={"key", "secret"},
+			['homepage']="https://owasp.org",
+			['rate_limit']=5,
+			['categories']={"api", "cert"},
+			['version']="1.0",
+		}
+	`, sys)
+	if s == nil {
+		t.Fatal("failed to initialize the script with a metadata table")
+	}
+
+	m := s.Metadata()
+	if len(m.Credentials) != 2 || m.Credentials[0] != "key" || m.Credentials[1] != "secret" {
+		t.Errorf("failed to obtain the credential fields: %v", m.Credentials)
+	}
+	if m.Homepage != "https://owasp.org" || m.Version != "1.0" || len(m.Categories) != 2 {
+		t.Errorf("failed to obtain the metadata strings: %v", m)
+	}
+	if m.RateLimit != 5 {
+		t.Errorf("failed to obtain the default rate limit, expected 5, got %d", m.RateLimit)
+	}
+
+	if err := sys.AddAndStart(s); err != nil {
+		t.Fatalf("failed to start the script: %v", err)
+	}
+	if s.seconds != 5 {
+		t.Errorf("the declared rate limit was not applied, expected 5, got %d", s.seconds)
+	}
+}
+
+func TestScriptMetadataInvalid(t *testing.T) {
+	sys := newMockSystem(config.NewConfig())
+	defer func() { _ = sys.Shutdown() }()
+
+	if s := NewScript(`
+		name="metadata"
+		type="testing"
+		metadata="invalid"
+	`, sys); s != nil {
+		t.Error("NewScript accepted a metadata global that is not a table")
+	}
+}
+
+func TestDefaultScriptsRateLimit(t *testing.T) {
+	sys := newMockSystem(config.NewConfig())
+	defer func() { _ = sys.Shutdown() }()
+
+	scripts, err := resources.GetDefaultScripts()
+	if err != nil {
+		t.Fatalf("failed to obtain the default scripts: %v", err)
+	}
+
+	for _, script := range scripts {
+		if !strings.Contains(script, "\nmetadata = {") || !strings.Contains(script, "set_rate_limit(") {
+			continue
+		}
+
+		s := NewScript(script, sys)
+		if s == nil {
+			t.Errorf("failed to initialize a default script")
+			continue
+		}
+		// The rate limit must be available without running the start callback
+		if s.Metadata().RateLimit <= 0 {
+			t.Errorf("the %s script metadata does not declare the rate limit", s.String())
+		}
+	}
+}
@@ -26,7 +26,14 @@ func (s *Script) genNewName(ctx context.Context, name string) {
 }
 
 func (s *Script) newNameWithSrc(ctx context.Context, name, tag, src string) {
-	if domain := s.sys.Config().WhichDomain(name); domain != "" {
+	cfg := s.sys.Config()
+
+	domain := cfg.WhichDomain(name)
+	// The names in the parent zones of the sub-zones and exact hosts are recorded as context
+	if domain == "" && cfg.IsScopeContext(name) {
+		domain, _ = publicsuffix.EffectiveTLDPlusOne(name)
+	}
+	if domain != "" {
 		select {
 		case <-ctx.Done():
 		case <-s.Done():
@@ -185,6 +192,9 @@ func (s *Script) newAddr(L *lua.LState) int {
 	if reserved, _ := amassnet.IsReservedAddress(ip.String()); reserved {
 		return 0
 	}
+	if s.sys.Config().IsAddressExcluded(ip.String()) {
+		return 0
+	}
 	if ctx, err := extractContext(L.CheckUserData(1)); err == nil && !contextExpired(ctx) {
 		if name := L.CheckString(3); err == nil && name != "" {
 			if domain := s.sys.Config().WhichDomain(name); domain != "" {
@@ -9,9 +9,11 @@ import (
 	"errors"
 	"fmt"
 	"regexp"
+	"sync"
 
 	"github.com/OWASP/Amass/v3/config"
 	"github.com/OWASP/Amass/v3/net/dns"
+	"github.com/OWASP/Amass/v3/net/whois"
 	"github.com/OWASP/Amass/v3/requests"
 	"github.com/OWASP/Amass/v3/systems"
 	"github.com/caffix/service"
@@ -40,11 +42,17 @@ type Script struct {
 	startRet   chan error
 	stop       chan struct{}
 	SourceType string
+	metadata   *Metadata
+	bootstrap  *whois.Bootstrap
 	sys        systems.System
 	luaState   *lua.LState
 	cbs        *callbacks
 	subre      *regexp.Regexp
 	seconds    int
+	hits       int64
+	misses     int64
+	statsLock  sync.Mutex
+	stats      RequestStats
 	ctx        context.Context
 	cancel     context.CancelFunc
 }
@@ -83,6 +91,12 @@ func NewScript(script string, sys systems.System) *Script {
 		sys.Config().Log.Printf("Script: Failed to obtain the %s script type: %v", script, err)
 		return nil
 	}
+	// Pull the optional metadata from the script
+	s.metadata, err = s.scriptMetadata()
+	if err != nil {
+		sys.Config().Log.Printf("Script: Failed to obtain the %s script metadata: %v", script, err)
+		return nil
+	}
 
 	s.BaseService = *service.NewBaseService(s, name)
 	s.assignCallbacks()
@@ -101,7 +115,7 @@ func (s *Script) newLuaState(cfg *config.Config) *lua.LState {
 	})
 	s.luaState = L
 
-	registerSocketType(L)
+	s.registerSocketType(L)
 	L.PreloadModule("url", luaurl.Loader)
 	L.PreloadModule("json", luajson.Loader)
 	L.SetGlobal("config", L.NewFunction(s.config))
@@ -122,6 +136,9 @@ func (s *Script) newLuaState(cfg *config.Config) *lua.LState {
 	L.SetGlobal("request", L.NewFunction(s.request))
 	L.SetGlobal("scrape", L.NewFunction(s.scrape))
 	L.SetGlobal("crawl", L.NewFunction(s.crawl))
+	L.SetGlobal("cert_info", L.NewFunction(s.certInfo))
+	L.SetGlobal("whois", L.NewFunction(s.whoisLookup))
+	L.SetGlobal("rdap", L.NewFunction(s.rdapLookup))
 	L.SetGlobal("resolve", L.NewFunction(s.resolve))
 	L.SetGlobal("reverse_sweep", L.NewFunction(s.reverseSweep))
 	L.SetGlobal("zone_walk", L.NewFunction(s.zoneWalk))
@@ -224,6 +241,10 @@ func (s *Script) startScript() {
 		}
 	}
 
+	// Use the declared rate limit when the start callback did not set one
+	if s.seconds == 0 && s.metadata.RateLimit > 0 {
+		s.seconds = s.metadata.RateLimit
+	}
 	if s.seconds > 0 {
 		s.SetRateLimit(1)
 	}
@@ -10,6 +10,7 @@ import (
 	"io"
 	"net"
 	"strconv"
+	"strings"
 
 	amassnet "github.com/OWASP/Amass/v3/net"
 	lua "github.com/yuin/gopher-lua"
@@ -28,16 +29,16 @@ var connectMethods = map[string]lua.LGFunction{
 	"send":     connectSend,
 }
 
-func registerSocketType(L *lua.LState) {
+func (s *Script) registerSocketType(L *lua.LState) {
 	mt := L.NewTypeMetatable(luaSocketTypeName)
 
 	L.SetGlobal(luaSocketTypeName, mt)
-	L.SetField(mt, "connect", L.NewFunction(connect))
+	L.SetField(mt, "connect", L.NewFunction(s.connect))
 	L.SetField(mt, "__index", L.SetFuncs(L.NewTable(), connectMethods))
 }
 
-// Wrapper so that scripts can make DNS queries.
-func connect(L *lua.LState) int {
+// Wrapper so that scripts can establish network connections.
+func (s *Script) connect(L *lua.LState) int {
 	ctx, err := extractContext(L.CheckUserData(1))
 	host := L.CheckString(2)
 	port := int(L.CheckNumber(3))
@@ -49,7 +50,22 @@ func connect(L *lua.LState) int {
 	}
 
 	addr := net.JoinHostPort(host, strconv.Itoa(port))
-	conn, err := amassnet.DialContext(ctx, proto, addr)
+	p := s.proxy(addr)
+	// The proxies only relay TCP connections, and the datagrams must not bypass the proxy
+	if p != "" && strings.HasPrefix(strings.ToLower(proto), "udp") {
+		L.Push(lua.LNil)
+		L.Push(lua.LString("Failed to establish the connection: UDP cannot be proxied"))
+		return 2
+	}
+
+	dial, err := amassnet.ProxyDialContext(p)
+	if err != nil {
+		L.Push(lua.LNil)
+		L.Push(lua.LString(fmt.Sprintf("Failed to establish the connection: %v", err)))
+		return 2
+	}
+
+	conn, err := dial(ctx, proto, addr)
 	if err != nil {
 		L.Push(lua.LNil)
 		L.Push(lua.LString(fmt.Sprintf("Failed to establish the connection: %v", err)))
@@ -184,3 +184,57 @@ func TestSocketSend(t *testing.T) {
 		}
 	}
 }
+
+func TestSocketUDPWithProxy(t *testing.T) {
+	script, sys := setupMockScriptEnv(`
+		name="udp"
+		type="testing"
+
+		function vertical(ctx, domain)
+			local conn, err = socket.connect(ctx, "127.0.0.1", 8080, "udp")
+			if (err ~= nil and err ~= "") then
+				if (string.find(err, "UDP cannot be proxied", 1, true) ~= nil) then
+					new_name(ctx, "refused.owasp.org")
+				end
+				return
+			end
+
+			conn:send("Hello")
+			conn:close()
+			new_name(ctx, "sent.owasp.org")
+		end
+	`)
+	if script == nil || sys == nil {
+		t.Fatal("failed to initialize the scripting environment")
+	}
+	defer func() { _ = sys.Shutdown() }()
+
+	pc, err := net.ListenPacket("udp", "127.0.0.1:8080")
+	if err != nil {
+		t.Fatal("failed to listen on UDP port 8080")
+	}
+	defer pc.Close()
+
+	// The datagrams cannot be sent through the proxy, which only relays TCP connections
+	sys.Config().Proxy = "socks5://127.0.0.1:1"
+	sys.Config().AddDomain("owasp.org")
+	script.Input() <- &requests.DNSRequest{Domain: "owasp.org"}
+
+	timer := time.NewTimer(time.Duration(15) * time.Second)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		t.Fatal("the test timed out")
+	case msg := <-script.Output():
+		if ans, ok := msg.(*requests.DNSRequest); !ok || ans.Name != "refused.owasp.org" {
+			t.Errorf("the UDP socket was not refused when the proxy was configured: %v", msg)
+		}
+	}
+
+	// Nothing was sent directly to the target
+	_ = pc.SetReadDeadline(time.Now().Add(250 * time.Millisecond))
+	if _, _, err := pc.ReadFrom(make([]byte, 64)); err == nil {
+		t.Error("the datagram was sent directly to the target")
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package scripting
+
+import (
+	"time"
+)
+
+// RequestStats contains the measurements taken while a data source sends requests to its service.
+type RequestStats struct {
+	// The number of requests sent, not including responses obtained from the cache
+	Requests int64
+	// The number of requests that failed or returned an error status code
+	Errors int64
+	// The total time spent waiting for responses
+	Latency time.Duration
+	// The total time spent blocked by the data source rate limit
+	RateLimitWait time.Duration
+}
+
+// AverageLatency returns the mean time spent waiting for a response.
+func (rs *RequestStats) AverageLatency() time.Duration {
+	if rs.Requests == 0 {
+		return 0
+	}
+	return rs.Latency / time.Duration(rs.Requests)
+}
+
+// RequestStats returns a copy of the measurements taken for the requests sent by the script.
+func (s *Script) RequestStats() *RequestStats {
+	s.statsLock.Lock()
+	defer s.statsLock.Unlock()
+
+	stats := s.stats
+	return &stats
+}
+
+// CheckRateLimit blocks until the data source rate limit allows another request,
+// while tracking the amount of time spent waiting.
+func (s *Script) CheckRateLimit() {
+	start := time.Now()
+	s.BaseService.CheckRateLimit()
+
+	s.statsLock.Lock()
+	s.stats.RateLimitWait += time.Since(start)
+	s.statsLock.Unlock()
+}
+
+func (s *Script) recordRequest(latency time.Duration, failed bool) {
+	s.statsLock.Lock()
+	defer s.statsLock.Unlock()
+
+	s.stats.Requests++
+	s.stats.Latency += latency
+	if failed {
+		s.stats.Errors++
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package scripting
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/OWASP/Amass/v3/requests"
+)
+
+func TestRequestStats(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/error" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		fmt.Fprintln(w, "www.owasp.org")
+	}))
+	defer ts.Close()
+
+	script, sys := setupMockScriptEnv(fmt.Sprintf(`
+		name="stats"
+		type="testing"
+
+		function vertical(ctx, domain)
+			request(ctx, {['url']="%s/error"})
+
+			local resp, err = request(ctx, {['url']="%s/names"})
+			if (err == nil or err == "") then
+				send_names(ctx, resp.body)
+			end
+		end
+	`, ts.URL, ts.URL))
+	if script == nil || sys == nil {
+		t.Fatal("failed to initialize the scripting environment")
+	}
+	defer func() { _ = sys.Shutdown() }()
+
+	sys.Config().AddDomain("owasp.org")
+	script.Input() <- &requests.DNSRequest{Domain: "owasp.org"}
+
+	timer := time.NewTimer(15 * time.Second)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		t.Fatal("the test timed out")
+	case <-script.Output():
+	}
+
+	stats := script.(*Script).RequestStats()
+	if stats.Requests != 2 {
+		t.Errorf("expected 2 requests to be measured, got %d", stats.Requests)
+	}
+	if stats.Errors != 1 {
+		t.Errorf("expected 1 request error to be measured, got %d", stats.Errors)
+	}
+	if stats.Latency <= 0 || stats.AverageLatency() > stats.Latency {
+		t.Errorf("the request latency was not measured correctly: %v", stats)
+	}
+}
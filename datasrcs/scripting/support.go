@@ -150,3 +150,16 @@ func getNumberField(L *lua.LState, t lua.LValue, key string) (float64, bool) {
 	}
 	return 0, false
 }
+
+func getStringSliceField(L *lua.LState, t lua.LValue, key string) []string {
+	var results []string
+
+	if tb, ok := L.GetField(t, key).(*lua.LTable); ok {
+		tb.ForEach(func(_, v lua.LValue) {
+			if str, ok := v.(lua.LString); ok && str != "" {
+				results = append(results, string(str))
+			}
+		})
+	}
+	return results
+}
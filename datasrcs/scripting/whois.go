@@ -0,0 +1,107 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package scripting
+
+import (
+	"errors"
+
+	"github.com/OWASP/Amass/v3/net/whois"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// Wrapper so that scripts can perform WHOIS lookups that follow referrals.
+func (s *Script) whoisLookup(L *lua.LState) int {
+	ctx, err := extractContext(L.CheckUserData(1))
+	domain := L.CheckString(2)
+	server := L.OptString(3, "")
+	if err != nil || domain == "" {
+		L.Push(lua.LNil)
+		L.Push(lua.LString("Proper parameters were not provided"))
+		return 2
+	}
+
+	rec, err := whois.Lookup(ctx, server, domain)
+	if err != nil {
+		L.Push(lua.LNil)
+		L.Push(lua.LString(err.Error()))
+		return 2
+	}
+
+	L.Push(recordToTable(L, rec))
+	L.Push(lua.LNil)
+	return 2
+}
+
+// Wrapper so that scripts can perform RDAP domain queries.
+func (s *Script) rdapLookup(L *lua.LState) int {
+	ctx, err := extractContext(L.CheckUserData(1))
+	domain := L.CheckString(2)
+	if err != nil || domain == "" {
+		L.Push(lua.LNil)
+		L.Push(lua.LString("Proper parameters were not provided"))
+		return 2
+	}
+
+	b, err := s.rdapBootstrap()
+	if err != nil {
+		L.Push(lua.LNil)
+		L.Push(lua.LString(err.Error()))
+		return 2
+	}
+
+	rec, err := b.Lookup(ctx, domain)
+	if err != nil {
+		L.Push(lua.LNil)
+		L.Push(lua.LString(err.Error()))
+		return 2
+	}
+
+	L.Push(recordToTable(L, rec))
+	L.Push(lua.LNil)
+	return 2
+}
+
+func (s *Script) rdapBootstrap() (*whois.Bootstrap, error) {
+	if s.bootstrap != nil {
+		return s.bootstrap, nil
+	}
+
+	path := s.sys.Config().RDAPBootstrap
+	if path == "" {
+		return nil, errors.New("the RDAP bootstrap file was not provided in the configuration")
+	}
+
+	b, err := whois.LoadBootstrap(path)
+	if err != nil {
+		return nil, err
+	}
+
+	s.bootstrap = b
+	return b, nil
+}
+
+func recordToTable(L *lua.LState, rec *whois.Record) *lua.LTable {
+	tb := L.NewTable()
+
+	tb.RawSetString("domain", lua.LString(rec.Domain))
+	tb.RawSetString("registrar", lua.LString(rec.Registrar))
+	tb.RawSetString("company", lua.LString(rec.Company))
+	tb.RawSetString("email", lua.LString(rec.Email()))
+	tb.RawSetString("server", lua.LString(rec.Server))
+	tb.RawSetString("raw", lua.LString(rec.Raw))
+
+	emails := L.NewTable()
+	for _, email := range rec.Emails {
+		emails.Append(lua.LString(email))
+	}
+	tb.RawSetString("emails", emails)
+
+	servers := L.NewTable()
+	for _, ns := range rec.NameServers {
+		servers.Append(lua.LString(ns))
+	}
+	tb.RawSetString("name_servers", servers)
+	return tb
+}
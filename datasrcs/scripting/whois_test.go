@@ -0,0 +1,72 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package scripting
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/OWASP/Amass/v3/requests"
+)
+
+func TestWhois(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to start the WHOIS server: %v", err)
+	}
+	defer ln.Close()
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		if _, err := bufio.NewReader(conn).ReadString('\n'); err == nil {
+			_, _ = io.WriteString(conn, "Domain Name: OWASP.ORG\nRegistrant Organization: OWASP Foundation\nName Server: ns1.owasp.org\n")
+		}
+	}()
+
+	script, sys := setupMockScriptEnv(fmt.Sprintf(`
+		name="whois"
+		type="testing"
+
+		function horizontal(ctx, domain)
+			local rec, err = whois(ctx, domain, "%s")
+			if (err ~= nil and err ~= "") then
+				log(ctx, err)
+				return
+			end
+
+			if (rec.company == "OWASP Foundation") then
+				new_name(ctx, rec.name_servers[1])
+			end
+		end
+	`, ln.Addr().String()))
+	if script == nil || sys == nil {
+		t.Fatal("failed to initialize the scripting environment")
+	}
+	defer func() { _ = sys.Shutdown() }()
+
+	sys.Config().AddDomain("owasp.org")
+	script.Input() <- &requests.WhoisRequest{Domain: "owasp.org"}
+
+	timer := time.NewTimer(15 * time.Second)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		t.Error("the test timed out")
+	case msg := <-script.Output():
+		if ans, ok := msg.(*requests.DNSRequest); !ok || ans.Name != "ns1.owasp.org" {
+			t.Errorf("failed to obtain the WHOIS details: %v", msg)
+		}
+	}
+}
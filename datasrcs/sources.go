@@ -5,7 +5,10 @@
 package datasrcs
 
 import (
+	"fmt"
 	"sort"
+	"strings"
+	"sync"
 
 	"github.com/OWASP/Amass/v3/config"
 	"github.com/OWASP/Amass/v3/datasrcs/scripting"
@@ -14,9 +17,80 @@ import (
 	"github.com/caffix/stringset"
 )
 
+// MetadataSource is implemented by data sources that declare details such as required credentials.
+type MetadataSource interface {
+	Metadata() *scripting.Metadata
+}
+
+// CacheStatsSource is implemented by data sources that reuse responses cached in the graph database.
+type CacheStatsSource interface {
+	CacheStats() (hits int64, misses int64)
+}
+
+// RequestStatsSource is implemented by data sources that measure the requests sent to their services.
+type RequestStatsSource interface {
+	RequestStats() *scripting.RequestStats
+}
+
+// ProvenanceSource is implemented by data sources that record where each discovered name was found.
+type ProvenanceSource interface {
+	Provenance() map[string][]string
+}
+
+// SourceConstructor returns a data source service initialized for the provided System.
+type SourceConstructor func(sys systems.System) service.Service
+
+var (
+	registryLock sync.Mutex
+	registry     = make(map[string]SourceConstructor)
+)
+
+func init() {
+	_ = RegisterSource("CTLogs", func(sys systems.System) service.Service { return NewCTLogs(sys) })
+	_ = RegisterSource("LocalFiles", func(sys systems.System) service.Service { return NewLocalFiles(sys) })
+	_ = RegisterSource("RADb", func(sys systems.System) service.Service { return NewRADb(sys) })
+}
+
+// RegisterSource makes the Go data source built by the constructor available through GetAllSources.
+// It is intended to be called from the init function of the package implementing the data source.
+func RegisterSource(name string, ctor SourceConstructor) error {
+	registryLock.Lock()
+	defer registryLock.Unlock()
+
+	key := strings.ToLower(strings.TrimSpace(name))
+	if key == "" || ctor == nil {
+		return fmt.Errorf("RegisterSource: the arguments provided for data source '%s' are invalid", name)
+	}
+	if _, found := registry[key]; found {
+		return fmt.Errorf("RegisterSource: the data source '%s' has already been registered", name)
+	}
+
+	registry[key] = ctor
+	return nil
+}
+
+func registeredSources(sys systems.System) []service.Service {
+	registryLock.Lock()
+	defer registryLock.Unlock()
+
+	var srvs []service.Service
+	for _, ctor := range registry {
+		if srv := ctor(sys); srv != nil {
+			srvs = append(srvs, srv)
+		}
+	}
+	return srvs
+}
+
 // GetAllSources returns a slice of all data source services initialized.
 func GetAllSources(sys systems.System) []service.Service {
-	srvs := []service.Service{NewRADb(sys)}
+	srvs := registeredSources(sys)
+
+	for _, dsc := range sys.Config().DataSourceConfigs() {
+		if dsc.Plugin != "" {
+			srvs = append(srvs, NewPlugin(dsc.Name, dsc.Plugin, sys))
+		}
+	}
 
 	if scripts, err := sys.Config().AcquireScripts(); err == nil {
 		for _, script := range scripts {
@@ -147,7 +147,8 @@ func (dt *dnsTask) rootTaskFunc() pipeline.TaskFunc {
 			}
 		}
 
-		if r != nil && dt.enum.Config.IsDomainInScope(r.Name) {
+		// The exact hosts are not zones, so the zone queries and transfers are not attempted
+		if r != nil && dt.enum.Config.IsDomainInScope(r.Name) && !dt.enum.Config.IsExactHost(r.Name) {
 			go dt.subdomainQueries(ctx, r, tp)
 		}
 		return data, nil
@@ -539,7 +540,8 @@ func (e *Enumeration) dnsQuery(ctx context.Context, name string, qtype uint16, r
 }
 
 func (e *Enumeration) wildcardDetected(ctx context.Context, req *requests.DNSRequest, resp *dns.Msg) bool {
-	if !requests.TrustedTag(req.Tag) && e.Sys.TrustedResolvers().WildcardDetected(ctx, resp, req.Domain) {
+	if !e.trust.Trusted(req.Source, req.Tag) && e.Sys.TrustedResolvers().WildcardDetected(ctx, resp, req.Domain) {
+		e.stats.wildcard(req.Name, req.Source)
 		return true
 	}
 	return false
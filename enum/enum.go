@@ -7,6 +7,7 @@ package enum
 import (
 	"context"
 	"sync"
+	"time"
 
 	"github.com/OWASP/Amass/v3/config"
 	"github.com/OWASP/Amass/v3/datasrcs"
@@ -34,17 +35,48 @@ type Enumeration struct {
 	requests queue.Queue
 	plock    sync.Mutex
 	pending  bool
+	stats    *sourceStats
+	trust    *TrustScores
+	// The data source reports for the completed enumeration
+	reportLock sync.Mutex
+	reports    []*SourceReport
+	// The observation times of the names imported from Passive DNS records
+	pdnsLock  sync.Mutex
+	pdnsTimes map[string]*seenTimes
 }
 
 // NewEnumeration returns an initialized Enumeration that has not been started yet.
 func NewEnumeration(cfg *config.Config, sys systems.System, graph *netmap.Graph) *Enumeration {
-	return &Enumeration{
-		Config:   cfg,
-		Sys:      sys,
-		graph:    graph,
-		srcs:     datasrcs.SelectedDataSources(cfg, sys.DataSources()),
-		requests: queue.NewQueue(),
+	e := &Enumeration{
+		Config:    cfg,
+		Sys:       sys,
+		graph:     graph,
+		srcs:      datasrcs.SelectedDataSources(cfg, sys.DataSources()),
+		requests:  queue.NewQueue(),
+		stats:     newSourceStats(),
+		pdnsTimes: make(map[string]*seenTimes),
 	}
+
+	e.trust = LoadTrustScores(context.Background(), sys.GraphDatabases()...)
+	e.trust.setTags(e.sourceTags())
+	return e
+}
+
+// Returns the tags for the data sources and the names provided by the enumeration itself.
+func (e *Enumeration) sourceTags() map[string]string {
+	tags := map[string]string{
+		"DNS":          requests.DNS,
+		"Reverse DNS":  requests.DNS,
+		"NSEC Walk":    requests.DNS,
+		"DNS Zone XFR": requests.AXFR,
+		"Active Cert":  requests.CERT,
+		"Active Crawl": requests.CRAWL,
+	}
+
+	for _, src := range e.Sys.DataSources() {
+		tags[src.String()] = src.Description()
+	}
+	return tags
 }
 
 // Start begins the vertical domain correlation process.
@@ -95,6 +127,12 @@ func (e *Enumeration) Start(ctx context.Context) error {
 	 */
 	go e.submitKnownNames()
 	go e.submitProvidedNames()
+	// Imported records are stored directly during passive enumerations
+	if e.Config.Passive {
+		e.importFiles()
+	} else {
+		go e.importFiles()
+	}
 
 	var err error
 	if e.Config.Passive {
@@ -104,9 +142,29 @@ func (e *Enumeration) Start(ctx context.Context) error {
 		// Ensure all data has been stored
 		<-e.store.Stop()
 	}
+
+	e.reportSources()
 	return err
 }
 
+// Builds the data source reports and stores them with the enumeration event.
+func (e *Enumeration) reportSources() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	e.storePassiveDNSTimes(ctx)
+	e.storeProvenance(ctx)
+	reports := e.buildSourceReports(ctx)
+	if err := e.storeSourceReports(ctx, reports); err != nil {
+		e.Config.Log.Printf("Failed to store the data source reports: %v", err)
+	}
+	e.stats.close()
+
+	e.reportLock.Lock()
+	e.reports = reports
+	e.reportLock.Unlock()
+}
+
 // Release the root domain names to the input source and each data source.
 func (e *Enumeration) submitDomainNames() {
 	for _, domain := range e.Config.Domains() {
@@ -120,13 +178,24 @@ func (e *Enumeration) submitDomainNames() {
 		e.nameSrc.newName(req)
 		e.sendRequests(req.Clone().(*requests.DNSRequest))
 	}
+	// The exact hosts are resolved without searching the data sources for names below them
+	for _, host := range e.Config.Hosts() {
+		e.nameSrc.newName(&requests.DNSRequest{
+			Name:   host,
+			Domain: e.Config.WhichDomain(host),
+			Tag:    requests.DNS,
+			Source: "DNS",
+		})
+	}
 }
 
 // If requests were made for specific ASNs, then those requests are
 // sent to included data sources at this point.
 func (e *Enumeration) submitASNs() {
 	for _, asn := range e.Config.ASNs {
-		e.sendRequests(&requests.ASNRequest{ASN: asn})
+		if !e.Config.IsASNExcluded(asn) {
+			e.sendRequests(&requests.ASNRequest{ASN: asn})
+		}
 	}
 }
 
@@ -0,0 +1,204 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package enum
+
+import (
+	"context"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/OWASP/Amass/v3/format"
+	"github.com/OWASP/Amass/v3/requests"
+	"github.com/OWASP/Amass/v3/systems"
+	"github.com/caffix/netmap"
+	"github.com/miekg/dns"
+)
+
+// Imports the Passive DNS records and the output of other reconnaissance tools provided by the configuration.
+func (e *Enumeration) importFiles() {
+	e.importPassiveDNS()
+	e.importToolOutput()
+}
+
+// Reads the output files of other reconnaissance tools provided by the configuration. During passive
+// enumerations, the names and records are stored directly in the graph database, otherwise the names
+// and addresses are validated by the pipeline.
+func (e *Enumeration) importToolOutput() {
+	for _, path := range e.Config.ImportFiles {
+		f, err := os.Open(path)
+		if err != nil {
+			e.Config.Log.Printf("Failed to open the import file %s: %v", path, err)
+			continue
+		}
+
+		var count int
+		tool, err := format.ReadImport(f, func(n *format.ImportedName) {
+			select {
+			case <-e.done:
+			default:
+				if e.importedName(n) {
+					count++
+				}
+			}
+		})
+		f.Close()
+
+		if err != nil {
+			e.Config.Log.Printf("Failed to import the file %s: %v", path, err)
+			continue
+		}
+		if e.Config.Verbose {
+			e.Config.Log.Printf("Imported %d names from the %s output in %s", count, tool, path)
+		}
+	}
+}
+
+func (e *Enumeration) importedName(n *format.ImportedName) bool {
+	domain, context := e.importScope(n.Name)
+	if domain == "" && !context {
+		return false
+	}
+
+	e.stats.submitted(n.Name, n.Source)
+	if context {
+		e.storeContextName(n.Name, n.Source)
+		return true
+	}
+
+	in := *n
+	in.Records = e.scopeAnswers(n.Records)
+	if e.Config.Passive {
+		if err := StoreImportedName(e.ctx, e.graph, e.Config.UUID.String(), &in); err != nil {
+			e.Config.Log.Printf("Failed to store the imported name %s: %v", in.Name, err)
+		}
+		return true
+	}
+
+	e.nameSrc.newName(&requests.DNSRequest{
+		Name:    in.Name,
+		Domain:  domain,
+		Records: in.Records,
+		Tag:     in.Tag,
+		Source:  in.Source,
+	})
+	for _, addr := range in.Addresses() {
+		e.nameSrc.newAddr(&requests.AddrRequest{
+			Address: addr,
+			InScope: true,
+			Domain:  domain,
+			Tag:     in.Tag,
+			Source:  in.Source,
+		})
+	}
+	return true
+}
+
+// Returns the root domain name of the imported name, or an empty string when the name is out of scope.
+// As with the names provided by the data sources, the names in the parent zones of the sub-zones and
+// exact hosts are only recorded as context for the enumeration, which is indicated by the second value.
+func (e *Enumeration) importScope(name string) (string, bool) {
+	if e.Config.IsScopeContext(name) {
+		return "", true
+	}
+
+	domain := e.Config.WhichDomain(name)
+	if domain == "" || e.Config.Blacklisted(name) {
+		return "", false
+	}
+	return domain, false
+}
+
+// Stores the imported name as context for the enumeration, without the records of the name.
+func (e *Enumeration) storeContextName(name, source string) {
+	if _, err := e.graph.UpsertFQDN(e.ctx, name, source, e.Config.UUID.String()); err != nil {
+		e.Config.Log.Print(err.Error())
+	}
+}
+
+// StoreImportedName stores the name and the DNS records imported from another reconnaissance tool
+// in the graph database, attributed to the tool and associated with the event.
+func StoreImportedName(ctx context.Context, g *netmap.Graph, uuid string, n *format.ImportedName) error {
+	return storeAnswers(ctx, g, uuid, n.Name, n.Source, n.Records)
+}
+
+// Stores the name and the supported DNS records without performing any resolution.
+func storeAnswers(ctx context.Context, g *netmap.Graph, uuid, name, source string, answers []requests.DNSAnswer) error {
+	if _, err := g.UpsertFQDN(ctx, name, source, uuid); err != nil {
+		return err
+	}
+
+	var lastErr error
+	for _, ans := range answers {
+		data := strings.Trim(strings.TrimSpace(ans.Data), ".")
+		if data == "" {
+			continue
+		}
+
+		var err error
+		switch uint16(ans.Type) {
+		case dns.TypeA:
+			if addr := answerAddress(ans); addr != "" {
+				err = g.UpsertA(ctx, name, addr, source, uuid)
+			}
+		case dns.TypeAAAA:
+			if addr := answerAddress(ans); addr != "" {
+				err = g.UpsertAAAA(ctx, name, addr, source, uuid)
+			}
+		case dns.TypeCNAME:
+			err = g.UpsertCNAME(ctx, name, strings.ToLower(data), source, uuid)
+		case dns.TypeNS:
+			err = g.UpsertNS(ctx, name, strings.ToLower(data), source, uuid)
+		case dns.TypeMX:
+			// The data includes the preference before the mail exchange
+			if fields := strings.Fields(data); len(fields) > 0 {
+				target := strings.Trim(strings.ToLower(fields[len(fields)-1]), ".")
+				err = g.UpsertMX(ctx, name, target, source, uuid)
+			}
+		}
+		if err != nil {
+			lastErr = err
+		}
+	}
+	return lastErr
+}
+
+// Returns the address provided by the A or AAAA record, or an empty string when the data
+// is not an address of the record type.
+func answerAddress(ans requests.DNSAnswer) string {
+	ip := net.ParseIP(strings.TrimSpace(ans.Data))
+	if ip == nil {
+		return ""
+	}
+
+	switch uint16(ans.Type) {
+	case dns.TypeA:
+		if ip4 := ip.To4(); ip4 != nil {
+			return ip4.String()
+		}
+	case dns.TypeAAAA:
+		if ip.To4() == nil {
+			return ip.String()
+		}
+	}
+	return ""
+}
+
+// Removes the malformed A and AAAA records and the records of the addresses excluded from the scope.
+func (e *Enumeration) scopeAnswers(answers []requests.DNSAnswer) []requests.DNSAnswer {
+	var keep []requests.DNSAnswer
+
+	for _, ans := range answers {
+		if t := uint16(ans.Type); t == dns.TypeA || t == dns.TypeAAAA {
+			addr := answerAddress(ans)
+			if addr == "" || systems.IsAddressExcluded(e.Sys, addr) {
+				continue
+			}
+			ans.Data = addr
+		}
+		keep = append(keep, ans)
+	}
+	return keep
+}
@@ -0,0 +1,123 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package enum
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/OWASP/Amass/v3/config"
+	"github.com/OWASP/Amass/v3/requests"
+	"github.com/OWASP/Amass/v3/systems"
+	"github.com/caffix/netmap"
+)
+
+func TestImportToolOutput(t *testing.T) {
+	ctx := context.Background()
+	g := netmap.NewGraph(netmap.NewCayleyGraphMemory())
+	defer g.Close()
+
+	dir := t.TempDir()
+	massdns := filepath.Join(dir, "massdns.txt")
+	if err := os.WriteFile(massdns, []byte("www.owasp.org. A 72.237.4.113\nwww.example.com. A 1.1.1.1\n"), 0644); err != nil {
+		t.Fatalf("failed to write the massdns file: %v", err)
+	}
+	subfinder := filepath.Join(dir, "subfinder.json")
+	if err := os.WriteFile(subfinder, []byte(`{"host":"api.owasp.org","source":"crtsh"}`+"\n"), 0644); err != nil {
+		t.Fatalf("failed to write the subfinder file: %v", err)
+	}
+
+	cfg := config.NewConfig()
+	cfg.Passive = true
+	cfg.AddDomain("owasp.org")
+	cfg.ImportFiles = []string{massdns, subfinder}
+
+	e := &Enumeration{
+		Config: cfg,
+		Sys:    &systems.SimpleSystem{Cfg: cfg, ASNCache: requests.NewASNCache()},
+		ctx:    ctx,
+		graph:  g,
+		done:   make(chan struct{}),
+		stats:  newSourceStats(),
+	}
+	e.importToolOutput()
+
+	uuid := cfg.UUID.String()
+	if pairs, err := g.NamesToAddrs(ctx, uuid, "www.owasp.org"); err != nil || len(pairs) != 1 {
+		t.Errorf("the massdns address was not stored: %v", pairs)
+	}
+	if srcs, err := g.NodeSources(ctx, netmap.Node("api.owasp.org"), uuid); err != nil || len(srcs) != 1 || srcs[0] != "Subfinder" {
+		t.Errorf("the subfinder name was not attributed to the tool: %v", srcs)
+	}
+	if _, err := g.ReadNode(ctx, "www.example.com", netmap.TypeFQDN); err == nil {
+		t.Errorf("the out of scope name was stored")
+	}
+
+	reports := make(map[string]*SourceReport)
+	for _, r := range e.buildSourceReports(ctx) {
+		reports[r.Source] = r
+	}
+	for _, src := range []string{"MassDNS", "Subfinder"} {
+		if r, found := reports[src]; !found || r.Submitted != 1 || r.Validated != 1 {
+			t.Errorf("unexpected source report for %s: %+v", src, r)
+		}
+	}
+}
+
+func TestImportScope(t *testing.T) {
+	ctx := context.Background()
+	g := netmap.NewGraph(netmap.NewCayleyGraphMemory())
+	defer g.Close()
+
+	massdns := filepath.Join(t.TempDir(), "massdns.txt")
+	if err := os.WriteFile(massdns, []byte("www.dev.example.com. A 72.237.4.113\n"+
+		"example.com. A 72.237.4.114\n"+
+		"x.app.example.net. A 72.237.4.115\n"+
+		"app.example.net. A 192.168.1.1\n"+
+		"www.other.com. A 72.237.4.116\n"), 0644); err != nil {
+		t.Fatalf("failed to write the massdns file: %v", err)
+	}
+
+	cfg := config.NewConfig()
+	cfg.Passive = true
+	cfg.AddDomain("dev.example.com")
+	cfg.AddHost("app.example.net")
+	_, excluded, _ := net.ParseCIDR("192.168.1.0/24")
+	cfg.ExcludedCIDRs = []*net.IPNet{excluded}
+	cfg.ImportFiles = []string{massdns}
+
+	e := &Enumeration{
+		Config: cfg,
+		Sys:    &systems.SimpleSystem{Cfg: cfg, ASNCache: requests.NewASNCache()},
+		ctx:    ctx,
+		graph:  g,
+		done:   make(chan struct{}),
+		stats:  newSourceStats(),
+	}
+	defer e.stats.close()
+	e.importToolOutput()
+
+	uuid := cfg.UUID.String()
+	if pairs, err := g.NamesToAddrs(ctx, uuid, "www.dev.example.com"); err != nil || len(pairs) != 1 {
+		t.Errorf("the in scope address was not stored: %v", pairs)
+	}
+	// The names in the parent zone and below the exact host are only recorded as context
+	for _, name := range []string{"example.com", "x.app.example.net", "app.example.net"} {
+		if _, err := g.ReadNode(ctx, name, netmap.TypeFQDN); err != nil {
+			t.Errorf("the name %s was not stored", name)
+		}
+	}
+	if _, err := g.ReadNode(ctx, "www.other.com", netmap.TypeFQDN); err == nil {
+		t.Error("the out of scope name was stored")
+	}
+	for _, addr := range []string{"72.237.4.114", "72.237.4.115", "192.168.1.1", "72.237.4.116"} {
+		if _, err := g.ReadNode(ctx, addr, netmap.TypeAddr); err == nil {
+			t.Errorf("the address %s of a context name or excluded netblock was stored", addr)
+		}
+	}
+}
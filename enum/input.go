@@ -14,6 +14,7 @@ import (
 	amassnet "github.com/OWASP/Amass/v3/net"
 	"github.com/OWASP/Amass/v3/net/dns"
 	"github.com/OWASP/Amass/v3/requests"
+	"github.com/OWASP/Amass/v3/systems"
 	"github.com/caffix/pipeline"
 	"github.com/caffix/queue"
 	"github.com/caffix/service"
@@ -110,7 +111,10 @@ func (r *enumSource) newName(req *requests.DNSRequest) {
 		r.releaseOutput(1)
 		return
 	}
-	if r.enum.Config.Blacklisted(req.Name) {
+	// Names in the parent zones of the sub-zones and exact hosts are only recorded, unless the
+	// name was obtained from the DNS records of an in scope name, such as the target of a CNAME
+	if req.Source != "DNS" && r.enum.Config.IsScopeContext(req.Name) {
+		r.recordContext(req)
 		r.releaseOutput(1)
 		return
 	}
@@ -118,7 +122,8 @@ func (r *enumSource) newName(req *requests.DNSRequest) {
 		r.releaseOutput(1)
 		return
 	}
-	r.queue.Append(req)
+	// Names from data sources with better validation histories are processed first
+	r.queue.AppendPriority(req, r.enum.trust.Priority(req.Source))
 }
 
 func (r *enumSource) newAddr(req *requests.AddrRequest) {
@@ -141,6 +146,11 @@ func (r *enumSource) newAddr(req *requests.AddrRequest) {
 }
 
 func (r *enumSource) accept(s, tag, source string, name bool) bool {
+	// Names and addresses excluded from the scope are never investigated
+	if (name && r.enum.Config.Blacklisted(s)) || (!name && systems.IsAddressExcluded(r.enum.Sys, s)) {
+		return false
+	}
+
 	trusted := requests.TrustedTag(tag)
 	// Do not submit names from untrusted sources, after already receiving the name
 	// from a trusted source
@@ -277,6 +287,7 @@ func (r *enumSource) monitorDataSrcOutput(srv service.Service) {
 
 			switch req := in.(type) {
 			case *requests.DNSRequest:
+				r.enum.stats.submitted(req.Name, srv.String())
 				r.newName(req)
 			case *requests.AddrRequest:
 				r.newAddr(req)
@@ -359,6 +370,16 @@ loop:
 	}
 }
 
+// Stores the name in the graph database as context for the enumeration, without investigating the name.
+func (r *enumSource) recordContext(req *requests.DNSRequest) {
+	if r.filter.TestAndAdd([]byte(req.Name + req.Source)) {
+		return
+	}
+	if _, err := r.enum.graph.UpsertFQDN(r.enum.ctx, req.Name, req.Source, r.enum.Config.UUID.String()); err != nil {
+		r.enum.Config.Log.Print(err.Error())
+	}
+}
+
 func (r *enumSource) addSourceToEntry(uuid, name, source string) bool {
 	if _, err := r.enum.graph.ReadNode(r.enum.ctx, name, "fqdn"); err == nil {
 		_, _ = r.enum.graph.UpsertFQDN(r.enum.ctx, name, source, uuid)
@@ -0,0 +1,187 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package enum
+
+import (
+	"context"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/OWASP/Amass/v3/format"
+	"github.com/OWASP/Amass/v3/requests"
+	"github.com/caffix/netmap"
+	"github.com/miekg/dns"
+)
+
+const (
+	// PassiveDNSSource is the source name assigned to names imported from Passive DNS records.
+	PassiveDNSSource = "Passive DNS"
+	// FirstSeenPredicate is the FQDN property storing the first time the name was observed.
+	FirstSeenPredicate = "first_seen"
+	// LastSeenPredicate is the FQDN property storing the last time the name was observed.
+	LastSeenPredicate = "last_seen"
+)
+
+type seenTimes struct {
+	first time.Time
+	last  time.Time
+}
+
+// Reads the Passive DNS files provided by the configuration. During passive enumerations, the records
+// are stored directly in the graph database, otherwise the names are validated by the pipeline.
+func (e *Enumeration) importPassiveDNS() {
+	for _, path := range e.Config.PassiveDNSFiles {
+		f, err := os.Open(path)
+		if err != nil {
+			e.Config.Log.Printf("Failed to open the Passive DNS file %s: %v", path, err)
+			continue
+		}
+
+		malformed, err := format.ReadPassiveDNS(f, func(rec *format.PassiveDNSRecord) {
+			select {
+			case <-e.done:
+			default:
+				e.passiveDNSRecord(rec)
+			}
+		})
+		f.Close()
+
+		if err != nil {
+			e.Config.Log.Printf("Failed to import the Passive DNS file %s: %v", path, err)
+		}
+		if malformed > 0 {
+			e.Config.Log.Printf("Skipped %d malformed records in the Passive DNS file %s", malformed, path)
+		}
+	}
+}
+
+func (e *Enumeration) passiveDNSRecord(rec *format.PassiveDNSRecord) {
+	name := rec.RRName
+	domain, context := e.importScope(name)
+	if domain == "" && !context {
+		return
+	}
+	if context {
+		e.pdnsSeen(name, rec)
+		e.stats.submitted(name, PassiveDNSSource)
+		e.storeContextName(name, PassiveDNSSource)
+		return
+	}
+
+	var answers []requests.DNSAnswer
+	if qtype, found := dns.StringToType[rec.RRType]; found {
+		for _, data := range rec.RData {
+			answers = append(answers, requests.DNSAnswer{
+				Name: name,
+				Type: int(qtype),
+				Data: strings.Trim(strings.TrimSpace(data), "."),
+			})
+		}
+	}
+
+	answers = e.scopeAnswers(answers)
+
+	e.pdnsSeen(name, rec)
+	e.stats.submitted(name, PassiveDNSSource)
+	if e.Config.Passive {
+		if err := storeAnswers(e.ctx, e.graph, e.Config.UUID.String(), name, PassiveDNSSource, answers); err != nil {
+			e.Config.Log.Printf("Failed to store the Passive DNS records for %s: %v", name, err)
+		}
+		return
+	}
+
+	e.nameSrc.newName(&requests.DNSRequest{
+		Name:    name,
+		Domain:  domain,
+		Records: answers,
+		Tag:     requests.EXTERNAL,
+		Source:  PassiveDNSSource,
+	})
+}
+
+// Keeps the earliest and latest observation times for the name.
+func (e *Enumeration) pdnsSeen(name string, rec *format.PassiveDNSRecord) {
+	if rec.TimeFirst == 0 && rec.TimeLast == 0 {
+		return
+	}
+
+	first, last := rec.FirstSeen(), rec.LastSeen()
+	if rec.TimeFirst == 0 {
+		first = last
+	} else if rec.TimeLast == 0 {
+		last = first
+	}
+
+	e.pdnsLock.Lock()
+	defer e.pdnsLock.Unlock()
+
+	if t, found := e.pdnsTimes[name]; found {
+		if first.Before(t.first) {
+			t.first = first
+		}
+		if last.After(t.last) {
+			t.last = last
+		}
+		return
+	}
+	e.pdnsTimes[name] = &seenTimes{first: first, last: last}
+}
+
+// Stores the observation times of the imported names that were found in the graph database.
+func (e *Enumeration) storePassiveDNSTimes(ctx context.Context) {
+	e.pdnsLock.Lock()
+	defer e.pdnsLock.Unlock()
+
+	for name, t := range e.pdnsTimes {
+		node, err := e.graph.ReadNode(ctx, name, netmap.TypeFQDN)
+		if err != nil {
+			continue
+		}
+
+		_ = e.graph.UpsertProperty(ctx, node, FirstSeenPredicate, t.first.Format(time.RFC3339))
+		_ = e.graph.UpsertProperty(ctx, node, LastSeenPredicate, t.last.Format(time.RFC3339))
+	}
+	e.pdnsTimes = make(map[string]*seenTimes)
+}
+
+// ReadSeenTimes returns the earliest and latest observation times stored for the FQDN in the graph database.
+func ReadSeenTimes(ctx context.Context, g *netmap.Graph, name string) (time.Time, time.Time, error) {
+	var first, last time.Time
+
+	node, err := g.ReadNode(ctx, name, netmap.TypeFQDN)
+	if err != nil {
+		return first, last, err
+	}
+
+	properties, err := g.ReadProperties(ctx, node, FirstSeenPredicate, LastSeenPredicate)
+	if err != nil {
+		return first, last, err
+	}
+
+	for _, p := range properties {
+		v, ok := p.Value.Native().(string)
+		if !ok {
+			continue
+		}
+
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			continue
+		}
+
+		switch p.Predicate {
+		case FirstSeenPredicate:
+			if first.IsZero() || t.Before(first) {
+				first = t
+			}
+		case LastSeenPredicate:
+			if t.After(last) {
+				last = t
+			}
+		}
+	}
+	return first, last, nil
+}
@@ -0,0 +1,103 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package enum
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/OWASP/Amass/v3/config"
+	"github.com/OWASP/Amass/v3/requests"
+	"github.com/OWASP/Amass/v3/systems"
+	"github.com/caffix/netmap"
+	"github.com/caffix/stringset"
+)
+
+func TestImportPassiveDNS(t *testing.T) {
+	ctx := context.Background()
+	g := netmap.NewGraph(netmap.NewCayleyGraphMemory())
+	defer g.Close()
+
+	path := filepath.Join(t.TempDir(), "pdns.json")
+	records := `{"rrname":"www.owasp.org.","rrtype":"A","rdata":"72.237.4.113","time_first":1500000000,"time_last":1550000000}
+{"rrname":"www.owasp.org","rrtype":"A","rdata":"72.237.4.114","time_first":1400000000,"time_last":1600000000}
+{"rrname":"owasp.org","rrtype":"MX","rdata":["10 mail.owasp.org."]}
+{"rrname":"www.example.com","rrtype":"A","rdata":"1.1.1.1","time_first":1400000000,"time_last":1600000000}
+{"rrname":"www.owasp.org","rrtype":"A","rdata":["not-an-address","2001:db8::1"]}
+{"rrname":"www.owasp.org","rrtype":"AAAA","rdata":"72.237.4.115"}
+{"rrname":"api.owasp.org","rrtype":"A","rdata":["192.168.1.1","72.237.5.1"]}
+{"rrname":"x.app.example.net","rrtype":"A","rdata":"72.237.4.116"}
+`
+	if err := os.WriteFile(path, []byte(records), 0644); err != nil {
+		t.Fatalf("failed to write the Passive DNS file: %v", err)
+	}
+
+	cfg := config.NewConfig()
+	cfg.Passive = true
+	cfg.AddDomain("owasp.org")
+	cfg.AddHost("app.example.net")
+	cfg.PassiveDNSFiles = []string{path}
+	// The addresses excluded by netblock and by ASN are not stored
+	_, excluded, _ := net.ParseCIDR("192.168.1.0/24")
+	cfg.ExcludedCIDRs = []*net.IPNet{excluded}
+	cfg.ExcludedASNs = []int{64500}
+	cache := requests.NewASNCache()
+	cache.Update(&requests.ASNRequest{ASN: 64500, Prefix: "72.237.5.0/24"})
+
+	e := &Enumeration{
+		Config:    cfg,
+		Sys:       &systems.SimpleSystem{Cfg: cfg, ASNCache: cache},
+		ctx:       ctx,
+		graph:     g,
+		done:      make(chan struct{}),
+		pdnsTimes: make(map[string]*seenTimes),
+		stats:     newSourceStats(),
+	}
+	e.importPassiveDNS()
+	e.storePassiveDNSTimes(ctx)
+
+	names := stringset.New()
+	defer names.Close()
+	for _, name := range e.graph.EventFQDNs(ctx, cfg.UUID.String()) {
+		names.Insert(name)
+	}
+	// The name below the exact host is recorded as context
+	for _, name := range []string{"www.owasp.org", "owasp.org", "mail.owasp.org", "x.app.example.net"} {
+		if !names.Has(name) {
+			t.Errorf("the name %s was not imported", name)
+		}
+	}
+	if names.Has("www.example.com") {
+		t.Errorf("the out of scope name was imported")
+	}
+
+	pairs, err := g.NamesToAddrs(ctx, cfg.UUID.String(), "www.owasp.org")
+	if err != nil || len(pairs) != 2 {
+		t.Errorf("expected two addresses for www.owasp.org, got %v", pairs)
+	}
+
+	if pairs, err := g.NamesToAddrs(ctx, cfg.UUID.String(), "api.owasp.org"); err == nil && len(pairs) != 0 {
+		t.Errorf("the excluded addresses were stored for api.owasp.org: %v", pairs)
+	}
+	for _, addr := range []string{"not-an-address", "2001:db8::1", "72.237.4.115", "192.168.1.1", "72.237.5.1", "72.237.4.116"} {
+		if _, err := g.ReadNode(ctx, addr, netmap.TypeAddr); err == nil {
+			t.Errorf("the address %s was stored from an invalid or excluded record", addr)
+		}
+	}
+
+	first, last, err := ReadSeenTimes(ctx, g, "www.owasp.org")
+	if err != nil {
+		t.Fatalf("failed to read the observation times: %v", err)
+	}
+	if first.Unix() != 1400000000 || last.Unix() != 1600000000 {
+		t.Errorf("unexpected observation times: %v and %v", first, last)
+	}
+	if first, last, _ := ReadSeenTimes(ctx, g, "owasp.org"); !first.IsZero() || !last.IsZero() {
+		t.Errorf("observation times were stored for a record without timestamps")
+	}
+}
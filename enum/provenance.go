@@ -0,0 +1,57 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package enum
+
+import (
+	"context"
+
+	"github.com/OWASP/Amass/v3/datasrcs"
+	"github.com/caffix/netmap"
+)
+
+// ProvenancePredicate is the FQDN property storing where a data source found the name.
+const ProvenancePredicate = "provenance"
+
+// Stores the locations recorded by the data sources for the names that were found in the graph database.
+func (e *Enumeration) storeProvenance(ctx context.Context) {
+	for _, src := range e.srcs {
+		ps, ok := src.(datasrcs.ProvenanceSource)
+		if !ok {
+			continue
+		}
+
+		for name, locations := range ps.Provenance() {
+			node, err := e.graph.ReadNode(ctx, name, netmap.TypeFQDN)
+			if err != nil {
+				continue
+			}
+
+			for _, loc := range locations {
+				_ = e.graph.UpsertProperty(ctx, node, ProvenancePredicate, src.String()+": "+loc)
+			}
+		}
+	}
+}
+
+// ReadProvenance returns the locations where data sources found the FQDN, such as file paths and commits.
+func ReadProvenance(ctx context.Context, g *netmap.Graph, name string) ([]string, error) {
+	node, err := g.ReadNode(ctx, name, netmap.TypeFQDN)
+	if err != nil {
+		return nil, err
+	}
+
+	properties, err := g.ReadProperties(ctx, node, ProvenancePredicate)
+	if err != nil {
+		return nil, err
+	}
+
+	var locations []string
+	for _, p := range properties {
+		if v, ok := p.Value.Native().(string); ok {
+			locations = append(locations, v)
+		}
+	}
+	return locations, nil
+}
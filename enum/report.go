@@ -0,0 +1,232 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package enum
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/OWASP/Amass/v3/datasrcs"
+	"github.com/caffix/netmap"
+	"github.com/caffix/stringset"
+)
+
+// The event property used to store the data source reports in the graph database.
+const sourceReportPredicate = "source_report"
+
+// The predicates of the DNS records showing that a name was resolved.
+var resolvedRecordPredicates = []string{
+	"a_record", "aaaa_record", "cname_record", "ptr_record", "ns_record", "mx_record", "srv_record", "service",
+}
+
+// SourceReport describes the effectiveness of a data source during an enumeration.
+type SourceReport struct {
+	Source string `json:"source"`
+	// Names are not validated during passive enumerations
+	Passive bool `json:"passive,omitempty"`
+	// The number of distinct names provided by the data source
+	Submitted int `json:"submitted"`
+	// The number of submitted names that were stored with the enumeration, excluding the unresolved context names
+	Validated int `json:"validated"`
+	// The number of validated names that no other data source provided
+	Unique int `json:"unique"`
+	// The number of submitted names that were filtered due to DNS wildcards
+	Wildcards      int           `json:"wildcards"`
+	Requests       int64         `json:"requests"`
+	Errors         int64         `json:"errors"`
+	AverageLatency time.Duration `json:"average_latency"`
+	RateLimitWait  time.Duration `json:"rate_limit_wait"`
+}
+
+// sourceStats collects the names provided by each data source during the enumeration.
+type sourceStats struct {
+	sync.Mutex
+	names     map[string][]string
+	wildcards map[string]*stringset.Set
+}
+
+func newSourceStats() *sourceStats {
+	return &sourceStats{
+		names:     make(map[string][]string),
+		wildcards: make(map[string]*stringset.Set),
+	}
+}
+
+func (ss *sourceStats) submitted(name, source string) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" || source == "" {
+		return
+	}
+
+	ss.Lock()
+	defer ss.Unlock()
+
+	for _, src := range ss.names[name] {
+		if src == source {
+			return
+		}
+	}
+	ss.names[name] = append(ss.names[name], source)
+}
+
+func (ss *sourceStats) wildcard(name, source string) {
+	ss.Lock()
+	defer ss.Unlock()
+
+	set, found := ss.wildcards[source]
+	if !found {
+		set = stringset.New()
+		ss.wildcards[source] = set
+	}
+	set.Insert(strings.ToLower(name))
+}
+
+func (ss *sourceStats) close() {
+	ss.Lock()
+	defer ss.Unlock()
+
+	for _, set := range ss.wildcards {
+		set.Close()
+	}
+	ss.wildcards = make(map[string]*stringset.Set)
+}
+
+// SourceReports returns the effectiveness of each data source used by the completed enumeration.
+func (e *Enumeration) SourceReports() []*SourceReport {
+	e.reportLock.Lock()
+	defer e.reportLock.Unlock()
+
+	return e.reports
+}
+
+func (e *Enumeration) buildSourceReports(ctx context.Context) []*SourceReport {
+	validated := stringset.New()
+	defer validated.Close()
+
+	validated.InsertMany(e.graph.EventFQDNs(ctx, e.Config.UUID.String())...)
+
+	reports := make(map[string]*SourceReport)
+	for _, src := range e.srcs {
+		report := &SourceReport{
+			Source:  src.String(),
+			Passive: e.Config.Passive,
+		}
+
+		if rs, ok := src.(datasrcs.RequestStatsSource); ok {
+			stats := rs.RequestStats()
+
+			report.Requests = stats.Requests
+			report.Errors = stats.Errors
+			report.AverageLatency = stats.AverageLatency()
+			report.RateLimitWait = stats.RateLimitWait
+		}
+		reports[report.Source] = report
+	}
+
+	e.stats.Lock()
+	for name, srcs := range e.stats.names {
+		valid := validated.Has(name)
+		// The names in the parent zones are stored as context without being resolved
+		if valid && e.Config.IsScopeContext(name) {
+			valid = e.resolvedName(ctx, name)
+		}
+
+		for _, src := range srcs {
+			report, found := reports[src]
+			// Names imported from files are attributed to sources that are not data sources
+			if !found {
+				report = &SourceReport{
+					Source:  src,
+					Passive: e.Config.Passive,
+				}
+				reports[src] = report
+			}
+
+			report.Submitted++
+			if valid {
+				report.Validated++
+				if len(srcs) == 1 {
+					report.Unique++
+				}
+			}
+		}
+	}
+	for src, set := range e.stats.wildcards {
+		if report, found := reports[src]; found {
+			report.Wildcards = set.Len()
+		}
+	}
+	e.stats.Unlock()
+
+	var results []*SourceReport
+	for _, report := range reports {
+		results = append(results, report)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Source < results[j].Source
+	})
+	return results
+}
+
+// Returns true when DNS records for the name are stored in the graph database.
+func (e *Enumeration) resolvedName(ctx context.Context, name string) bool {
+	node, err := e.graph.ReadNode(ctx, name, netmap.TypeFQDN)
+	if err != nil {
+		return false
+	}
+
+	count, err := e.graph.CountOutEdges(ctx, node, resolvedRecordPredicates...)
+	return err == nil && count > 0
+}
+
+// Stores the data source reports as properties of the enumeration event in the graph database.
+func (e *Enumeration) storeSourceReports(ctx context.Context, reports []*SourceReport) error {
+	event, err := e.graph.UpsertEvent(ctx, e.Config.UUID.String())
+	if err != nil {
+		return err
+	}
+
+	for _, report := range reports {
+		data, err := json.Marshal(report)
+		if err != nil {
+			return err
+		}
+		if err := e.graph.UpsertProperty(ctx, event, sourceReportPredicate, string(data)); err != nil {
+			return fmt.Errorf("failed to store the %s report: %v", report.Source, err)
+		}
+	}
+	return nil
+}
+
+// ReadSourceReports returns the data source reports stored with the enumeration event in the graph database.
+func ReadSourceReports(ctx context.Context, g *netmap.Graph, uuid string) ([]*SourceReport, error) {
+	event, err := g.ReadNode(ctx, uuid, netmap.TypeEvent)
+	if err != nil {
+		return nil, err
+	}
+
+	properties, err := g.ReadProperties(ctx, event, sourceReportPredicate)
+	if err != nil {
+		return nil, err
+	}
+
+	var reports []*SourceReport
+	for _, p := range properties {
+		var report SourceReport
+
+		if v, ok := p.Value.Native().(string); ok && json.Unmarshal([]byte(v), &report) == nil {
+			reports = append(reports, &report)
+		}
+	}
+	sort.Slice(reports, func(i, j int) bool {
+		return reports[i].Source < reports[j].Source
+	})
+	return reports, nil
+}
@@ -0,0 +1,94 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package enum
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/OWASP/Amass/v3/config"
+	"github.com/caffix/netmap"
+)
+
+func TestSourceReports(t *testing.T) {
+	ctx := context.Background()
+	g := netmap.NewGraph(netmap.NewCayleyGraphMemory())
+	defer g.Close()
+
+	cfg := config.NewConfig()
+	cfg.AddDomain("dev.example.com")
+	uuid := cfg.UUID.String()
+
+	e := &Enumeration{
+		Config: cfg,
+		ctx:    ctx,
+		graph:  g,
+		stats:  newSourceStats(),
+	}
+	defer e.stats.close()
+
+	for _, s := range []struct {
+		name, source string
+	}{
+		{"www.dev.example.com", "Certs"},
+		{"www.dev.example.com", "Scraper"},
+		{"api.dev.example.com", "Certs"},
+		{"example.com", "Certs"},
+		{"bogus.dev.example.com", "Scraper"},
+		{"mail.example.com", "Scraper"},
+		// Duplicate submissions are counted once
+		{"www.dev.example.com", "Scraper"},
+	} {
+		e.stats.submitted(s.name, s.source)
+	}
+	for _, name := range []string{"x.dev.example.com", "y.dev.example.com", "x.dev.example.com"} {
+		e.stats.wildcard(name, "Scraper")
+	}
+
+	for _, name := range []string{"www.dev.example.com", "api.dev.example.com"} {
+		if err := g.UpsertA(ctx, name, "192.0.2.1", "DNS", uuid); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The name in the parent zone is recorded as context without being resolved
+	if _, err := g.UpsertFQDN(ctx, "example.com", "Certs", uuid); err != nil {
+		t.Fatal(err)
+	}
+	// The context name has been resolved as the target of an in scope name
+	if err := g.UpsertA(ctx, "mail.example.com", "192.0.2.2", "DNS", uuid); err != nil {
+		t.Fatal(err)
+	}
+
+	reports := e.buildSourceReports(ctx)
+	expected := []*SourceReport{
+		{Source: "Certs", Submitted: 3, Validated: 2, Unique: 1},
+		{Source: "Scraper", Submitted: 3, Validated: 2, Unique: 1, Wildcards: 2},
+	}
+	if !reflect.DeepEqual(reports, expected) {
+		for _, r := range reports {
+			t.Logf("%+v", r)
+		}
+		t.Fatal("buildSourceReports returned unexpected reports")
+	}
+
+	if err := e.storeSourceReports(ctx, reports); err != nil {
+		t.Fatalf("storeSourceReports failed: %v", err)
+	}
+	stored, err := ReadSourceReports(ctx, g, uuid)
+	if err != nil {
+		t.Fatalf("ReadSourceReports failed: %v", err)
+	}
+	if !reflect.DeepEqual(stored, expected) {
+		for _, r := range stored {
+			t.Logf("%+v", r)
+		}
+		t.Error("ReadSourceReports did not return the stored reports")
+	}
+
+	if _, err := ReadSourceReports(ctx, g, "missing"); err == nil {
+		t.Error("ReadSourceReports did not return an error for a missing event")
+	}
+}
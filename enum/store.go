@@ -345,7 +345,7 @@ func (dm *dataManager) addrRequest(ctx context.Context, req *requests.AddrReques
 	}
 
 	uuid := dm.enum.Config.UUID.String()
-	if req == nil || !req.InScope || uuid == "" {
+	if req == nil || !req.InScope || uuid == "" || dm.enum.Config.IsAddressExcluded(req.Address) {
 		return nil
 	}
 	if yes, prefix := amassnet.IsReservedAddress(req.Address); yes {
@@ -357,6 +357,10 @@ func (dm *dataManager) addrRequest(ctx context.Context, req *requests.AddrReques
 		return err
 	}
 	if r := dm.enum.Sys.Cache().AddrSearch(req.Address); r != nil {
+		if dm.enum.Config.IsASNExcluded(r.ASN) {
+			return nil
+		}
+
 		var err error
 		if e := dm.enum.graph.UpsertInfrastructure(ctx, r.ASN,
 			r.Description, req.Address, r.Prefix, r.Source, uuid); e != nil {
@@ -395,7 +399,7 @@ func (dm *dataManager) nextInfraInfo() {
 	req := e.(*requests.AddrRequest)
 	uuid := dm.enum.Config.UUID.String()
 	if r := dm.enum.Sys.Cache().AddrSearch(req.Address); r != nil {
-		_ = dm.enum.graph.UpsertInfrastructure(ctx, r.ASN, r.Description, req.Address, r.Prefix, r.Source, uuid)
+		dm.upsertInfrastructure(ctx, req.Address, r, uuid)
 		return
 	}
 
@@ -410,7 +414,7 @@ loop:
 
 		time.Sleep(2 * time.Second)
 		if r := dm.enum.Sys.Cache().AddrSearch(req.Address); r != nil {
-			_ = dm.enum.graph.UpsertInfrastructure(ctx, r.ASN, r.Description, req.Address, r.Prefix, r.Source, uuid)
+			dm.upsertInfrastructure(ctx, req.Address, r, uuid)
 			return
 		}
 	}
@@ -431,6 +435,13 @@ loop:
 	})
 }
 
+// Enters the infrastructure of the address into the graph, unless the autonomous system is out of scope.
+func (dm *dataManager) upsertInfrastructure(ctx context.Context, addr string, r *requests.ASNRequest, uuid string) {
+	if !dm.enum.Config.IsASNExcluded(r.ASN) {
+		_ = dm.enum.graph.UpsertInfrastructure(ctx, r.ASN, r.Description, addr, r.Prefix, r.Source, uuid)
+	}
+}
+
 func fakePrefix(addr string) string {
 	bits := 24
 	total := 32
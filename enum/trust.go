@@ -0,0 +1,160 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package enum
+
+import (
+	"context"
+
+	"github.com/OWASP/Amass/v3/requests"
+	"github.com/caffix/netmap"
+	"github.com/caffix/queue"
+	"github.com/caffix/stringset"
+)
+
+const (
+	// The number of names a data source must have submitted before a trust score is assigned
+	minTrustSamples = 25
+	// Names from data sources at or above this score bypass the DNS wildcard filtering
+	trustedScore = 0.95
+	// Scores used to select the priority of names in the enumeration input source
+	highTrustScore = 0.75
+	lowTrustScore  = 0.25
+	// The confidence used for data sources without a trust score
+	defaultConfidence = 0.5
+)
+
+// TrustScores provides the historical validation rates of data sources across enumerations.
+type TrustScores struct {
+	submitted map[string]int
+	validated map[string]int
+	tags      map[string]string
+}
+
+// LoadTrustScores calculates the data source trust scores from the reports stored with the
+// active enumeration events in the provided graph databases.
+func LoadTrustScores(ctx context.Context, graphs ...*netmap.Graph) *TrustScores {
+	ts := newTrustScores()
+
+	events := stringset.New()
+	defer events.Close()
+
+	for _, g := range graphs {
+		for _, uuid := range g.EventList(ctx) {
+			if events.Has(uuid) {
+				continue
+			}
+			events.Insert(uuid)
+
+			reports, err := ReadSourceReports(ctx, g, uuid)
+			if err != nil {
+				continue
+			}
+
+			for _, report := range reports {
+				if !report.Passive {
+					ts.add(report.Source, report.Submitted, report.Validated)
+				}
+			}
+		}
+	}
+	return ts
+}
+
+func newTrustScores() *TrustScores {
+	return &TrustScores{
+		submitted: make(map[string]int),
+		validated: make(map[string]int),
+		tags:      make(map[string]string),
+	}
+}
+
+func (ts *TrustScores) add(source string, submitted, validated int) {
+	if source == "" || submitted <= 0 {
+		return
+	}
+	if validated > submitted {
+		validated = submitted
+	}
+
+	ts.submitted[source] += submitted
+	ts.validated[source] += validated
+}
+
+// Score returns the fraction of names submitted by the data source that were validated
+// in previous enumerations. False is returned when the source lacks enough history.
+func (ts *TrustScores) Score(source string) (float64, bool) {
+	if ts == nil {
+		return 0, false
+	}
+
+	submitted := ts.submitted[source]
+	if submitted < minTrustSamples {
+		return 0, false
+	}
+	return float64(ts.validated[source]) / float64(submitted), true
+}
+
+// Trusted returns true if the names provided by the data source bypass the DNS wildcard filtering.
+// Data sources with a high historical validation rate are trusted, and the sources with a low rate
+// are not trusted even when the tag is. The tag decides for the sources without a trust score.
+func (ts *TrustScores) Trusted(source, tag string) bool {
+	if score, ok := ts.Score(source); ok {
+		if score >= trustedScore {
+			return true
+		}
+		if score < lowTrustScore {
+			return false
+		}
+	}
+	return requests.TrustedTag(tag)
+}
+
+// Priority returns the queue priority for names submitted by the data source.
+func (ts *TrustScores) Priority(source string) int {
+	score, ok := ts.Score(source)
+
+	switch {
+	case !ok:
+		return queue.PriorityNormal
+	case score >= highTrustScore:
+		return queue.PriorityHigh
+	case score < lowTrustScore:
+		return queue.PriorityLow
+	}
+	return queue.PriorityNormal
+}
+
+// Confidence returns the likelihood that a name provided by the data sources is legitimate,
+// based on the best trust score among the sources. Sources without a trust score are assigned
+// full confidence when their tag is trusted.
+func (ts *TrustScores) Confidence(sources []string) float64 {
+	var conf float64
+
+	for _, src := range sources {
+		score, ok := ts.Score(src)
+		if !ok {
+			score = defaultConfidence
+			if ts != nil && requests.TrustedTag(ts.tags[src]) {
+				score = 1
+			}
+		}
+		if score > conf {
+			conf = score
+		}
+	}
+	return conf
+}
+
+// Set the tags used for data sources without a trust score.
+func (ts *TrustScores) setTags(tags map[string]string) {
+	for src, tag := range tags {
+		ts.tags[src] = tag
+	}
+}
+
+// TrustScores returns the data source trust scores used by the enumeration.
+func (e *Enumeration) TrustScores() *TrustScores {
+	return e.trust
+}
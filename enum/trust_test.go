@@ -0,0 +1,93 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package enum
+
+import (
+	"context"
+	"testing"
+
+	"github.com/OWASP/Amass/v3/config"
+	"github.com/OWASP/Amass/v3/requests"
+	"github.com/caffix/netmap"
+	"github.com/caffix/queue"
+)
+
+func TestTrustScores(t *testing.T) {
+	ctx := context.Background()
+	g := netmap.NewGraph(netmap.NewCayleyGraphMemory())
+	defer g.Close()
+
+	events := []struct {
+		passive bool
+		reports []*SourceReport
+	}{
+		{false, []*SourceReport{
+			{Source: "Certs", Submitted: 100, Validated: 99},
+			{Source: "Scraper", Submitted: 50, Validated: 5},
+			{Source: "Newcomer", Submitted: 10, Validated: 10},
+		}},
+		{false, []*SourceReport{
+			{Source: "Certs", Submitted: 100, Validated: 97},
+			{Source: "Scraper", Submitted: 50, Validated: 5},
+		}},
+		// Passive enumerations do not validate names and must be ignored
+		{true, []*SourceReport{
+			{Source: "Scraper", Submitted: 1000, Validated: 1000},
+		}},
+	}
+	for _, event := range events {
+		cfg := config.NewConfig()
+		e := &Enumeration{Config: cfg, graph: g}
+
+		for _, r := range event.reports {
+			r.Passive = event.passive
+		}
+		if err := e.storeSourceReports(ctx, event.reports); err != nil {
+			t.Fatalf("failed to store the reports: %v", err)
+		}
+	}
+
+	ts := LoadTrustScores(ctx, g)
+	if score, ok := ts.Score("Certs"); !ok || score != 0.98 {
+		t.Errorf("expected a score of 0.98 for Certs, got %f", score)
+	}
+	if score, ok := ts.Score("Scraper"); !ok || score != 0.1 {
+		t.Errorf("expected a score of 0.1 for Scraper, got %f", score)
+	}
+	if _, ok := ts.Score("Newcomer"); ok {
+		t.Error("Newcomer was scored without enough history")
+	}
+
+	// Certs has a high validation rate, and the low rate of Scraper overrides the trusted tag
+	if !ts.Trusted("Certs", requests.API) || ts.Trusted("Scraper", requests.CERT) || ts.Trusted("Scraper", requests.API) {
+		t.Error("Trusted returned unexpected results for the scored sources")
+	}
+	// The tag decides for the sources without a trust score
+	if !ts.Trusted("Newcomer", requests.CERT) || ts.Trusted("Newcomer", requests.API) {
+		t.Error("Trusted returned unexpected results for the unscored sources")
+	}
+	var none *TrustScores
+	if !none.Trusted("Certs", requests.DNS) || none.Trusted("Certs", requests.API) {
+		t.Error("Trusted returned unexpected results without the trust scores")
+	}
+	if ts.Priority("Certs") != queue.PriorityHigh || ts.Priority("Scraper") != queue.PriorityLow ||
+		ts.Priority("Newcomer") != queue.PriorityNormal {
+		t.Error("Priority returned unexpected results")
+	}
+
+	ts.setTags(map[string]string{"DNS": requests.DNS, "Newcomer": requests.API})
+	if c := ts.Confidence([]string{"Scraper", "Certs"}); c != 0.98 {
+		t.Errorf("expected a confidence of 0.98, got %f", c)
+	}
+	if c := ts.Confidence([]string{"Scraper"}); c != 0.1 {
+		t.Errorf("expected a confidence of 0.1, got %f", c)
+	}
+	if c := ts.Confidence([]string{"Newcomer"}); c != defaultConfidence {
+		t.Errorf("expected the default confidence for Newcomer, got %f", c)
+	}
+	if c := ts.Confidence([]string{"DNS"}); c != 1 {
+		t.Errorf("expected full confidence for DNS, got %f", c)
+	}
+}
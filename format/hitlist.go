@@ -0,0 +1,36 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"strings"
+
+	amassnet "github.com/OWASP/Amass/v3/net"
+)
+
+// ReadIPv6Hitlist parses a hitlist of responsive IPv6 addresses, one per line, and calls the
+// provided function for each address. Blank lines, comments and IPv4 addresses are skipped.
+func ReadIPv6Hitlist(r io.Reader, fn func(net.IP)) error {
+	scanner := bufio.NewScanner(r)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		// Some hitlists provide additional columns after the address
+		if fields := strings.Fields(line); len(fields) > 1 {
+			line = fields[0]
+		}
+
+		if ip := net.ParseIP(line); ip != nil && amassnet.IsIPv6(ip) {
+			fn(ip)
+		}
+	}
+	return scanner.Err()
+}
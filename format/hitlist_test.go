@@ -0,0 +1,31 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"net"
+	"strings"
+	"testing"
+)
+
+func TestReadIPv6Hitlist(t *testing.T) {
+	input := `# responsive addresses
+2001:db8::1
+
+2001:DB8::211:22ff:fe33:4455 icmp
+192.168.1.1
+not an address
+`
+
+	var ips []string
+	if err := ReadIPv6Hitlist(strings.NewReader(input), func(ip net.IP) {
+		ips = append(ips, ip.String())
+	}); err != nil {
+		t.Fatalf("Failed to read the hitlist: %v", err)
+	}
+	if len(ips) != 2 || ips[0] != "2001:db8::1" || ips[1] != "2001:db8::211:22ff:fe33:4455" {
+		t.Errorf("Read the addresses %v from the hitlist", ips)
+	}
+}
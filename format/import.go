@@ -0,0 +1,461 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/OWASP/Amass/v3/requests"
+	"github.com/miekg/dns"
+)
+
+// The output formats of other reconnaissance tools supported by ReadImport.
+const (
+	ImportMassDNS   = "massdns"
+	ImportSubfinder = "subfinder"
+	ImportFindomain = "findomain"
+	ImportNmap      = "nmap"
+)
+
+// The source names attributed to the imported names.
+var importSources = map[string]string{
+	ImportMassDNS:   "MassDNS",
+	ImportSubfinder: "Subfinder",
+	ImportFindomain: "Findomain",
+	ImportNmap:      "Nmap",
+}
+
+var (
+	nmapSANRE = regexp.MustCompile(`DNS:([^,\s]+)`)
+	nmapCNRE  = regexp.MustCompile(`commonName=([^/,\s]+)`)
+)
+
+// ImportedName is a DNS name, and the records for the name, discovered by another reconnaissance tool.
+type ImportedName struct {
+	Name    string
+	Tag     string
+	Source  string
+	Records []requests.DNSAnswer
+}
+
+// Addresses returns the IP addresses provided by the A and AAAA records of the imported name.
+func (n *ImportedName) Addresses() []string {
+	var addrs []string
+
+	for _, rec := range n.Records {
+		if rec.Type == int(dns.TypeA) || rec.Type == int(dns.TypeAAAA) {
+			addrs = append(addrs, rec.Data)
+		}
+	}
+	return addrs
+}
+
+// ImportSource returns the data source name attributed to names imported from the format.
+func ImportSource(format string) string {
+	return importSources[format]
+}
+
+// ReadImport detects the format of the reconnaissance tool output and calls the provided function
+// for each name found. The supported formats are the massdns simple text and ndjson output, the
+// subfinder and findomain JSON output, and the nmap XML output, including the ssl-cert script
+// results. The detected format is returned.
+func ReadImport(r io.Reader, fn func(*ImportedName)) (string, error) {
+	br := bufio.NewReader(r)
+
+	first, err := FirstNonSpace(br)
+	if err == io.EOF {
+		return "", errors.New("the file is empty")
+	} else if err != nil {
+		return "", err
+	}
+
+	switch first {
+	case '<':
+		return ImportNmap, readNmap(br, fn)
+	case '{', '[':
+		return readImportJSON(br, fn)
+	}
+	return ImportMassDNS, readMassDNSSimple(br, fn)
+}
+
+// FirstNonSpace returns the first byte of the reader that is not whitespace or part of a UTF-8 byte
+// order mark, and leaves the byte unread.
+func FirstNonSpace(br *bufio.Reader) (byte, error) {
+	for {
+		b, err := br.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		switch b {
+		// Skip the whitespace and a UTF-8 byte order mark
+		case ' ', '\t', '\r', '\n', 0xef, 0xbb, 0xbf:
+		default:
+			return b, br.UnreadByte()
+		}
+	}
+}
+
+func newImportedName(name, format, tag string) *ImportedName {
+	name = strings.Trim(strings.ToLower(strings.TrimSpace(name)), ".")
+	name = strings.TrimPrefix(name, "*.")
+	if name == "" {
+		return nil
+	}
+
+	return &ImportedName{
+		Name:   name,
+		Tag:    tag,
+		Source: importSources[format],
+	}
+}
+
+// Appends the answer to the imported name when the record type and data are valid.
+func (n *ImportedName) addRecord(rrtype, data string) {
+	qtype, found := dns.StringToType[strings.ToUpper(strings.TrimSpace(rrtype))]
+	if !found {
+		return
+	}
+
+	data = strings.Trim(strings.TrimSpace(data), ".")
+	if data == "" {
+		return
+	}
+	if qtype == dns.TypeA || qtype == dns.TypeAAAA {
+		ip := net.ParseIP(data)
+		if ip == nil {
+			return
+		}
+		data = ip.String()
+	}
+
+	n.Records = append(n.Records, requests.DNSAnswer{
+		Name: n.Name,
+		Type: int(qtype),
+		Data: data,
+	})
+}
+
+// Reads the massdns simple text output, such as "www.example.com. A 93.184.216.34".
+// The TTL and class columns are optional. Consecutive lines for a name are combined.
+func readMassDNSSimple(r io.Reader, fn func(*ImportedName)) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var cur *ImportedName
+	var num int
+	for scanner.Scan() {
+		num++
+
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, ";") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) > 2 {
+			if _, err := strconv.Atoi(fields[1]); err == nil {
+				fields = append(fields[:1], fields[2:]...)
+			}
+		}
+		if len(fields) > 2 && strings.EqualFold(fields[1], "IN") {
+			fields = append(fields[:1], fields[2:]...)
+		}
+		if len(fields) < 3 {
+			return fmt.Errorf("the format of line %d was not recognized", num)
+		}
+		if _, found := dns.StringToType[strings.ToUpper(fields[1])]; !found {
+			return fmt.Errorf("the record type on line %d was not recognized", num)
+		}
+
+		name := strings.Trim(strings.ToLower(fields[0]), ".")
+		if cur == nil || cur.Name != name {
+			if cur != nil {
+				fn(cur)
+			}
+			if cur = newImportedName(name, ImportMassDNS, requests.EXTERNAL); cur == nil {
+				continue
+			}
+		}
+		cur.addRecord(fields[1], strings.Join(fields[2:], " "))
+	}
+	if cur != nil {
+		fn(cur)
+	}
+	return scanner.Err()
+}
+
+type massDNSRecord struct {
+	Name   string `json:"name"`
+	Type   string `json:"type"`
+	Status string `json:"status"`
+	Data   struct {
+		Answers []struct {
+			Name string `json:"name"`
+			Type string `json:"type"`
+			Data string `json:"data"`
+		} `json:"answers"`
+	} `json:"data"`
+}
+
+type toolRecord struct {
+	Host      string `json:"host"`
+	Subdomain string `json:"subdomain"`
+	Name      string `json:"name"`
+	IP        string `json:"ip"`
+	IPv4      string `json:"ipv4"`
+	IPv6      string `json:"ipv6"`
+}
+
+// Reads the massdns ndjson output, the subfinder JSON lines, or the findomain JSON output.
+func readImportJSON(r io.Reader, fn func(*ImportedName)) (string, error) {
+	var format string
+	dec := json.NewDecoder(r)
+
+	for {
+		var value json.RawMessage
+		if err := dec.Decode(&value); err == io.EOF {
+			break
+		} else if err != nil {
+			return format, err
+		}
+
+		values := []json.RawMessage{value}
+		if bytes.HasPrefix(bytes.TrimSpace(value), []byte("[")) {
+			values = nil
+			if err := json.Unmarshal(value, &values); err != nil {
+				return format, err
+			}
+		}
+
+		for _, v := range values {
+			f, err := readImportValue(v, format, fn)
+			if err != nil {
+				return format, err
+			}
+			format = f
+		}
+	}
+
+	if format == "" {
+		return "", errors.New("the JSON format was not recognized")
+	}
+	return format, nil
+}
+
+// Reads a single JSON value. The first object determines the format for the remainder of the file.
+func readImportValue(value json.RawMessage, format string, fn func(*ImportedName)) (string, error) {
+	var name string
+	// The findomain output can provide a list of names
+	if err := json.Unmarshal(value, &name); err == nil {
+		if format == "" {
+			format = ImportFindomain
+		}
+		if n := newImportedName(name, format, requests.EXTERNAL); n != nil {
+			fn(n)
+		}
+		return format, nil
+	}
+
+	var keys map[string]json.RawMessage
+	if err := json.Unmarshal(value, &keys); err != nil {
+		return format, err
+	}
+	if format == "" {
+		format = detectJSONFormat(keys)
+		if format == "" {
+			return "", errors.New("the JSON format was not recognized")
+		}
+	}
+
+	if format == ImportMassDNS {
+		var rec massDNSRecord
+		if err := json.Unmarshal(value, &rec); err != nil {
+			return format, err
+		}
+
+		readMassDNSRecord(&rec, fn)
+		return format, nil
+	}
+
+	var rec toolRecord
+	if err := json.Unmarshal(value, &rec); err != nil {
+		return format, err
+	}
+
+	for _, name := range []string{rec.Host, rec.Subdomain, rec.Name} {
+		if n := newImportedName(name, format, requests.EXTERNAL); n != nil {
+			for _, ip := range []string{rec.IP, rec.IPv4, rec.IPv6} {
+				if addr := net.ParseIP(strings.TrimSpace(ip)); addr != nil && addr.To4() != nil {
+					n.addRecord("A", ip)
+				} else if addr != nil {
+					n.addRecord("AAAA", ip)
+				}
+			}
+			fn(n)
+			break
+		}
+	}
+	return format, nil
+}
+
+func detectJSONFormat(keys map[string]json.RawMessage) string {
+	has := func(key string) bool {
+		_, found := keys[key]
+		return found
+	}
+
+	switch {
+	case has("host"):
+		return ImportSubfinder
+	case has("subdomain"):
+		return ImportFindomain
+	case has("name") && (has("status") || has("data") || has("resolver")):
+		return ImportMassDNS
+	case has("name"):
+		return ImportFindomain
+	}
+	return ""
+}
+
+// The answers are grouped by owner name, since the CNAME chain can include several names.
+func readMassDNSRecord(rec *massDNSRecord, fn func(*ImportedName)) {
+	if rec.Status != "" && !strings.EqualFold(rec.Status, "NOERROR") {
+		return
+	}
+
+	names := make(map[string]*ImportedName)
+	order := []string{}
+	get := func(name string) *ImportedName {
+		n := newImportedName(name, ImportMassDNS, requests.EXTERNAL)
+		if n == nil {
+			return nil
+		}
+		if cur, found := names[n.Name]; found {
+			return cur
+		}
+		names[n.Name] = n
+		order = append(order, n.Name)
+		return n
+	}
+
+	get(rec.Name)
+	for _, ans := range rec.Data.Answers {
+		if n := get(ans.Name); n != nil {
+			n.addRecord(ans.Type, ans.Data)
+		}
+	}
+	for _, name := range order {
+		fn(names[name])
+	}
+}
+
+type nmapHost struct {
+	Addresses []struct {
+		Addr string `xml:"addr,attr"`
+		Type string `xml:"addrtype,attr"`
+	} `xml:"address"`
+	Hostnames []struct {
+		Name string `xml:"name,attr"`
+	} `xml:"hostnames>hostname"`
+	Scripts []struct {
+		ID     string `xml:"id,attr"`
+		Output string `xml:"output,attr"`
+	} `xml:"ports>port>script"`
+}
+
+// Reads the hostnames and the ssl-cert script names from the nmap XML output.
+func readNmap(r io.Reader, fn func(*ImportedName)) error {
+	dec := xml.NewDecoder(r)
+	dec.Strict = false
+
+	for {
+		t, err := dec.Token()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		start, ok := t.(xml.StartElement)
+		if !ok || start.Name.Local != "host" {
+			continue
+		}
+
+		var host nmapHost
+		if err := dec.DecodeElement(&host, &start); err != nil {
+			return err
+		}
+		readNmapHost(&host, fn)
+	}
+}
+
+func readNmapHost(host *nmapHost, fn func(*ImportedName)) {
+	seen := make(map[string]struct{})
+
+	for _, hn := range host.Hostnames {
+		n := newImportedName(hn.Name, ImportNmap, requests.EXTERNAL)
+		if n == nil {
+			continue
+		}
+		if _, found := seen[n.Name]; found {
+			continue
+		}
+		seen[n.Name] = struct{}{}
+
+		for _, addr := range host.Addresses {
+			switch addr.Type {
+			case "ipv4":
+				n.addRecord("A", addr.Addr)
+			case "ipv6":
+				n.addRecord("AAAA", addr.Addr)
+			}
+		}
+		fn(n)
+	}
+
+	for _, script := range host.Scripts {
+		if script.ID != "ssl-cert" {
+			continue
+		}
+
+		var names []string
+		for _, line := range strings.Split(script.Output, "\n") {
+			line = strings.TrimSpace(line)
+
+			switch {
+			case strings.HasPrefix(line, "Subject Alternative Name:"):
+				for _, m := range nmapSANRE.FindAllStringSubmatch(line, -1) {
+					names = append(names, m[1])
+				}
+			case strings.HasPrefix(line, "Subject:"):
+				if m := nmapCNRE.FindStringSubmatch(line); m != nil {
+					names = append(names, m[1])
+				}
+			}
+		}
+
+		for _, name := range names {
+			n := newImportedName(name, ImportNmap, requests.CERT)
+			if n == nil {
+				continue
+			}
+			if _, found := seen[n.Name]; found {
+				continue
+			}
+			seen[n.Name] = struct{}{}
+			fn(n)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/OWASP/Amass/v3/requests"
+)
+
+func TestReadImport(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		format  string
+		source  string
+		names   []string
+		records int
+	}{
+		{
+			name: "massdns simple",
+			input: `www.owasp.org. A 72.237.4.113
+www.owasp.org. A 72.237.4.114
+api.owasp.org. 300 IN CNAME www.owasp.org.
+`,
+			format:  ImportMassDNS,
+			source:  "MassDNS",
+			names:   []string{"api.owasp.org", "www.owasp.org"},
+			records: 3,
+		},
+		{
+			name: "massdns ndjson",
+			input: `{"name":"api.owasp.org.","type":"A","class":"IN","status":"NOERROR","data":{"answers":[{"ttl":300,"type":"CNAME","class":"IN","name":"api.owasp.org.","data":"www.owasp.org."},{"ttl":300,"type":"A","class":"IN","name":"www.owasp.org.","data":"72.237.4.113"}]}}
+{"name":"missing.owasp.org.","type":"A","class":"IN","status":"NXDOMAIN","data":{}}
+`,
+			format:  ImportMassDNS,
+			source:  "MassDNS",
+			names:   []string{"api.owasp.org", "www.owasp.org"},
+			records: 2,
+		},
+		{
+			name: "subfinder",
+			input: `{"host":"www.owasp.org","input":"owasp.org","source":"crtsh"}
+{"host":"api.owasp.org","input":"owasp.org","source":"alienvault","ip":"72.237.4.113"}
+`,
+			format:  ImportSubfinder,
+			source:  "Subfinder",
+			names:   []string{"api.owasp.org", "www.owasp.org"},
+			records: 1,
+		},
+		{
+			name:    "findomain",
+			input:   `[{"subdomain":"www.owasp.org","ipv4":"72.237.4.113"},"*.api.owasp.org"]`,
+			format:  ImportFindomain,
+			source:  "Findomain",
+			names:   []string{"api.owasp.org", "www.owasp.org"},
+			records: 1,
+		},
+		{
+			name: "nmap",
+			input: `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE nmaprun>
+<nmaprun scanner="nmap">
+<host><status state="up"/>
+<address addr="72.237.4.113" addrtype="ipv4"/>
+<hostnames><hostname name="www.owasp.org" type="user"/><hostname name="www.owasp.org" type="PTR"/></hostnames>
+<ports><port protocol="tcp" portid="443"><state state="open"/>
+<script id="ssl-cert" output="Subject: commonName=owasp.org&#xa;Subject Alternative Name: DNS:owasp.org, DNS:*.dev.owasp.org, DNS:www.owasp.org&#xa;Issuer: commonName=R3"/>
+</port></ports>
+</host>
+</nmaprun>
+`,
+			format:  ImportNmap,
+			source:  "Nmap",
+			names:   []string{"dev.owasp.org", "owasp.org", "www.owasp.org"},
+			records: 1,
+		},
+	}
+
+	for _, test := range tests {
+		var names []string
+		var records int
+
+		format, err := ReadImport(strings.NewReader(test.input), func(n *ImportedName) {
+			if n.Source != test.source {
+				t.Errorf("%s: the name %s was attributed to %s", test.name, n.Name, n.Source)
+			}
+			names = append(names, n.Name)
+			records += len(n.Records)
+		})
+		if err != nil {
+			t.Errorf("%s: failed to read the input: %v", test.name, err)
+			continue
+		}
+		if format != test.format {
+			t.Errorf("%s: detected the %s format", test.name, format)
+		}
+
+		sort.Strings(names)
+		if strings.Join(names, ",") != strings.Join(test.names, ",") {
+			t.Errorf("%s: expected the names %v, got %v", test.name, test.names, names)
+		}
+		if records != test.records {
+			t.Errorf("%s: expected %d records, got %d", test.name, test.records, records)
+		}
+	}
+}
+
+func TestReadImportNmapTags(t *testing.T) {
+	input := `<nmaprun><host><address addr="2001:db8::1" addrtype="ipv6"/>
+<hostnames><hostname name="www.owasp.org" type="user"/></hostnames>
+<ports><port><script id="ssl-cert" output="Subject Alternative Name: DNS:api.owasp.org"/></port></ports>
+</host></nmaprun>`
+
+	tags := make(map[string]*ImportedName)
+	if _, err := ReadImport(strings.NewReader(input), func(n *ImportedName) {
+		tags[n.Name] = n
+	}); err != nil {
+		t.Fatalf("failed to read the input: %v", err)
+	}
+
+	if n, found := tags["www.owasp.org"]; !found || n.Tag != requests.EXTERNAL {
+		t.Errorf("the hostname was not imported with the external tag")
+	} else if addrs := n.Addresses(); len(addrs) != 1 || addrs[0] != "2001:db8::1" {
+		t.Errorf("the host address was not imported: %v", addrs)
+	}
+	if n, found := tags["api.owasp.org"]; !found || n.Tag != requests.CERT {
+		t.Errorf("the certificate name was not imported with the cert tag")
+	}
+}
+
+func TestReadImportErrors(t *testing.T) {
+	for _, input := range []string{
+		"",
+		"www.owasp.org\n",
+		"this is plain text\n",
+		`{"unknown":"field"}`,
+	} {
+		if _, err := ReadImport(strings.NewReader(input), func(n *ImportedName) {}); err == nil {
+			t.Errorf("the input %q did not return an error", input)
+		}
+	}
+}
@@ -5,8 +5,10 @@
 package format
 
 import (
+	"bytes"
 	"fmt"
 	"math"
+	"math/big"
 	"net"
 	"strconv"
 	"strings"
@@ -14,6 +16,9 @@ import (
 	amassnet "github.com/OWASP/Amass/v3/net"
 )
 
+// MaxIPv6RangeHosts is the largest IPv6 address range that can be expanded into individual addresses.
+const MaxIPv6RangeHosts = 65536
+
 // ParseStrings implements the flag.Value interface.
 type ParseStrings []string
 
@@ -102,6 +107,10 @@ func (p *ParseIPs) Set(s string) error {
 
 	for _, v := range strings.Split(s, ",") {
 		if start, end, ok := parseRange(v); ok {
+			if amassnet.IsIPv6(start) && rangeSize(start, end) > MaxIPv6RangeHosts {
+				return fmt.Errorf("%s contains more than %d addresses, provide it as a CIDR range instead", v, MaxIPv6RangeHosts)
+			}
+
 			ips := amassnet.RangeHosts(start, end)
 			if len(ips) == 0 {
 				return fmt.Errorf("%s is not a valid IP address or range", v)
@@ -130,7 +139,16 @@ func parseRange(s string) (start net.IP, end net.IP, ok bool) {
 		return
 	}
 	end = net.ParseIP(twoIPs[1])
-	if end == nil {
+	if end == nil && amassnet.IsIPv6(start) {
+		// The short-hand IPv6 range replaces the last group of hexadecimal digits
+		num, err := strconv.ParseUint(twoIPs[1], 16, 16)
+		if err != nil {
+			return
+		}
+		end = make(net.IP, len(start))
+		copy(end, start)
+		end[len(end)-2], end[len(end)-1] = byte(num>>8), byte(num)
+	} else if end == nil {
 		num, err := strconv.Atoi(twoIPs[1])
 		if err != nil || math.MaxUint8 < num {
 			return
@@ -143,6 +161,20 @@ func parseRange(s string) (start net.IP, end net.IP, ok bool) {
 	return
 }
 
+// rangeSize returns the number of addresses in the range, or zero when the range is empty.
+func rangeSize(start, end net.IP) int64 {
+	s, e := start.To16(), end.To16()
+	if s == nil || e == nil || bytes.Compare(e, s) < 0 {
+		return 0
+	}
+
+	size := new(big.Int).Sub(new(big.Int).SetBytes(e), new(big.Int).SetBytes(s))
+	if size.Add(size, big.NewInt(1)); !size.IsInt64() {
+		return math.MaxInt64
+	}
+	return size.Int64()
+}
+
 func (p *ParseCIDRs) String() string {
 	if p == nil {
 		return ""
@@ -166,6 +198,16 @@ func (p *ParseCIDRs) Set(s string) error {
 
 	cidrs := strings.Split(s, ",")
 	for _, cidr := range cidrs {
+		if start, end, ok := parseRange(cidr); ok {
+			ipnets := amassnet.RangeCIDRs(start, end)
+			if len(ipnets) == 0 {
+				return fmt.Errorf("failed to parse %s as a CIDR range", cidr)
+			}
+
+			*p = append(*p, ipnets...)
+			continue
+		}
+
 		_, ipnet, err := net.ParseCIDR(cidr)
 		if err != nil {
 			return fmt.Errorf("failed to parse %s as a CIDR", cidr)
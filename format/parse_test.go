@@ -178,6 +178,22 @@ func TestParseIPs(t *testing.T) {
 			input:    "127.0.0.1-3,255.0.0.0",
 			ok:       true,
 			expected: "127.0.0.1,127.0.0.2,127.0.0.3,255.0.0.0",
+		}, {
+			label:    "Valid_Compact_IPv6_Range",
+			input:    "2001:db8::fe-101",
+			ok:       true,
+			expected: "2001:db8::fe,2001:db8::ff,2001:db8::100,2001:db8::101",
+		}, {
+			label:    "Valid_IPv6_Range",
+			input:    "2001:db8::1-2001:db8::3",
+			ok:       true,
+			expected: "2001:db8::1,2001:db8::2,2001:db8::3",
+		}, {
+			label: "IPv6_Range_Too_Large",
+			input: "2001:db8::1-2001:db8::1:1",
+		}, {
+			label: "Invalid_Compact_IPv6_Range",
+			input: "2001:db8::1-fffff",
 		}, {
 			label: "Extraneous_Comma",
 			input: "127.0.0.1-3,255.0.0.0,",
@@ -238,6 +254,19 @@ func TestParseCIDRs(t *testing.T) {
 			input:    "192.0.2.1/24,193.0.2.1/16",
 			ok:       true,
 			expected: "192.0.2.0/24,193.0.0.0/16",
+		}, {
+			label:    "Valid_IPv6_CIDR",
+			input:    "2001:db8::1/48",
+			ok:       true,
+			expected: "2001:db8::/48",
+		}, {
+			label:    "Valid_Ranges",
+			input:    "192.0.2.0-192.0.2.255,192.0.3.1-2,2001:db8::-2001:db8::1:ffff",
+			ok:       true,
+			expected: "192.0.2.0/24,192.0.3.1/32,192.0.3.2/32,2001:db8::/111",
+		}, {
+			label: "Empty_Range",
+			input: "192.0.2.255-192.0.2.0",
 		}, {
 			label: "Invalid_CIDRs",
 			input: "192.0.2.1/24,193.0.2.1/66",
@@ -0,0 +1,98 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"strings"
+	"time"
+)
+
+// PassiveDNSRecord is a record in the Passive DNS Common Output Format.
+type PassiveDNSRecord struct {
+	RRName    string   `json:"rrname"`
+	RRType    string   `json:"rrtype"`
+	RData     []string `json:"-"`
+	TimeFirst int64    `json:"time_first"`
+	TimeLast  int64    `json:"time_last"`
+	Count     int64    `json:"count,omitempty"`
+	Bailiwick string   `json:"bailiwick,omitempty"`
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface, since the rdata field
+// can be provided as a string or an array of strings.
+func (p *PassiveDNSRecord) UnmarshalJSON(data []byte) error {
+	type record PassiveDNSRecord
+	aux := &struct {
+		*record
+		RData         json.RawMessage `json:"rdata"`
+		ZoneTimeFirst int64           `json:"zone_time_first"`
+		ZoneTimeLast  int64           `json:"zone_time_last"`
+	}{record: (*record)(p)}
+
+	if err := json.Unmarshal(data, aux); err != nil {
+		return err
+	}
+	// Records observed in zone files only provide the zone timestamps
+	if p.TimeFirst == 0 {
+		p.TimeFirst = aux.ZoneTimeFirst
+	}
+	if p.TimeLast == 0 {
+		p.TimeLast = aux.ZoneTimeLast
+	}
+
+	p.RData = nil
+	if len(aux.RData) == 0 {
+		return nil
+	}
+
+	var single string
+	if err := json.Unmarshal(aux.RData, &single); err == nil {
+		p.RData = []string{single}
+		return nil
+	}
+	return json.Unmarshal(aux.RData, &p.RData)
+}
+
+// FirstSeen returns the time the record was first observed.
+func (p *PassiveDNSRecord) FirstSeen() time.Time {
+	return time.Unix(p.TimeFirst, 0).UTC()
+}
+
+// LastSeen returns the time the record was last observed.
+func (p *PassiveDNSRecord) LastSeen() time.Time {
+	return time.Unix(p.TimeLast, 0).UTC()
+}
+
+// ReadPassiveDNS parses the JSON lines of Passive DNS Common Output Format records and
+// calls the provided function for each record with a name. Blank lines are skipped, and
+// the number of malformed lines that were skipped is returned.
+func ReadPassiveDNS(r io.Reader, fn func(*PassiveDNSRecord)) (int, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 10*1024*1024)
+
+	var malformed int
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var rec PassiveDNSRecord
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			malformed++
+			continue
+		}
+
+		rec.RRName = strings.Trim(strings.ToLower(strings.TrimSpace(rec.RRName)), ".")
+		rec.RRType = strings.ToUpper(strings.TrimSpace(rec.RRType))
+		if rec.RRName != "" {
+			fn(&rec)
+		}
+	}
+	return malformed, scanner.Err()
+}
@@ -0,0 +1,54 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReadPassiveDNS(t *testing.T) {
+	input := `{"rrname":"WWW.OWASP.ORG.","rrtype":"a","rdata":"72.237.4.113","time_first":1500000000,"time_last":1600000000,"count":5}
+
+{"rrname":"owasp.org","rrtype":"NS","rdata":["ns1.owasp.org.","ns2.owasp.org."],"zone_time_first":1400000000,"zone_time_last":1450000000}
+{"rrname":"","rrtype":"A","rdata":"1.1.1.1"}
+{"rrname":"truncated.owasp.org","rrtype":"A","rda
+{"rrname":"mail.owasp.org","rrtype":"A","rdata":"72.237.4.114"}
+`
+
+	var records []*PassiveDNSRecord
+	malformed, err := ReadPassiveDNS(strings.NewReader(input), func(rec *PassiveDNSRecord) {
+		records = append(records, rec)
+	})
+	if err != nil {
+		t.Fatalf("Failed to read the records: %v", err)
+	}
+	if malformed != 1 {
+		t.Errorf("Counted %d malformed records instead of 1", malformed)
+	}
+	if len(records) != 3 || records[2].RRName != "mail.owasp.org" {
+		t.Fatalf("Failed to read the records after the malformed record: %d records", len(records))
+	}
+
+	rec := records[0]
+	if rec.RRName != "www.owasp.org" || rec.RRType != "A" || len(rec.RData) != 1 || rec.RData[0] != "72.237.4.113" {
+		t.Errorf("Failed to parse the record: %+v", rec)
+	}
+	if rec.Count != 5 || rec.FirstSeen().Unix() != 1500000000 || rec.LastSeen().Unix() != 1600000000 {
+		t.Errorf("Failed to parse the record details: %+v", rec)
+	}
+
+	rec = records[1]
+	if len(rec.RData) != 2 || rec.RData[1] != "ns2.owasp.org." {
+		t.Errorf("Failed to parse the rdata array: %v", rec.RData)
+	}
+	if rec.TimeFirst != 1400000000 || rec.TimeLast != 1450000000 {
+		t.Errorf("Failed to use the zone file timestamps: %+v", rec)
+	}
+
+	if malformed, err := ReadPassiveDNS(strings.NewReader("{\"rrname\":"), func(rec *PassiveDNSRecord) {}); err != nil || malformed != 1 {
+		t.Errorf("Failed to detect the malformed record")
+	}
+}
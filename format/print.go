@@ -39,6 +39,9 @@ const (
 
 	// Description is the slogan for the Amass Project.
 	Description = "In-depth Attack Surface Mapping and Asset Discovery"
+
+	// The layout of the Passive DNS observation times in the output
+	seenTimeFormat = "2006-01-02"
 )
 
 var (
@@ -126,8 +129,8 @@ func FprintEnumerationSummary(out io.Writer, total int, tags map[string]int, asn
 		datastr := data.Name
 
 		if demo && asn > 0 {
-			asnstr = censorString(asnstr, 0, len(asnstr))
-			datastr = censorString(datastr, 0, len(datastr))
+			asnstr = CensorString(asnstr, 0, len(asnstr))
+			datastr = CensorString(datastr, 0, len(datastr))
 		}
 		fmt.Fprintf(out, "%s%s %s %s\n", blue("ASN: "), yellow(asnstr), green("-"), green(datastr))
 
@@ -170,19 +173,22 @@ func FprintBanner(out io.Writer) {
 	_, _ = y.Fprintf(out, "%s\n\n\n", Description)
 }
 
-func censorDomain(input string) string {
-	return censorString(input, strings.Index(input, "."), len(input))
+// CensorDomain replaces the characters of the first label in the DNS name.
+func CensorDomain(input string) string {
+	return CensorString(input, strings.Index(input, "."), len(input))
 }
 
-func censorIP(input string) string {
-	return censorString(input, 0, strings.LastIndex(input, "."))
+// CensorIP replaces the characters of the IP address before the last dot.
+func CensorIP(input string) string {
+	return CensorString(input, 0, strings.LastIndex(input, "."))
 }
 
 func censorNetBlock(input string) string {
-	return censorString(input, 0, strings.Index(input, "/"))
+	return CensorString(input, 0, strings.Index(input, "/"))
 }
 
-func censorString(input string, start, end int) string {
+// CensorString replaces the characters of the input between start and end, except for the separators.
+func CensorString(input string, start, end int) string {
 	runes := []rune(input)
 	for i := start; i < end; i++ {
 		if runes[i] == '.' ||
@@ -207,7 +213,7 @@ func OutputLineParts(out *requests.Output, src, addrs, demo bool) (source, name,
 				ips += ","
 			}
 			if demo {
-				ips += censorIP(a.Address.String())
+				ips += CensorIP(a.Address.String())
 			} else {
 				ips += a.Address.String()
 			}
@@ -218,11 +224,19 @@ func OutputLineParts(out *requests.Output, src, addrs, demo bool) (source, name,
 	}
 	name = out.Name
 	if demo {
-		name = censorDomain(name)
+		name = CensorDomain(name)
 	}
 	return
 }
 
+// OutputSeenTimes returns the Passive DNS observation times of the requests.Output, or an empty string.
+func OutputSeenTimes(out *requests.Output) string {
+	if out.FirstSeen == nil || out.LastSeen == nil {
+		return ""
+	}
+	return fmt.Sprintf("(seen %s -> %s)", out.FirstSeen.Format(seenTimeFormat), out.LastSeen.Format(seenTimeFormat))
+}
+
 // DesiredAddrTypes removes undesired address types from the AddressInfo slice.
 func DesiredAddrTypes(addrs []requests.AddressInfo, ipv4, ipv6 bool) []requests.AddressInfo {
 	if !ipv4 && !ipv6 {
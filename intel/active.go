@@ -11,6 +11,7 @@ import (
 
 	"github.com/OWASP/Amass/v3/net/http"
 	"github.com/OWASP/Amass/v3/requests"
+	"github.com/OWASP/Amass/v3/systems"
 	"github.com/caffix/pipeline"
 	"github.com/caffix/queue"
 	"golang.org/x/net/publicsuffix"
@@ -113,27 +114,32 @@ func (a *activeTask) certEnumeration(ctx context.Context, req *requests.AddrRequ
 	}
 
 	ip := net.ParseIP(req.Address)
-	if ip == nil {
+	if ip == nil || systems.IsAddressExcluded(a.c.Sys, req.Address) {
 		return
 	}
 
 	c := a.c
 	addrinfo := requests.AddressInfo{Address: ip}
-	for _, name := range http.PullCertificateNames(ctx, req.Address, c.Config.Ports) {
-		if n := strings.TrimSpace(name); n != "" {
-			domain, err := publicsuffix.EffectiveTLDPlusOne(n)
-			if err != nil {
-				continue
-			}
-
-			if domain != "" {
-				go pipeline.SendData(ctx, "filter", &requests.Output{
-					Name:      domain,
-					Domain:    domain,
-					Addresses: []requests.AddressInfo{addrinfo},
-					Tag:       requests.CERT,
-					Sources:   []string{"Active Cert"},
-				}, tp)
+	for _, cert := range http.PullCertificates(ctx, req.Address, c.Config.Ports) {
+		// Keep the subject and issuer details for clustering the addresses and domains
+		c.clusters.add(ip.String(), cert)
+
+		for _, name := range cert.Names {
+			if n := strings.TrimSpace(name); n != "" {
+				domain, err := publicsuffix.EffectiveTLDPlusOne(n)
+				if err != nil {
+					continue
+				}
+
+				if domain != "" {
+					go pipeline.SendData(ctx, "filter", &requests.Output{
+						Name:      domain,
+						Domain:    domain,
+						Addresses: []requests.AddressInfo{addrinfo},
+						Tag:       requests.CERT,
+						Sources:   []string{"Active Cert"},
+					}, tp)
+				}
 			}
 		}
 	}
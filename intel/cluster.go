@@ -0,0 +1,207 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package intel
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/OWASP/Amass/v3/net/http"
+	"golang.org/x/net/publicsuffix"
+)
+
+// Certificates covering more registered domains than this are considered to belong to shared
+// hosting, such as a CDN, and do not connect the clusters of the domains
+const maxLinkingCertDomains int = 10
+
+// CertCluster is a group of addresses and domains connected by the organizations in the
+// certificate subjects and by the certificates served from more than one address.
+type CertCluster struct {
+	Organizations []string `json:"organizations"`
+	Issuers       []string `json:"issuers"`
+	Certificates  int      `json:"certificates"`
+	Addresses     []string `json:"addresses"`
+	Domains       []string `json:"domains"`
+}
+
+// certClusters collects the certificates obtained from addresses during the active methods.
+type certClusters struct {
+	sync.Mutex
+	certs map[string]*http.CertificateInfo
+	addrs map[string]map[string]struct{}
+}
+
+func newCertClusters() *certClusters {
+	return &certClusters{
+		certs: make(map[string]*http.CertificateInfo),
+		addrs: make(map[string]map[string]struct{}),
+	}
+}
+
+func (cc *certClusters) add(addr string, cert *http.CertificateInfo) {
+	if cert == nil || cert.Fingerprint == "" {
+		return
+	}
+
+	cc.Lock()
+	defer cc.Unlock()
+
+	if _, found := cc.certs[cert.Fingerprint]; !found {
+		cc.certs[cert.Fingerprint] = cert
+		cc.addrs[cert.Fingerprint] = make(map[string]struct{})
+	}
+	cc.addrs[cert.Fingerprint][addr] = struct{}{}
+}
+
+// clusters groups the certificates that share an address, a subject organization or, when the
+// certificates are not shared hosting, a registered domain name.
+func (cc *certClusters) clusters() []*CertCluster {
+	cc.Lock()
+	defer cc.Unlock()
+
+	parent := make(map[string]string)
+	var find func(key string) string
+	find = func(key string) string {
+		p, found := parent[key]
+		if !found || p == key {
+			parent[key] = key
+			return key
+		}
+
+		root := find(p)
+		parent[key] = root
+		return root
+	}
+	union := func(a, b string) {
+		if ra, rb := find(a), find(b); ra != rb {
+			parent[rb] = ra
+		}
+	}
+
+	domains := make(map[string][]string)
+	for fp, cert := range cc.certs {
+		key := "cert:" + fp
+
+		find(key)
+		for addr := range cc.addrs[fp] {
+			union(key, "addr:"+addr)
+		}
+		for _, org := range cert.Organization {
+			union(key, "org:"+strings.ToLower(org))
+		}
+
+		domains[fp] = certDomains(cert)
+		if len(domains[fp]) <= maxLinkingCertDomains {
+			for _, d := range domains[fp] {
+				union(key, "domain:"+d)
+			}
+		}
+	}
+
+	type members struct {
+		orgs, issuers, addrs, domains map[string]string
+		certs                         int
+	}
+	insert := func(m map[string]string, values ...string) {
+		for _, v := range values {
+			// The first spelling of the value is kept
+			if _, found := m[strings.ToLower(v)]; !found {
+				m[strings.ToLower(v)] = v
+			}
+		}
+	}
+
+	// The certificates are visited in a stable order, so the same spellings are kept
+	fps := make([]string, 0, len(cc.certs))
+	for fp := range cc.certs {
+		fps = append(fps, fp)
+	}
+	sort.Strings(fps)
+
+	groups := make(map[string]*members)
+	for _, fp := range fps {
+		cert := cc.certs[fp]
+		root := find("cert:" + fp)
+
+		m, found := groups[root]
+		if !found {
+			m = &members{
+				orgs:    make(map[string]string),
+				issuers: make(map[string]string),
+				addrs:   make(map[string]string),
+				domains: make(map[string]string),
+			}
+			groups[root] = m
+		}
+
+		m.certs++
+		insert(m.orgs, cert.Organization...)
+		if cert.Issuer != "" {
+			insert(m.issuers, cert.Issuer)
+		}
+		for addr := range cc.addrs[fp] {
+			insert(m.addrs, addr)
+		}
+		insert(m.domains, domains[fp]...)
+	}
+
+	var results []*CertCluster
+	for _, m := range groups {
+		results = append(results, &CertCluster{
+			Organizations: sortedValues(m.orgs),
+			Issuers:       sortedValues(m.issuers),
+			Certificates:  m.certs,
+			Addresses:     sortedValues(m.addrs),
+			Domains:       sortedValues(m.domains),
+		})
+	}
+	// The largest clusters with a subject organization are the most informative
+	sort.Slice(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+
+		if (len(a.Organizations) > 0) != (len(b.Organizations) > 0) {
+			return len(a.Organizations) > 0
+		}
+		if len(a.Addresses) != len(b.Addresses) {
+			return len(a.Addresses) > len(b.Addresses)
+		}
+		return strings.Join(a.Domains, ",") < strings.Join(b.Domains, ",")
+	})
+	return results
+}
+
+func certDomains(cert *http.CertificateInfo) []string {
+	var domains []string
+	seen := make(map[string]struct{})
+
+	for _, name := range cert.Names {
+		d, err := publicsuffix.EffectiveTLDPlusOne(strings.ToLower(strings.TrimSpace(name)))
+		if err != nil || d == "" {
+			continue
+		}
+		if _, found := seen[d]; !found {
+			seen[d] = struct{}{}
+			domains = append(domains, d)
+		}
+	}
+	return domains
+}
+
+func sortedValues(m map[string]string) []string {
+	var values []string
+
+	for _, v := range m {
+		values = append(values, v)
+	}
+	sort.Strings(values)
+	return values
+}
+
+// CertClusters returns the addresses and domains grouped by the certificates obtained during the
+// active methods, which helps to separate the hosting of the target from shared hosting.
+func (c *Collection) CertClusters() []*CertCluster {
+	return c.clusters.clusters()
+}
@@ -0,0 +1,54 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package intel
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/OWASP/Amass/v3/net/http"
+)
+
+func TestCertClusters(t *testing.T) {
+	var shared []string
+	for i := 0; i < maxLinkingCertDomains+1; i++ {
+		shared = append(shared, fmt.Sprintf("www.customer%d.com", i))
+	}
+	shared = append(shared, "www.example.com")
+
+	cc := newCertClusters()
+	// The organization connects the certificates on different addresses
+	cc.add("192.0.2.1", &http.CertificateInfo{Fingerprint: "a", Organization: []string{"Example, Inc."},
+		Issuer: "DigiCert", Names: []string{"www.example.com"}})
+	cc.add("192.0.2.2", &http.CertificateInfo{Fingerprint: "b", Organization: []string{"EXAMPLE, INC."},
+		Issuer: "DigiCert", Names: []string{"mail.example.net"}})
+	// The certificate without an organization is connected by the domain name
+	cc.add("192.0.2.3", &http.CertificateInfo{Fingerprint: "c", Issuer: "R3", Names: []string{"api.example.net"}})
+	// The shared hosting certificate does not join the cluster of the domain names
+	cc.add("203.0.113.1", &http.CertificateInfo{Fingerprint: "d", Issuer: "R3", Names: shared})
+	cc.add("203.0.113.2", &http.CertificateInfo{Fingerprint: "d", Issuer: "R3", Names: shared})
+
+	clusters := cc.clusters()
+	if len(clusters) != 2 {
+		t.Fatalf("Returned %d clusters instead of 2", len(clusters))
+	}
+
+	target := clusters[0]
+	if !reflect.DeepEqual(target.Organizations, []string{"Example, Inc."}) || target.Certificates != 3 {
+		t.Errorf("Failed to cluster by organization: %+v", target)
+	}
+	if !reflect.DeepEqual(target.Addresses, []string{"192.0.2.1", "192.0.2.2", "192.0.2.3"}) {
+		t.Errorf("Clustered the addresses %v", target.Addresses)
+	}
+	if !reflect.DeepEqual(target.Domains, []string{"example.com", "example.net"}) ||
+		!reflect.DeepEqual(target.Issuers, []string{"DigiCert", "R3"}) {
+		t.Errorf("Clustered the domains %v and issuers %v", target.Domains, target.Issuers)
+	}
+
+	if hosting := clusters[1]; hosting.Certificates != 1 || len(hosting.Addresses) != 2 || len(hosting.Domains) != len(shared) {
+		t.Errorf("Failed to cluster the shared hosting certificate: %+v", hosting)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/OWASP/Amass/v3/requests"
+	"github.com/OWASP/Amass/v3/systems"
 	"github.com/caffix/pipeline"
 	"github.com/caffix/queue"
 	bf "github.com/tylertreat/BoomFilters"
@@ -44,7 +45,11 @@ func (r *intelSource) InputAddress(req *requests.AddrRequest) {
 	default:
 	}
 
-	if req != nil && !r.filter.TestAndAdd([]byte(req.Address)) {
+	// Addresses excluded from the scope are never investigated
+	if req == nil || systems.IsAddressExcluded(r.collection.Sys, req.Address) {
+		return
+	}
+	if !r.filter.TestAndAdd([]byte(req.Address)) {
 		r.queue.Append(req)
 	}
 }
@@ -15,6 +15,7 @@ import (
 	"github.com/OWASP/Amass/v3/config"
 	"github.com/OWASP/Amass/v3/datasrcs"
 	amassnet "github.com/OWASP/Amass/v3/net"
+	"github.com/OWASP/Amass/v3/net/whois"
 	"github.com/OWASP/Amass/v3/requests"
 	"github.com/OWASP/Amass/v3/systems"
 	"github.com/caffix/pipeline"
@@ -42,6 +43,7 @@ type Collection struct {
 	doneAlreadyClosed bool
 	filter            *bf.StableBloomFilter
 	timeChan          chan time.Time
+	clusters          *certClusters
 }
 
 // NewCollection returns an initialized Collection object that has not been started yet.
@@ -54,6 +56,7 @@ func NewCollection(cfg *config.Config, sys systems.System) *Collection {
 		done:     make(chan struct{}, 2),
 		filter:   bf.NewDefaultStableBloomFilter(1000000, 0.01),
 		timeChan: make(chan time.Time, 50),
+		clusters: newCertClusters(),
 	}
 }
 
@@ -94,9 +97,11 @@ func (c *Collection) HostedDomains(ctx context.Context) error {
 	for _, addr := range c.Config.Addresses {
 		source.InputAddress(&requests.AddrRequest{Address: addr.String()})
 	}
+	var ipv6 []*net.IPNet
 	for _, cidr := range append(c.Config.CIDRs, c.asnsToCIDRs()...) {
-		// Skip IPv6 netblocks, since they are simply too large
+		// IPv6 netblocks are simply too large to investigate every address
 		if ip := cidr.IP.Mask(cidr.Mask); amassnet.IsIPv6(ip) {
+			ipv6 = append(ipv6, cidr)
 			continue
 		}
 
@@ -104,6 +109,15 @@ func (c *Collection) HostedDomains(ctx context.Context) error {
 			source.InputAddress(&requests.AddrRequest{Address: addr.String()})
 		}
 	}
+	if len(ipv6) > 0 {
+		hitlist := c.readIPv6Hitlists(ipv6)
+
+		for _, cidr := range ipv6 {
+			for _, addr := range c.ipv6Addresses(cidr, hitlist) {
+				source.InputAddress(&requests.AddrRequest{Address: addr.String()})
+			}
+		}
+	}
 
 	return pipeline.NewPipeline(stages...).Execute(ctx, source, c.makeOutputSink())
 }
@@ -174,7 +188,8 @@ func (c *Collection) makeFilterTaskFunc() pipeline.TaskFunc {
 		default:
 		}
 
-		if req, ok := data.(*requests.Output); ok && req != nil && !c.filter.TestAndAdd([]byte(req.Domain)) {
+		if req, ok := data.(*requests.Output); ok && req != nil &&
+			!c.Config.Blacklisted(req.Domain) && !c.filter.TestAndAdd([]byte(req.Domain)) {
 			return data, nil
 		}
 		return nil, nil
@@ -192,6 +207,10 @@ func (c *Collection) asnsToCIDRs() []*net.IPNet {
 	defer cidrSet.Close()
 
 	for _, asn := range c.Config.ASNs {
+		if c.Config.IsASNExcluded(asn) {
+			continue
+		}
+
 		req := c.Sys.Cache().ASNSearch(asn)
 
 		if req == nil {
@@ -230,6 +249,28 @@ func (c *Collection) ReverseWhois() error {
 		return err
 	}
 
+	// Setup the context used throughout the collection, which is cancelled when the collection is done
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c.ctx = ctx
+	go func() {
+		select {
+		case <-c.done:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	// The RDAP bootstrap registry is shared by the lookups of all the domains
+	var bootstrap *whois.Bootstrap
+	if c.Config.RDAPBootstrap != "" {
+		b, err := whois.LoadBootstrap(c.Config.RDAPBootstrap)
+		if err != nil {
+			c.Config.Log.Print(err.Error())
+		}
+		bootstrap = b
+	}
+
 	go func() {
 		for {
 			for _, src := range c.srcs {
@@ -244,9 +285,18 @@ func (c *Collection) ReverseWhois() error {
 		}
 	}()
 	// Send the whois requests to the data sources
-	for _, src := range c.srcs {
-		for _, domain := range c.Config.Domains() {
-			src.Input() <- &requests.WhoisRequest{Domain: domain}
+	for _, domain := range c.Config.Domains() {
+		if ctx.Err() != nil {
+			break
+		}
+		req := c.registration(ctx, bootstrap, domain)
+
+		for _, src := range c.srcs {
+			src.Input() <- &requests.WhoisRequest{
+				Domain:  req.Domain,
+				Company: req.Company,
+				Email:   req.Email,
+			}
 		}
 	}
 
@@ -272,6 +322,34 @@ loop:
 	return nil
 }
 
+// Obtains the registrant details for the domain using WHOIS and, when the bootstrap registry is provided, RDAP.
+func (c *Collection) registration(ctx context.Context, bootstrap *whois.Bootstrap, domain string) *requests.WhoisRequest {
+	req := &requests.WhoisRequest{Domain: domain}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	rec, err := whois.Lookup(ctx, "", domain)
+	if err != nil && c.Config.Verbose {
+		c.Config.Log.Printf("WHOIS: %s: %v", domain, err)
+	}
+
+	if bootstrap != nil && (rec == nil || rec.Company == "" || rec.Email() == "") {
+		if r, err := bootstrap.Lookup(ctx, domain); err == nil {
+			r.Merge(rec)
+			rec = r
+		} else if c.Config.Verbose {
+			c.Config.Log.Printf("RDAP: %s: %v", domain, err)
+		}
+	}
+
+	if rec != nil {
+		req.Company = rec.Company
+		req.Email = rec.Email()
+	}
+	return req
+}
+
 func (c *Collection) collect(req *requests.WhoisRequest) {
 	c.timeChan <- time.Now()
 
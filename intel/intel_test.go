@@ -0,0 +1,29 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package intel
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/OWASP/Amass/v3/config"
+)
+
+func TestRegistrationCancelled(t *testing.T) {
+	c := &Collection{Config: config.NewConfig()}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	start := time.Now()
+	req := c.registration(ctx, nil, "owasp.org")
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("registration took %s after the collection was cancelled", elapsed)
+	}
+	if req.Domain != "owasp.org" || req.Company != "" || req.Email != "" {
+		t.Errorf("registration returned the incorrect details: %v", req)
+	}
+}
@@ -0,0 +1,176 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package intel
+
+import (
+	"context"
+	"math/rand"
+	"net"
+	"os"
+
+	"github.com/OWASP/Amass/v3/format"
+	amassnet "github.com/OWASP/Amass/v3/net"
+	"github.com/caffix/resolve"
+	"github.com/miekg/dns"
+)
+
+const (
+	// The maximum number of PTR queries used to walk the ip6.arpa tree of a single netblock
+	maxIPv6ReverseQueries int = 5000
+	// The number of /64 subnets at the start of a netblock that are probed for low-byte addresses
+	numIPv6StartSubnets int = 16
+	// The maximum number of /64 subnets containing known addresses that are probed for patterns
+	maxIPv6PatternSubnets int = 256
+	numIPv6LowByteHosts   int = 16
+	numIPv6EUI64Neighbors int = 8
+	// Netblocks at least this small are small enough to investigate every address
+	minIPv6AllHostsPrefix int = 120
+)
+
+// Returns the addresses within the IPv6 netblock that are worth investigating, since the
+// netblocks are too large to investigate every address. The ip6.arpa tree is walked, the hitlist
+// addresses are used as seeds, and the subnets are probed for common addressing patterns.
+func (c *Collection) ipv6Addresses(cidr *net.IPNet, hitlist []net.IP) []net.IP {
+	if ones, _ := cidr.Mask.Size(); ones >= minIPv6AllHostsPrefix {
+		return amassnet.AllHosts(cidr)
+	}
+
+	budget := maxIPv6ReverseQueries
+	var known []net.IP
+	for _, prefix := range amassnet.IPv6NibblePrefixes(cidr) {
+		known = append(known, c.walkIPv6ReverseTree(c.ctx, prefix, &budget)...)
+	}
+	if c.Config.Verbose {
+		c.Config.Log.Printf("IPv6: %s: %d addresses discovered in the ip6.arpa tree", cidr, len(known))
+	}
+
+	for _, ip := range hitlist {
+		if cidr.Contains(ip) {
+			known = append(known, ip)
+		}
+	}
+
+	ips := append([]net.IP{}, known...)
+	for _, subnet := range amassnet.IPv6Subnets(cidr, 64, numIPv6StartSubnets) {
+		ips = append(ips, amassnet.LowByteHosts(subnet, numIPv6LowByteHosts)...)
+	}
+
+	// Hosts are often numbered near the addresses already known to be in use
+	subnets := make(map[string]struct{})
+	for _, ip := range known {
+		subnet := &net.IPNet{IP: ip.Mask(net.CIDRMask(64, 128)), Mask: net.CIDRMask(64, 128)}
+
+		if _, found := subnets[subnet.String()]; !found && len(subnets) < maxIPv6PatternSubnets {
+			subnets[subnet.String()] = struct{}{}
+			ips = append(ips, amassnet.LowByteHosts(subnet, numIPv6LowByteHosts)...)
+		}
+		ips = append(ips, amassnet.EUI64Neighbors(ip, numIPv6EUI64Neighbors)...)
+	}
+	return ips
+}
+
+// Walks the ip6.arpa tree below the hexadecimal digit prefix, using NXDOMAIN responses to prune the
+// branches that do not exist, and returns the addresses that have PTR records.
+func (c *Collection) walkIPv6ReverseTree(ctx context.Context, prefix []byte, budget *int) []net.IP {
+	if len(prefix) == 32 {
+		return []net.IP{amassnet.NibblesToIPv6(prefix)}
+	}
+
+	children := c.ipv6ReverseChildren(ctx, prefix, budget)
+	// Zones that synthesize a PTR record for every address cannot be walked
+	if len(children) == 16 && c.syntheticIPv6Zone(ctx, prefix, budget) {
+		return nil
+	}
+
+	var ips []net.IP
+	for _, child := range children {
+		ips = append(ips, c.walkIPv6ReverseTree(ctx, child, budget)...)
+	}
+	return ips
+}
+
+// Returns the children of the ip6.arpa tree node that exist, while spending the query budget.
+func (c *Collection) ipv6ReverseChildren(ctx context.Context, prefix []byte, budget *int) [][]byte {
+	if *budget < 16 {
+		return nil
+	}
+	*budget -= 16
+
+	var candidates [][]byte
+	var chans []chan *dns.Msg
+	for n := byte(0); n < 16; n++ {
+		child := make([]byte, len(prefix)+1)
+		copy(child, prefix)
+		child[len(prefix)] = n
+
+		candidates = append(candidates, child)
+		chans = append(chans, c.Sys.TrustedResolvers().QueryChan(ctx,
+			resolve.QueryMsg(amassnet.IPv6ReverseName(child), dns.TypePTR)))
+	}
+
+	var children [][]byte
+	for i, ch := range chans {
+		var resp *dns.Msg
+		select {
+		case <-ctx.Done():
+			return nil
+		case resp = <-ch:
+		}
+		if resp == nil || resp.Rcode != dns.RcodeSuccess {
+			continue
+		}
+		// Only complete addresses with PTR records are of interest
+		if len(candidates[i]) == 32 && len(resolve.ExtractAnswers(resp)) == 0 {
+			continue
+		}
+		children = append(children, candidates[i])
+	}
+	return children
+}
+
+// Checks if a random address below the ip6.arpa tree node has a PTR record. Without the query
+// budget, the zone is treated as real, since the walk stops once the budget is spent.
+func (c *Collection) syntheticIPv6Zone(ctx context.Context, prefix []byte, budget *int) bool {
+	if *budget < 1 {
+		return false
+	}
+	*budget--
+
+	nibbles := make([]byte, 32)
+	copy(nibbles, prefix)
+	for i := len(prefix); i < 32; i++ {
+		nibbles[i] = byte(rand.Intn(16))
+	}
+
+	resp, err := c.Sys.TrustedResolvers().QueryBlocking(ctx,
+		resolve.QueryMsg(amassnet.IPv6ReverseName(nibbles), dns.TypePTR))
+	return err == nil && resp.Rcode == dns.RcodeSuccess && len(resolve.ExtractAnswers(resp)) > 0
+}
+
+// Returns the IPv6 addresses in the configured hitlist files that are within the netblocks.
+func (c *Collection) readIPv6Hitlists(cidrs []*net.IPNet) []net.IP {
+	var ips []net.IP
+
+	for _, path := range c.Config.IPv6HitlistFiles {
+		f, err := os.Open(path)
+		if err != nil {
+			c.Config.Log.Printf("Failed to open the IPv6 hitlist %s: %v", path, err)
+			continue
+		}
+
+		if err := format.ReadIPv6Hitlist(f, func(ip net.IP) {
+			for _, cidr := range cidrs {
+				if cidr.Contains(ip) {
+					ips = append(ips, ip)
+					break
+				}
+			}
+		}); err != nil {
+			c.Config.Log.Printf("Failed to read the IPv6 hitlist %s: %v", path, err)
+		}
+		f.Close()
+	}
+	return ips
+}
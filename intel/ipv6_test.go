@@ -0,0 +1,258 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package intel
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"sync/atomic"
+	"testing"
+
+	"github.com/OWASP/Amass/v3/config"
+	amassnet "github.com/OWASP/Amass/v3/net"
+	"github.com/OWASP/Amass/v3/systems"
+	"github.com/caffix/resolve"
+	"github.com/miekg/dns"
+)
+
+// The stub resolver answering the PTR queries for the ip6.arpa tree.
+type ipv6ReverseStub struct {
+	queries uint64
+	// The reverse names of the addresses with PTR records
+	ptrs map[string]struct{}
+	// The reverse names of the tree nodes above the addresses
+	nodes map[string]struct{}
+	// Answer every query, like the zones that synthesize the PTR records
+	synthetic bool
+}
+
+func newIPv6ReverseStub(addrs ...string) *ipv6ReverseStub {
+	s := &ipv6ReverseStub{
+		ptrs:  make(map[string]struct{}),
+		nodes: make(map[string]struct{}),
+	}
+
+	for _, addr := range addrs {
+		nibbles := amassnet.IPv6Nibbles(net.ParseIP(addr))
+
+		s.ptrs[dns.Fqdn(amassnet.IPv6ReverseName(nibbles))] = struct{}{}
+		for i := 1; i < len(nibbles); i++ {
+			s.nodes[dns.Fqdn(amassnet.IPv6ReverseName(nibbles[:i]))] = struct{}{}
+		}
+	}
+	return s
+}
+
+func (s *ipv6ReverseStub) ServeDNS(w dns.ResponseWriter, req *dns.Msg) {
+	atomic.AddUint64(&s.queries, 1)
+
+	name := req.Question[0].Name
+	m := new(dns.Msg)
+	m.SetReply(req)
+
+	if _, found := s.ptrs[name]; found || s.synthetic {
+		m.Answer = append(m.Answer, &dns.PTR{
+			Hdr: dns.RR_Header{Name: name, Rrtype: dns.TypePTR, Class: dns.ClassINET, Ttl: 60},
+			Ptr: "host.example.com.",
+		})
+	} else if _, found := s.nodes[name]; !found {
+		m.Rcode = dns.RcodeNameError
+	}
+	_ = w.WriteMsg(m)
+}
+
+func (s *ipv6ReverseStub) numQueries() int {
+	return int(atomic.LoadUint64(&s.queries))
+}
+
+// Returns a Collection that sends the trusted DNS queries to the stub resolver.
+func newIPv6TestCollection(t *testing.T, stub *ipv6ReverseStub) *Collection {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to start the stub resolver: %v", err)
+	}
+
+	server := &dns.Server{PacketConn: pc, Handler: stub}
+	go func() { _ = server.ActivateAndServe() }()
+	t.Cleanup(func() { _ = server.Shutdown() })
+
+	trusted := resolve.NewResolvers()
+	if err := trusted.AddResolvers(10000, pc.LocalAddr().String()); err != nil {
+		t.Fatalf("failed to add the stub resolver: %v", err)
+	}
+	t.Cleanup(trusted.Stop)
+
+	cfg := config.NewConfig()
+	return &Collection{
+		Config: cfg,
+		Sys:    &systems.SimpleSystem{Cfg: cfg, Trusted: trusted},
+		ctx:    context.Background(),
+	}
+}
+
+func ipv6Prefix(t *testing.T, cidr string) []byte {
+	_, ipnet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	prefixes := amassnet.IPv6NibblePrefixes(ipnet)
+	if len(prefixes) != 1 {
+		t.Fatalf("%s provided %d nibble prefixes", cidr, len(prefixes))
+	}
+	return prefixes[0]
+}
+
+func TestWalkIPv6ReverseTree(t *testing.T) {
+	addrs := []string{"2001:db8::1", "2001:db8::1:2", "2001:db8:0:1::10"}
+	stub := newIPv6ReverseStub(addrs...)
+	c := newIPv6TestCollection(t, stub)
+
+	budget := maxIPv6ReverseQueries
+	ips := c.walkIPv6ReverseTree(c.ctx, ipv6Prefix(t, "2001:db8::/32"), &budget)
+
+	var got []string
+	for _, ip := range ips {
+		got = append(got, ip.String())
+	}
+	sort.Strings(got)
+	sort.Strings(addrs)
+	if len(got) != len(addrs) {
+		t.Fatalf("walkIPv6ReverseTree returned %v, expected %v", got, addrs)
+	}
+	for i := range addrs {
+		if got[i] != addrs[i] {
+			t.Errorf("walkIPv6ReverseTree returned %v, expected %v", got, addrs)
+			break
+		}
+	}
+
+	// The NXDOMAIN responses prune the branches, so only the nodes above the addresses are queried
+	nodes := make(map[string]struct{})
+	for _, addr := range addrs {
+		nibbles := amassnet.IPv6Nibbles(net.ParseIP(addr))
+
+		for i := 8; i < len(nibbles); i++ {
+			nodes[string(nibbles[:i])] = struct{}{}
+		}
+	}
+	expected := 16 * len(nodes)
+	if n := stub.numQueries(); n != expected {
+		t.Errorf("walkIPv6ReverseTree sent %d queries, expected %d", n, expected)
+	}
+	if budget != maxIPv6ReverseQueries-expected {
+		t.Errorf("walkIPv6ReverseTree left a budget of %d, expected %d", budget, maxIPv6ReverseQueries-expected)
+	}
+}
+
+func TestWalkIPv6ReverseTreeBudget(t *testing.T) {
+	stub := newIPv6ReverseStub("2001:db8::1")
+	c := newIPv6TestCollection(t, stub)
+
+	budget := 40
+	if ips := c.walkIPv6ReverseTree(c.ctx, ipv6Prefix(t, "2001:db8::/32"), &budget); len(ips) != 0 {
+		t.Errorf("walkIPv6ReverseTree returned %v after spending the query budget", ips)
+	}
+	// Only two nodes can be queried, and the remaining budget is too small for a third node
+	if n := stub.numQueries(); n != 32 {
+		t.Errorf("walkIPv6ReverseTree sent %d queries with a budget of 40", n)
+	}
+	if budget != 8 {
+		t.Errorf("walkIPv6ReverseTree left a budget of %d, expected 8", budget)
+	}
+}
+
+func TestWalkIPv6ReverseTreeSynthetic(t *testing.T) {
+	stub := newIPv6ReverseStub()
+	stub.synthetic = true
+	c := newIPv6TestCollection(t, stub)
+
+	budget := maxIPv6ReverseQueries
+	if ips := c.walkIPv6ReverseTree(c.ctx, ipv6Prefix(t, "2001:db8::/32"), &budget); len(ips) != 0 {
+		t.Errorf("walkIPv6ReverseTree returned %d addresses from the synthetic zone", len(ips))
+	}
+	// The children and the random address are queried before the zone is abandoned
+	if n := stub.numQueries(); n != 17 {
+		t.Errorf("walkIPv6ReverseTree sent %d queries to the synthetic zone, expected 17", n)
+	}
+}
+
+func TestWalkIPv6ReverseTreeBudgetSpent(t *testing.T) {
+	var addrs []string
+	for i := 0; i < 16; i++ {
+		addrs = append(addrs, fmt.Sprintf("2001:db8::%x", i))
+	}
+	stub := newIPv6ReverseStub(addrs...)
+	c := newIPv6TestCollection(t, stub)
+
+	// The budget is spent by the children, so the zone cannot be checked for synthesized records
+	budget := 16
+	if ips := c.walkIPv6ReverseTree(c.ctx, ipv6Prefix(t, "2001:db8::/124"), &budget); len(ips) != 16 {
+		t.Errorf("walkIPv6ReverseTree returned %d addresses after spending the query budget, expected 16", len(ips))
+	}
+	if n := stub.numQueries(); n != 16 || budget != 0 {
+		t.Errorf("walkIPv6ReverseTree sent %d queries and left a budget of %d", n, budget)
+	}
+}
+
+func TestIPv6ReverseChildren(t *testing.T) {
+	stub := newIPv6ReverseStub("2001:db8::1", "2001:db8::3")
+	// The node exists, but the address does not have a PTR record
+	stub.nodes[dns.Fqdn(amassnet.IPv6ReverseName(amassnet.IPv6Nibbles(net.ParseIP("2001:db8::2"))))] = struct{}{}
+	c := newIPv6TestCollection(t, stub)
+
+	prefix := ipv6Prefix(t, "2001:db8::/124")
+	budget := 20
+	children := c.ipv6ReverseChildren(c.ctx, prefix, &budget)
+	if len(children) != 2 || children[0][31] != 1 || children[1][31] != 3 {
+		t.Errorf("ipv6ReverseChildren returned %v, expected the children 1 and 3", children)
+	}
+	if budget != 4 {
+		t.Errorf("ipv6ReverseChildren left a budget of %d, expected 4", budget)
+	}
+
+	// The remaining budget is too small to query the children
+	if children := c.ipv6ReverseChildren(c.ctx, prefix, &budget); children != nil {
+		t.Errorf("ipv6ReverseChildren returned %v without the query budget", children)
+	}
+	if n := stub.numQueries(); n != 16 || budget != 4 {
+		t.Errorf("ipv6ReverseChildren sent %d queries and left a budget of %d", n, budget)
+	}
+
+	// The nodes that do not exist are pruned
+	budget = 16
+	if children := c.ipv6ReverseChildren(c.ctx, ipv6Prefix(t, "2001:db9::/32"), &budget); len(children) != 0 {
+		t.Errorf("ipv6ReverseChildren returned %v for the NXDOMAIN responses", children)
+	}
+}
+
+func TestSyntheticIPv6Zone(t *testing.T) {
+	stub := newIPv6ReverseStub("2001:db8::1")
+	c := newIPv6TestCollection(t, stub)
+	prefix := ipv6Prefix(t, "2001:db8::/32")
+
+	budget := 1
+	if c.syntheticIPv6Zone(c.ctx, prefix, &budget) {
+		t.Error("syntheticIPv6Zone reported the zone as synthetic")
+	}
+	if budget != 0 || stub.numQueries() != 1 {
+		t.Errorf("syntheticIPv6Zone sent %d queries and left a budget of %d", stub.numQueries(), budget)
+	}
+	// Without the query budget, the zone is treated as real and the children already found are kept
+	if c.syntheticIPv6Zone(c.ctx, prefix, &budget) || stub.numQueries() != 1 {
+		t.Error("syntheticIPv6Zone reported the zone as synthetic without the query budget")
+	}
+
+	synthetic := newIPv6ReverseStub()
+	synthetic.synthetic = true
+	c = newIPv6TestCollection(t, synthetic)
+
+	budget = 1
+	if !c.syntheticIPv6Zone(c.ctx, prefix, &budget) {
+		t.Error("syntheticIPv6Zone did not detect the synthetic zone")
+	}
+}
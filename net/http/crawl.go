@@ -0,0 +1,331 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/geziyor/geziyor"
+	"github.com/geziyor/geziyor/client"
+	bf "github.com/tylertreat/BoomFilters"
+)
+
+const (
+	maxCrawlBodySize = 50 * 1024 * 1024 // 50MB
+	crawlDepthKey    = "depth"
+)
+
+var (
+	crawlAttrs = []string{"action", "cite", "data", "formaction",
+		"href", "longdesc", "poster", "src", "srcset", "xmlns"}
+	crawlTags = []string{"a", "area", "audio", "base", "blockquote", "button",
+		"embed", "form", "frame", "frameset", "html", "iframe", "img", "input",
+		"ins", "link", "noframes", "object", "q", "script", "source", "track", "video"}
+	// The response headers that reference other hosts
+	crawlHostHeaders = []string{"Content-Security-Policy", "Content-Security-Policy-Report-Only",
+		"X-Content-Security-Policy", "X-Webkit-CSP", "Access-Control-Allow-Origin"}
+
+	jsURLRE       = regexp.MustCompile(`(?i)(?:https?:)?//[a-z0-9][a-z0-9.\-]*\.[a-z]{2,}(?::[0-9]{1,5})?(?:/[^\s"'<>\x60\\()]*)?`)
+	jsPathRE      = regexp.MustCompile(`["'\x60](/[a-zA-Z0-9_\-][a-zA-Z0-9_\-./]*(?:\?[^\s"'<>\x60]*)?)["'\x60]`)
+	sourceMapRE   = regexp.MustCompile(`//[#@]\s*sourceMappingURL=(\S+)`)
+	sitemapLocRE  = regexp.MustCompile(`(?i)<loc>\s*([^<\s]+)\s*</loc>`)
+	headerHostRE  = regexp.MustCompile(`(?i)(?:https?://|\*\.)?([a-z0-9][a-z0-9\-]*(?:\.[a-z0-9][a-z0-9\-]*)+)`)
+	robotsEntryRE = regexp.MustCompile(`(?i)^\s*(sitemap|allow|disallow)\s*:\s*(\S+)`)
+)
+
+// CrawlOptions contains the settings that constrain a web crawl.
+type CrawlOptions struct {
+	// The domain names that followed links must belong to
+	Scope []string
+	// The maximum number of links followed, where zero means no limit
+	MaxLinks int
+	// The maximum number of links between the starting URL and a followed link
+	MaxDepth int
+	// The maximum number of response body bytes downloaded during the crawl
+	MaxBytes int64
+	// The proxy URL used for the crawl requests
+	Proxy string
+}
+
+// Crawl will spider the web page at the URL argument looking while staying within the scope provided.
+func Crawl(ctx context.Context, u string, scope []string, max int, callback func(*Request, *Response)) error {
+	return CrawlWithOptions(ctx, u, &CrawlOptions{Scope: scope, MaxLinks: max}, callback)
+}
+
+// CrawlWithProxy will spider the web page at the URL argument through the proxy while staying within the scope provided.
+func CrawlWithProxy(ctx context.Context, u string, scope []string, max int, proxy string, callback func(*Request, *Response)) error {
+	return CrawlWithOptions(ctx, u, &CrawlOptions{Scope: scope, MaxLinks: max, Proxy: proxy}, callback)
+}
+
+// CrawlWithOptions will spider the web page at the URL argument within the constraints provided by the options.
+// In addition to the HTML links, the crawl follows the robots.txt and sitemap.xml entries, the URLs referenced
+// by inline and external JavaScript, the JavaScript source maps, and the hosts found in CSP and CORS headers.
+// JavaScript and source maps are fetched from any host, while all other links must be within scope.
+func CrawlWithOptions(ctx context.Context, u string, opts *CrawlOptions, callback func(*Request, *Response)) error {
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("the context expired")
+	default:
+	}
+
+	if opts == nil {
+		opts = &CrawlOptions{}
+	}
+
+	hc, err := ClientForProxy(opts.Proxy)
+	if err != nil {
+		return err
+	}
+
+	start, err := url.Parse(u)
+	if err != nil {
+		return err
+	}
+
+	c := &crawler{
+		opts:   opts,
+		filter: bf.NewDefaultStableBloomFilter(10000, 0.01),
+	}
+	defer c.filter.Reset()
+
+	maxBody := int64(maxCrawlBodySize)
+	if opts.MaxBytes > 0 && opts.MaxBytes < maxBody {
+		maxBody = opts.MaxBytes
+	}
+
+	g := geziyor.NewGeziyor(&geziyor.Options{
+		StartRequestsFunc: func(g *geziyor.Geziyor) {
+			c.filter.Add([]byte(u))
+			c.get(g, u, 0)
+			// The robots.txt and sitemap.xml files are commonly used to list the site content
+			for _, path := range []string{"/robots.txt", "/sitemap.xml"} {
+				if link, err := start.Parse(path); err == nil {
+					c.follow(g, link.String(), 1, true, false)
+				}
+			}
+		},
+		RobotsTxtDisabled:     true,
+		UserAgent:             UserAgent,
+		LogDisabled:           true,
+		ConcurrentRequests:    5,
+		RequestDelay:          time.Second,
+		RequestDelayRandomize: true,
+		ParseFunc: func(g *geziyor.Geziyor, r *client.Response) {
+			select {
+			case <-ctx.Done():
+				return
+			default:
+			}
+
+			c.parse(g, r)
+			callback(ReqToAmassRequest(r.Request.Request), &Response{
+				Status:     r.Status,
+				StatusCode: r.StatusCode,
+				Proto:      r.Proto,
+				ProtoMajor: r.ProtoMajor,
+				ProtoMinor: r.ProtoMinor,
+				Header:     HdrToAmassHeader(r.Header),
+				Body:       string(r.Body),
+				Length:     r.ContentLength,
+				TLS:        r.TLS,
+			})
+		},
+	})
+	g.Client = client.NewClient(&client.Options{
+		MaxBodySize:    maxBody,
+		RetryTimes:     2,
+		RetryHTTPCodes: []int{408, 500, 502, 503, 504, 522, 524},
+	})
+	g.Client.Client = hc
+
+	g.Start()
+	return nil
+}
+
+type crawler struct {
+	sync.Mutex
+	opts   *CrawlOptions
+	filter *bf.StableBloomFilter
+	count  int
+	bytes  int64
+}
+
+func (c *crawler) get(g *geziyor.Geziyor, link string, depth int) {
+	req, err := client.NewRequest("GET", link, nil)
+	if err != nil {
+		return
+	}
+
+	req.Meta[crawlDepthKey] = depth
+	g.Do(req, g.Opt.ParseFunc)
+}
+
+// Requests the link when it has not been seen and the crawl is within its budgets. Links that are
+// not resources, such as JavaScript and source maps, must be within scope. The robots.txt and
+// sitemap.xml files are not counted against the maximum number of links.
+func (c *crawler) follow(g *geziyor.Geziyor, link string, depth int, meta, resource bool) {
+	u, err := url.Parse(link)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return
+	}
+	if host := u.Hostname(); host == "" || (!resource && whichDomain(host, c.opts.Scope) == "") {
+		return
+	}
+	if c.opts.MaxDepth > 0 && depth > c.opts.MaxDepth {
+		return
+	}
+
+	u.Fragment = ""
+	s := u.String()
+
+	c.Lock()
+	defer c.Unlock()
+
+	if c.opts.MaxBytes > 0 && c.bytes >= c.opts.MaxBytes {
+		return
+	}
+	if s == "" || c.filter.Test([]byte(s)) {
+		return
+	}
+	if !meta {
+		c.count++
+		// Be sure the crawl has not exceeded the maximum links to be followed
+		if c.opts.MaxLinks > 0 && c.count >= c.opts.MaxLinks {
+			return
+		}
+	}
+
+	c.filter.Add([]byte(s))
+	c.get(g, s, depth)
+}
+
+func (c *crawler) parse(g *geziyor.Geziyor, r *client.Response) {
+	c.Lock()
+	c.bytes += int64(len(r.Body))
+	c.Unlock()
+
+	var depth int
+	if d, ok := r.Request.Meta[crawlDepthKey].(int); ok {
+		depth = d
+	}
+	next := depth + 1
+	base := r.Request.URL
+
+	resolve := func(ref string) string {
+		ref = strings.TrimSpace(ref)
+		if strings.HasPrefix(ref, "//") {
+			ref = base.Scheme + ":" + ref
+		}
+		if u, err := base.Parse(ref); err == nil {
+			return u.String()
+		}
+		return ""
+	}
+
+	body := string(r.Body)
+	ctype := strings.ToLower(r.Header.Get("Content-Type"))
+	path := strings.ToLower(base.Path)
+
+	switch {
+	case strings.HasSuffix(path, "/robots.txt"):
+		for _, entry := range robotsEntries(body) {
+			c.follow(g, resolve(entry), next, true, false)
+		}
+	case strings.HasSuffix(path, ".xml") || strings.Contains(ctype, "xml") && !strings.Contains(ctype, "html"):
+		for _, loc := range sitemapLocRE.FindAllStringSubmatch(body, -1) {
+			link := resolve(loc[1])
+			// Nested sitemaps do not count against the maximum number of links
+			c.follow(g, link, next, strings.HasSuffix(strings.ToLower(link), ".xml"), false)
+		}
+	case r.HTMLDoc != nil:
+		tag := func(i int, s *goquery.Selection) {
+			for _, attr := range crawlAttrs {
+				if name, ok := s.Attr(attr); ok {
+					// External JavaScript is fetched even when served from hosts outside the scope
+					c.follow(g, resolve(name), next, false, goquery.NodeName(s) == "script" && attr == "src")
+				}
+			}
+		}
+		for _, t := range crawlTags {
+			r.HTMLDoc.Find(t).Each(tag)
+		}
+		// Inline JavaScript
+		r.HTMLDoc.Find("script").Each(func(i int, s *goquery.Selection) {
+			if _, ok := s.Attr("src"); !ok {
+				c.followScript(g, s.Text(), resolve, next)
+			}
+		})
+	default:
+		// JavaScript, source maps, JSON and other text content
+		c.followScript(g, body, resolve, next)
+	}
+
+	// Source maps can be referenced by the response headers
+	for _, hdr := range []string{"SourceMap", "X-SourceMap"} {
+		if ref := r.Header.Get(hdr); ref != "" {
+			c.follow(g, resolve(ref), next, false, true)
+		}
+	}
+	// Follow the in-scope hosts referenced by the CSP and CORS headers
+	for _, hdr := range crawlHostHeaders {
+		for _, v := range r.Header.Values(hdr) {
+			for _, host := range headerHosts(v) {
+				c.follow(g, base.Scheme+"://"+host+"/", next, false, false)
+			}
+		}
+	}
+}
+
+// Follows the URLs and source maps referenced by JavaScript code.
+func (c *crawler) followScript(g *geziyor.Geziyor, code string, resolve func(string) string, depth int) {
+	for _, m := range sourceMapRE.FindAllStringSubmatch(code, -1) {
+		if ref := m[1]; !strings.HasPrefix(ref, "data:") {
+			c.follow(g, resolve(ref), depth, false, true)
+		}
+	}
+	for _, link := range jsURLRE.FindAllString(code, -1) {
+		c.follow(g, resolve(link), depth, false, false)
+	}
+	for _, m := range jsPathRE.FindAllStringSubmatch(code, -1) {
+		if !strings.HasPrefix(m[1], "//") {
+			c.follow(g, resolve(m[1]), depth, false, false)
+		}
+	}
+}
+
+// Returns the sitemaps and paths listed in the robots.txt content. Paths containing wildcards are skipped.
+func robotsEntries(body string) []string {
+	var entries []string
+
+	for _, line := range strings.Split(body, "\n") {
+		m := robotsEntryRE.FindStringSubmatch(line)
+		if m == nil || strings.ContainsAny(m[2], "*$") {
+			continue
+		}
+		entries = append(entries, m[2])
+	}
+	return entries
+}
+
+// Returns the hostnames referenced by a CSP or CORS header value.
+func headerHosts(value string) []string {
+	var hosts []string
+
+	for _, m := range headerHostRE.FindAllStringSubmatch(value, -1) {
+		host := strings.ToLower(m[1])
+		// Skip the CSP keywords and directives
+		if strings.Contains(host, ".") {
+			hosts = append(hosts, host)
+		}
+	}
+	return hosts
+}
@@ -6,8 +6,10 @@ package http
 
 import (
 	"context"
+	"crypto/sha256"
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
@@ -24,11 +26,7 @@ import (
 
 	amassnet "github.com/OWASP/Amass/v3/net"
 	"github.com/OWASP/Amass/v3/net/dns"
-	"github.com/PuerkitoBio/goquery"
 	"github.com/caffix/stringset"
-	"github.com/geziyor/geziyor"
-	"github.com/geziyor/geziyor/client"
-	bf "github.com/tylertreat/BoomFilters"
 )
 
 const (
@@ -53,6 +51,12 @@ var (
 // DefaultClient is the same HTTP client used by the package methods.
 var DefaultClient *http.Client
 
+var (
+	jar         http.CookieJar
+	proxyLock   sync.Mutex
+	proxyClient map[string]*http.Client
+)
+
 // Header represents the HTTP headers for requests and responses.
 type Header map[string]string
 
@@ -63,6 +67,8 @@ type Request struct {
 	Header Header
 	Body   string
 	Auth   *BasicAuth
+	// The proxy URL used for the request instead of the DefaultClient settings
+	Proxy string
 }
 
 // Response represents the HTTP response in the Amass preferred format.
@@ -85,21 +91,9 @@ type BasicAuth struct {
 }
 
 func init() {
-	jar, _ := cookiejar.New(nil)
-	DefaultClient = &http.Client{
-		Timeout: httpTimeout,
-		Transport: &http.Transport{
-			Proxy:                 http.ProxyFromEnvironment,
-			DialContext:           amassnet.DialContext,
-			MaxIdleConns:          200,
-			MaxConnsPerHost:       50,
-			IdleConnTimeout:       90 * time.Second,
-			TLSHandshakeTimeout:   handshakeTimeout,
-			ExpectContinueTimeout: 5 * time.Second,
-			TLSClientConfig:       &tls.Config{InsecureSkipVerify: true},
-		},
-		Jar: jar,
-	}
+	jar, _ = cookiejar.New(nil)
+	proxyClient = make(map[string]*http.Client)
+	DefaultClient, _ = newClient("")
 
 	switch runtime.GOOS {
 	case "windows":
@@ -111,6 +105,79 @@ func init() {
 	}
 }
 
+func newClient(proxy string) (*http.Client, error) {
+	transport := &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           amassnet.DialContext,
+		MaxIdleConns:          200,
+		MaxConnsPerHost:       50,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   handshakeTimeout,
+		ExpectContinueTimeout: 5 * time.Second,
+		TLSClientConfig:       &tls.Config{InsecureSkipVerify: true},
+	}
+
+	if proxy != "" {
+		u, err := amassnet.ParseProxy(proxy)
+		if err != nil {
+			return nil, err
+		}
+
+		switch strings.ToLower(u.Scheme) {
+		case "http", "https":
+			transport.Proxy = http.ProxyURL(u)
+		default:
+			dial, err := amassnet.ProxyDialContext(proxy)
+			if err != nil {
+				return nil, err
+			}
+			transport.Proxy = nil
+			transport.DialContext = dial
+		}
+	}
+
+	return &http.Client{
+		Timeout:   httpTimeout,
+		Transport: transport,
+		Jar:       jar,
+	}, nil
+}
+
+// SetDefaultProxy configures the DefaultClient to send all requests through the proxy.
+// Supported proxy URLs use the http, https, socks5 and socks5h schemes, and can include
+// credentials. An empty proxy URL restores the proxy settings from the environment.
+func SetDefaultProxy(proxy string) error {
+	c, err := newClient(proxy)
+	if err != nil {
+		return err
+	}
+
+	DefaultClient = c
+	return nil
+}
+
+// ClientForProxy returns the HTTP client that sends requests through the proxy.
+// The DefaultClient is returned when the proxy URL is empty.
+func ClientForProxy(proxy string) (*http.Client, error) {
+	if proxy == "" {
+		return DefaultClient, nil
+	}
+
+	proxyLock.Lock()
+	defer proxyLock.Unlock()
+
+	if c, found := proxyClient[proxy]; found {
+		return c, nil
+	}
+
+	c, err := newClient(proxy)
+	if err != nil {
+		return nil, err
+	}
+	proxyClient[proxy] = c
+	return c, nil
+}
+
 // HdrToAmassHeader converts a net/http Header to an Amass Header.
 func HdrToAmassHeader(hdr http.Header) Header {
 	h := make(Header)
@@ -206,6 +273,29 @@ func RequestWebPage(ctx context.Context, r *Request) (*Response, error) {
 		return nil, errors.New("failed to provide a valid HTTP method")
 	}
 
+	c, err := ClientForProxy(r.Proxy)
+	if err != nil {
+		return nil, err
+	}
+
+	max, backoff := retryPolicy()
+	for attempt := 0; ; attempt++ {
+		resp, err := sendRequest(ctx, c, r)
+		if attempt >= max || ctx.Err() != nil || (err == nil && !retryStatus(resp.StatusCode)) {
+			return resp, err
+		}
+
+		var retryAfter string
+		if resp != nil {
+			retryAfter = resp.Header["Retry-After"]
+		}
+		if err := sleepContext(ctx, retryDelay(attempt, backoff, retryAfter)); err != nil {
+			return resp, err
+		}
+	}
+}
+
+func sendRequest(ctx context.Context, c *http.Client, r *Request) (*Response, error) {
 	req, err := http.NewRequestWithContext(ctx, r.Method, r.URL, strings.NewReader(r.Body))
 	if err != nil {
 		return nil, err
@@ -222,100 +312,18 @@ func RequestWebPage(ctx context.Context, r *Request) (*Response, error) {
 	for k, v := range r.Header {
 		req.Header.Set(k, v)
 	}
-
-	resp, err := DefaultClient.Do(req)
+	// Limit the number of concurrent requests sent to the host by all data sources
+	release, err := currentHostLimiter().acquire(ctx, req.URL.Hostname())
 	if err != nil {
 		return nil, err
 	}
-	return RespToAmassResponse(resp), nil
-}
+	defer release()
 
-// Crawl will spider the web page at the URL argument looking while staying within the scope provided.
-func Crawl(ctx context.Context, u string, scope []string, max int, callback func(*Request, *Response)) error {
-	select {
-	case <-ctx.Done():
-		return fmt.Errorf("the context expired")
-	default:
+	resp, err := c.Do(req)
+	if err != nil {
+		return nil, err
 	}
-
-	var count int
-	var m sync.Mutex
-	filter := bf.NewDefaultStableBloomFilter(10000, 0.01)
-	defer filter.Reset()
-	attrs := []string{"action", "cite", "data", "formaction",
-		"href", "longdesc", "poster", "src", "srcset", "xmlns"}
-	tags := []string{"a", "area", "audio", "base", "blockquote", "button",
-		"embed", "form", "frame", "frameset", "html", "iframe", "img", "input",
-		"ins", "link", "noframes", "object", "q", "script", "source", "track", "video"}
-
-	g := geziyor.NewGeziyor(&geziyor.Options{
-		StartURLs:             []string{u},
-		RobotsTxtDisabled:     true,
-		UserAgent:             UserAgent,
-		LogDisabled:           true,
-		ConcurrentRequests:    5,
-		RequestDelay:          time.Second,
-		RequestDelayRandomize: true,
-		ParseFunc: func(g *geziyor.Geziyor, r *client.Response) {
-			select {
-			case <-ctx.Done():
-				return
-			default:
-			}
-
-			process := func(n string) {
-				u, err := r.Request.URL.Parse(n)
-				if err != nil {
-					return
-				}
-				if host := u.Hostname(); host == "" || whichDomain(host, scope) == "" {
-					return
-				}
-
-				m.Lock()
-				if s := u.String(); s != "" && !filter.Test([]byte(s)) {
-					count++
-					// Be sure the crawl has not exceeded the maximum links to be followed
-					if max <= 0 || count < max {
-						filter.Add([]byte(s))
-						g.Get(s, g.Opt.ParseFunc)
-					}
-				}
-				m.Unlock()
-			}
-			tag := func(i int, s *goquery.Selection) {
-				for _, attr := range attrs {
-					if name, ok := s.Attr(attr); ok {
-						process(name)
-					}
-				}
-			}
-			for _, t := range tags {
-				r.HTMLDoc.Find(t).Each(tag)
-			}
-
-			callback(ReqToAmassRequest(r.Request.Request), &Response{
-				Status:     r.Status,
-				StatusCode: r.StatusCode,
-				Proto:      r.Proto,
-				ProtoMajor: r.ProtoMajor,
-				ProtoMinor: r.ProtoMinor,
-				Header:     HdrToAmassHeader(r.Header),
-				Body:       string(r.Body),
-				Length:     r.ContentLength,
-				TLS:        r.TLS,
-			})
-		},
-	})
-	g.Client = client.NewClient(&client.Options{
-		MaxBodySize:    50 * 1024 * 1024, // 50MB
-		RetryTimes:     2,
-		RetryHTTPCodes: []int{408, 500, 502, 503, 504, 522, 524},
-	})
-	g.Client.Client = DefaultClient
-
-	g.Start()
-	return nil
+	return RespToAmassResponse(resp), nil
 }
 
 func whichDomain(name string, scope []string) string {
@@ -334,30 +342,84 @@ func whichDomain(name string, scope []string) string {
 	return ""
 }
 
+// CertificateInfo contains the details of a certificate served on a port of an IP address.
+type CertificateInfo struct {
+	Port         int
+	Fingerprint  string
+	Organization []string
+	Issuer       string
+	Names        []string
+}
+
 // PullCertificateNames attempts to pull a cert from one or more ports on an IP.
 func PullCertificateNames(ctx context.Context, addr string, ports []int) []string {
 	var names []string
+
+	for _, cert := range PullCertificates(ctx, addr, ports) {
+		names = append(names, cert.Names...)
+	}
+	return names
+}
+
+// PullCertificates attempts to pull a cert from one or more ports on an IP and returns the
+// details of each certificate obtained.
+func PullCertificates(ctx context.Context, addr string, ports []int) []*CertificateInfo {
+	var certs []*CertificateInfo
 	// check hosts for certificates that contain subdomain names
 	for _, port := range ports {
 		if c, err := TLSConn(ctx, addr, port); err == nil {
 			// get the correct certificate in the chain
 			certChain := c.ConnectionState().PeerCertificates
-			// create the new requests from names found within the cert
-			names = append(names, NamesFromCert(certChain[0])...)
+			if len(certChain) > 0 {
+				info := CertificateDetails(certChain[0])
+
+				info.Port = port
+				certs = append(certs, info)
+			}
 			c.Close()
 		}
 
 		select {
 		case <-ctx.Done():
-			return names
+			return certs
 		default:
 		}
 	}
-	return names
+	return certs
+}
+
+// CertificateDetails returns the fingerprint, subject organization, issuer and names of the certificate.
+func CertificateDetails(cert *x509.Certificate) *CertificateInfo {
+	sum := sha256.Sum256(cert.Raw)
+
+	issuer := cert.Issuer.CommonName
+	if issuer == "" && len(cert.Issuer.Organization) > 0 {
+		issuer = cert.Issuer.Organization[0]
+	}
+
+	var orgs []string
+	for _, org := range cert.Subject.Organization {
+		if o := strings.TrimSpace(org); o != "" {
+			orgs = append(orgs, o)
+		}
+	}
+
+	return &CertificateInfo{
+		Fingerprint:  hex.EncodeToString(sum[:]),
+		Organization: orgs,
+		Issuer:       strings.TrimSpace(issuer),
+		Names:        NamesFromCert(cert),
+	}
 }
 
 // TLSConn attempts to make a TLS connection with the host on the given port.
 func TLSConn(ctx context.Context, host string, port int) (*tls.Conn, error) {
+	return TLSConnWithServerName(ctx, host, port, "")
+}
+
+// TLSConnWithServerName attempts to make a TLS connection with the host on the given port,
+// sending the server name provided in the SNI extension when it is not empty.
+func TLSConnWithServerName(ctx context.Context, host string, port int, sni string) (*tls.Conn, error) {
 	// set the maximum time allowed for making the connection
 	tCtx, cancel := context.WithTimeout(ctx, handshakeTimeout)
 	defer cancel()
@@ -367,7 +429,10 @@ func TLSConn(ctx context.Context, host string, port int) (*tls.Conn, error) {
 		return nil, err
 	}
 
-	c := tls.Client(conn, &tls.Config{InsecureSkipVerify: true})
+	c := tls.Client(conn, &tls.Config{
+		ServerName:         sni,
+		InsecureSkipVerify: true,
+	})
 	// attempt to acquire the certificate chain
 	if err := c.HandshakeContext(tCtx); err != nil {
 		c.Close()
@@ -13,7 +13,9 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"regexp"
+	"strconv"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -147,6 +149,38 @@ func TestRequestWebPage(t *testing.T) {
 	}
 }
 
+func TestRequestWebPageProxy(t *testing.T) {
+	var proxied string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Proxy-Authorization") == "" {
+			w.WriteHeader(http.StatusProxyAuthRequired)
+			return
+		}
+		proxied = r.URL.String()
+		fmt.Fprint(w, "Proxied")
+	}))
+	defer ts.Close()
+
+	u, _ := url.Parse(ts.URL)
+	u.User = url.UserPassword("caffix", "OWASP")
+
+	target := "http://www.owasp.org/"
+	resp, err := RequestWebPage(context.TODO(), &Request{
+		URL:   target,
+		Proxy: u.String(),
+	})
+	if err != nil || resp.StatusCode != 200 || resp.Body != "Proxied" || proxied != target {
+		t.Errorf("The request was not sent through the proxy: %v", err)
+	}
+
+	if _, err := RequestWebPage(context.TODO(), &Request{
+		URL:   target,
+		Proxy: "ftp://" + u.Host,
+	}); err == nil {
+		t.Errorf("Failed to detect the unsupported proxy")
+	}
+}
+
 func TestCrawl(t *testing.T) {
 	re, err := regexp.Compile(amassdns.AnySubdomainRegexString())
 	if err != nil {
@@ -260,6 +294,30 @@ func TestPullCertificateNames(t *testing.T) {
 	}
 }
 
+func TestPullCertificates(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	host, p, _ := net.SplitHostPort(ts.Listener.Addr().String())
+	port, _ := strconv.Atoi(p)
+
+	certs := PullCertificates(context.Background(), host, []int{port})
+	if len(certs) != 1 {
+		t.Fatalf("Obtained %d certificates from the test server", len(certs))
+	}
+
+	cert := certs[0]
+	if cert.Port != port || len(cert.Fingerprint) != 64 {
+		t.Errorf("Failed to identify the certificate: %+v", cert)
+	}
+	if len(cert.Organization) != 1 || cert.Organization[0] != "Acme Co" || cert.Issuer != "Acme Co" {
+		t.Errorf("Failed to obtain the organization and issuer: %v, %s", cert.Organization, cert.Issuer)
+	}
+	if !stringset.New(cert.Names...).Has("example.com") {
+		t.Errorf("Failed to obtain the names from the certificate: %v", cert.Names)
+	}
+}
+
 func TestCleanName(t *testing.T) {
 	tests := []struct {
 		data string
@@ -293,3 +351,91 @@ func TestCleanName(t *testing.T) {
 		}
 	}
 }
+
+func TestCrawlWithOptions(t *testing.T) {
+	pages := map[string]string{
+		"/":              `<html><body><a href="/page.html">Page</a><script src="/app.js"></script><script>var u = "/inline/path";</script></body></html>`,
+		"/robots.txt":    "User-agent: *\nDisallow: /private/\nDisallow: /*.php$\nSitemap: /sitemap.xml",
+		"/sitemap.xml":   `<?xml version="1.0"?><urlset><url><loc>/mapped.html</loc></url></urlset>`,
+		"/app.js":        "fetch(\"/api/v1/names\");\n//# sourceMappingURL=app.js.map",
+		"/app.js.map":    `{"version":3,"sources":["src/app.ts"],"sourcesContent":["const api = '/api/v2/source';"]}`,
+		"/page.html":     "<html><body>Page</body></html>",
+		"/mapped.html":   "<html><body>Mapped</body></html>",
+		"/private/":      "Private",
+		"/inline/path":   "Inline",
+		"/api/v1/names":  "Names",
+		"/api/v2/source": "Source",
+	}
+
+	var lock sync.Mutex
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		content, found := pages[r.URL.Path]
+		if !found {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		switch {
+		case strings.HasSuffix(r.URL.Path, ".js"):
+			w.Header().Set("Content-Type", "application/javascript")
+		case strings.HasSuffix(r.URL.Path, ".map"):
+			w.Header().Set("Content-Type", "application/json")
+		case strings.HasSuffix(r.URL.Path, ".xml"):
+			w.Header().Set("Content-Type", "application/xml")
+		case strings.HasSuffix(r.URL.Path, ".txt"):
+			w.Header().Set("Content-Type", "text/plain")
+		default:
+			w.Header().Set("Content-Type", "text/html")
+		}
+		fmt.Fprint(w, content)
+	}))
+	defer ts.Close()
+
+	crawl := func(opts *CrawlOptions) *stringset.Set {
+		got := stringset.New()
+
+		_ = CrawlWithOptions(context.Background(), ts.URL+"/", opts, func(req *Request, resp *Response) {
+			if u, err := url.Parse(req.URL); err == nil {
+				lock.Lock()
+				got.Insert(u.Path)
+				lock.Unlock()
+			}
+		})
+		return got
+	}
+
+	got := crawl(&CrawlOptions{Scope: []string{"127.0.0.1"}})
+	for path := range pages {
+		if !got.Has(path) {
+			t.Errorf("The crawl failed to request %s", path)
+		}
+	}
+	got.Close()
+
+	got = crawl(&CrawlOptions{Scope: []string{"127.0.0.1"}, MaxDepth: 1})
+	if !got.Has("/app.js") || got.Has("/api/v1/names") || got.Has("/mapped.html") {
+		t.Errorf("The crawl did not respect the maximum depth: %v", got.Slice())
+	}
+	got.Close()
+
+	// Only the starting URL, robots.txt and sitemap.xml are requested before the budget is exhausted
+	got = crawl(&CrawlOptions{Scope: []string{"127.0.0.1"}, MaxBytes: 10})
+	defer got.Close()
+	if got.Len() > 3 || got.Has("/page.html") || got.Has("/mapped.html") {
+		t.Errorf("The crawl did not respect the maximum number of bytes: %v", got.Slice())
+	}
+}
+
+func TestHeaderHosts(t *testing.T) {
+	csp := "default-src 'self' https://*.owasp.org; script-src cdn.example.com 'sha256-abc='; report-uri /csp"
+	got := stringset.New(headerHosts(csp)...)
+	defer got.Close()
+
+	want := stringset.New("owasp.org", "cdn.example.com")
+	defer want.Close()
+
+	want.Subtract(got)
+	if want.Len() != 0 || got.Len() != 2 {
+		t.Errorf("Failed to extract the hosts from the header: %v", got.Slice())
+	}
+}
@@ -0,0 +1,173 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package http
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultRetries is the default number of times a failed request is retried.
+	DefaultRetries = 2
+	// DefaultRetryBackoff is the default delay before the first retry of a failed request.
+	DefaultRetryBackoff = time.Second
+	// DefaultHostConcurrency is the default number of concurrent requests sent to a single host.
+	DefaultHostConcurrency = 10
+	// The longest delay between attempts, including delays requested using Retry-After
+	maxRetryBackoff = 2 * time.Minute
+)
+
+var (
+	retryLock    sync.Mutex
+	retries      = DefaultRetries
+	retryBackoff = DefaultRetryBackoff
+	hostLimiter  = newHostLimiter(DefaultHostConcurrency)
+)
+
+// SetRetryPolicy sets the number of times RequestWebPage retries requests that fail with
+// network errors, 429 or 5xx responses, and the delay before the first retry. The delay
+// doubles with each attempt and is randomized to avoid synchronized retries.
+func SetRetryPolicy(max int, backoff time.Duration) {
+	retryLock.Lock()
+	defer retryLock.Unlock()
+
+	if max < 0 {
+		max = 0
+	}
+	if backoff <= 0 {
+		backoff = DefaultRetryBackoff
+	}
+
+	retries = max
+	retryBackoff = backoff
+}
+
+func retryPolicy() (int, time.Duration) {
+	retryLock.Lock()
+	defer retryLock.Unlock()
+
+	return retries, retryBackoff
+}
+
+// SetHostConcurrency sets the maximum number of concurrent requests that RequestWebPage
+// sends to a single host, shared by all the data sources. A value of zero removes the limit.
+func SetHostConcurrency(max int) {
+	retryLock.Lock()
+	defer retryLock.Unlock()
+
+	hostLimiter = newHostLimiter(max)
+}
+
+func currentHostLimiter() *limiter {
+	retryLock.Lock()
+	defer retryLock.Unlock()
+
+	return hostLimiter
+}
+
+func retryStatus(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout, 522, 524:
+		return true
+	}
+	return false
+}
+
+// Returns the delay before the next attempt, using the Retry-After header when provided.
+func retryDelay(attempt int, backoff time.Duration, retryAfter string) time.Duration {
+	if d, ok := parseRetryAfter(retryAfter); ok {
+		if d > maxRetryBackoff {
+			d = maxRetryBackoff
+		}
+		return d
+	}
+
+	d := backoff << uint(attempt)
+	if d <= 0 || d > maxRetryBackoff {
+		d = maxRetryBackoff
+	}
+	// Select a random delay between half and the full backoff
+	half := d / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+func parseRetryAfter(value string) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+
+	if secs, err := strconv.Atoi(value); err == nil {
+		if secs < 0 {
+			secs = 0
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		d := time.Until(t)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return errors.New("the context expired")
+	case <-t.C:
+	}
+	return nil
+}
+
+// limiter restricts the number of concurrent requests sent to each host.
+type limiter struct {
+	sync.Mutex
+	max   int
+	hosts map[string]chan struct{}
+}
+
+func newHostLimiter(max int) *limiter {
+	return &limiter{
+		max:   max,
+		hosts: make(map[string]chan struct{}),
+	}
+}
+
+// Blocks until the request can be sent to the host and returns the function that releases the slot.
+func (l *limiter) acquire(ctx context.Context, host string) (func(), error) {
+	if l.max <= 0 {
+		return func() {}, nil
+	}
+
+	host = strings.ToLower(host)
+	l.Lock()
+	sem, found := l.hosts[host]
+	if !found {
+		sem = make(chan struct{}, l.max)
+		l.hosts[host] = sem
+	}
+	l.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return nil, errors.New("the context expired")
+	case sem <- struct{}{}:
+	}
+	return func() { <-sem }, nil
+}
@@ -0,0 +1,107 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRequestWebPageRetry(t *testing.T) {
+	SetRetryPolicy(2, 10*time.Millisecond)
+	defer SetRetryPolicy(DefaultRetries, DefaultRetryBackoff)
+
+	var attempts int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch atomic.AddInt32(&attempts, 1) {
+		case 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case 2:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			fmt.Fprint(w, "Success")
+		}
+	}))
+	defer ts.Close()
+
+	resp, err := RequestWebPage(context.TODO(), &Request{URL: ts.URL})
+	if err != nil || resp.StatusCode != 200 || resp.Body != "Success" {
+		t.Errorf("Failed to retry the request: %v", err)
+	}
+	if a := atomic.LoadInt32(&attempts); a != 3 {
+		t.Errorf("The request was sent %d times instead of 3", a)
+	}
+
+	atomic.StoreInt32(&attempts, 0)
+	SetRetryPolicy(0, 10*time.Millisecond)
+	resp, err = RequestWebPage(context.TODO(), &Request{URL: ts.URL})
+	if err != nil || resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("The request was retried after disabling the retries")
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	if d := retryDelay(0, time.Second, "5"); d != 5*time.Second {
+		t.Errorf("Failed to honour the Retry-After seconds: %v", d)
+	}
+	if d := retryDelay(0, time.Second, "86400"); d != maxRetryBackoff {
+		t.Errorf("Failed to limit the Retry-After delay: %v", d)
+	}
+
+	date := time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)
+	if d := retryDelay(0, time.Second, date); d <= 0 || d > maxRetryBackoff {
+		t.Errorf("Failed to honour the Retry-After date: %v", d)
+	}
+
+	for attempt := 0; attempt < 3; attempt++ {
+		max := time.Second << uint(attempt)
+
+		if d := retryDelay(attempt, time.Second, ""); d < max/2 || d > max {
+			t.Errorf("The delay %v for attempt %d is outside the backoff range", d, attempt)
+		}
+	}
+}
+
+func TestHostConcurrency(t *testing.T) {
+	SetHostConcurrency(2)
+	defer SetHostConcurrency(DefaultHostConcurrency)
+
+	var current, highest int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&current, 1)
+		defer atomic.AddInt32(&current, -1)
+
+		for {
+			h := atomic.LoadInt32(&highest)
+			if n <= h || atomic.CompareAndSwapInt32(&highest, h, n) {
+				break
+			}
+		}
+		time.Sleep(50 * time.Millisecond)
+		fmt.Fprint(w, "Success")
+	}))
+	defer ts.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = RequestWebPage(context.TODO(), &Request{URL: ts.URL})
+		}()
+	}
+	wg.Wait()
+
+	if h := atomic.LoadInt32(&highest); h > 2 {
+		t.Errorf("The host received %d concurrent requests instead of 2", h)
+	}
+}
@@ -0,0 +1,214 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package net
+
+import (
+	"math/big"
+	"net"
+	"strings"
+)
+
+const hexDigits = "0123456789abcdef"
+
+// RangeCIDRs returns the fewest CIDRs that cover the IPv4 or IPv6 address range, including both addresses.
+func RangeCIDRs(first, last net.IP) []*net.IPNet {
+	bits := 32
+	f, l := first.To4(), last.To4()
+	if f == nil || l == nil {
+		if f != nil || l != nil {
+			// The addresses belong to different families
+			return nil
+		}
+
+		bits = 128
+		f, l = first.To16(), last.To16()
+		if f == nil || l == nil {
+			return nil
+		}
+	}
+
+	start := new(big.Int).SetBytes(f)
+	end := new(big.Int).SetBytes(l)
+	one := big.NewInt(1)
+
+	var cidrs []*net.IPNet
+	for start.Cmp(end) <= 0 {
+		// Find the largest block that is aligned on the start address and ends within the range
+		host := int(start.TrailingZeroBits())
+		if start.Sign() == 0 || host > bits {
+			host = bits
+		}
+		for ; host > 0; host-- {
+			size := new(big.Int).Lsh(one, uint(host))
+			if blockEnd := size.Add(size, start).Sub(size, one); blockEnd.Cmp(end) <= 0 {
+				break
+			}
+		}
+
+		cidrs = append(cidrs, &net.IPNet{
+			IP:   intToIP(start, bits),
+			Mask: net.CIDRMask(bits-host, bits),
+		})
+		start = new(big.Int).Add(start, new(big.Int).Lsh(one, uint(host)))
+	}
+	return cidrs
+}
+
+// IPv6Subnets returns the first num subnets, of the provided prefix length, within the IPv6 netblock.
+// The netblock is returned when it is already smaller than the subnets.
+func IPv6Subnets(cidr *net.IPNet, prefix, num int) []*net.IPNet {
+	ones, bits := cidr.Mask.Size()
+	if bits != 128 || num <= 0 {
+		return nil
+	}
+	if ones >= prefix {
+		return []*net.IPNet{{IP: cidr.IP.Mask(cidr.Mask), Mask: cidr.Mask}}
+	}
+
+	// Only the subnets within the netblock are returned
+	if avail := new(big.Int).Lsh(big.NewInt(1), uint(prefix-ones)); avail.Cmp(big.NewInt(int64(num))) < 0 {
+		num = int(avail.Int64())
+	}
+
+	first, _ := ipToInt(cidr.IP.Mask(cidr.Mask).To16())
+	step := new(big.Int).Lsh(big.NewInt(1), uint(128-prefix))
+	mask := net.CIDRMask(prefix, 128)
+
+	var subnets []*net.IPNet
+	for i := 0; i < num; i++ {
+		n := new(big.Int).Mul(step, big.NewInt(int64(i)))
+		subnets = append(subnets, &net.IPNet{
+			IP:   intToIP(n.Add(n, first), 128),
+			Mask: mask,
+		})
+	}
+	return subnets
+}
+
+// LowByteHosts returns the addresses with the interface identifiers 1 through num within the IPv6
+// subnet, such as 2001:db8::1, since hosts are commonly numbered from the start of the subnet.
+func LowByteHosts(subnet *net.IPNet, num int) []net.IP {
+	if _, bits := subnet.Mask.Size(); bits != 128 {
+		return nil
+	}
+
+	var ips []net.IP
+	ip := subnet.IP.Mask(subnet.Mask).To16()
+	for i := 0; i < num; i++ {
+		next := make(net.IP, len(ip))
+		copy(next, ip)
+		IPInc(next)
+
+		if !subnet.Contains(next) {
+			break
+		}
+		ips = append(ips, next)
+		ip = next
+	}
+	return ips
+}
+
+// IsEUI64 returns true when the interface identifier of the IPv6 address was derived from a
+// MAC address, which places the ff:fe bytes in the middle of the identifier.
+func IsEUI64(ip net.IP) bool {
+	if ip.To4() != nil {
+		return false
+	}
+
+	ip16 := ip.To16()
+	return ip16 != nil && ip16[11] == 0xff && ip16[12] == 0xfe
+}
+
+// EUI64Neighbors returns up to num addresses around the EUI-64 address, within the same subnet and
+// with the same manufacturer, since devices purchased together often have sequential MAC addresses.
+func EUI64Neighbors(ip net.IP, num int) []net.IP {
+	if !IsEUI64(ip) || num <= 0 {
+		return nil
+	}
+
+	ip16 := ip.To16()
+	// The last three bytes are assigned to the device by the manufacturer
+	nic := int(ip16[13])<<16 | int(ip16[14])<<8 | int(ip16[15])
+
+	var ips []net.IP
+	for delta := 1; len(ips) < num && delta <= num; delta++ {
+		for _, n := range []int{nic - delta, nic + delta} {
+			if n < 0 || n > 0xffffff || len(ips) >= num {
+				continue
+			}
+
+			neighbor := make(net.IP, net.IPv6len)
+			copy(neighbor, ip16)
+			neighbor[13], neighbor[14], neighbor[15] = byte(n>>16), byte(n>>8), byte(n)
+			ips = append(ips, neighbor)
+		}
+	}
+	return ips
+}
+
+// IPv6Nibbles returns the hexadecimal digits of the IPv6 address, from the most significant.
+func IPv6Nibbles(ip net.IP) []byte {
+	ip16 := ip.To16()
+	if ip16 == nil || ip.To4() != nil {
+		return nil
+	}
+
+	nibbles := make([]byte, 0, 32)
+	for _, b := range ip16 {
+		nibbles = append(nibbles, b>>4, b&0xf)
+	}
+	return nibbles
+}
+
+// NibblesToIPv6 returns the IPv6 address for the 32 hexadecimal digits, from the most significant.
+func NibblesToIPv6(nibbles []byte) net.IP {
+	if len(nibbles) != 32 {
+		return nil
+	}
+
+	ip := make(net.IP, net.IPv6len)
+	for i := range ip {
+		ip[i] = nibbles[2*i]<<4 | nibbles[2*i+1]&0xf
+	}
+	return ip
+}
+
+// IPv6ReverseName returns the name in the ip6.arpa tree for the hexadecimal digits, from the most
+// significant, such as 8.b.d.0.1.0.0.2.ip6.arpa for the first eight digits of 2001:db8::/32.
+func IPv6ReverseName(nibbles []byte) string {
+	var b strings.Builder
+
+	for i := len(nibbles) - 1; i >= 0; i-- {
+		b.WriteByte(hexDigits[nibbles[i]&0xf])
+		b.WriteByte('.')
+	}
+	b.WriteString("ip6.arpa")
+	return b.String()
+}
+
+// IPv6NibblePrefixes returns the hexadecimal digit prefixes that cover the IPv6 netblock, since the
+// ip6.arpa tree delegates on the boundaries of the digits. A /30 netblock is covered by four /32 prefixes.
+func IPv6NibblePrefixes(cidr *net.IPNet) [][]byte {
+	ones, bits := cidr.Mask.Size()
+	if bits != 128 {
+		return nil
+	}
+
+	nibbles := IPv6Nibbles(cidr.IP.Mask(cidr.Mask))
+	length := (ones + 3) / 4
+	prefixes := [][]byte{nibbles[:length]}
+	if rem := ones % 4; rem != 0 {
+		prefixes = nil
+		// The last digit of the prefix takes every value allowed by the remaining bits of the mask
+		last := nibbles[length-1]
+		for i := byte(0); i < 1<<(4-rem); i++ {
+			p := make([]byte, length)
+			copy(p, nibbles[:length])
+			p[length-1] = last | i
+			prefixes = append(prefixes, p)
+		}
+	}
+	return prefixes
+}
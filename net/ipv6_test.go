@@ -0,0 +1,149 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package net
+
+import (
+	"net"
+	"testing"
+)
+
+func TestIPv6Subnets(t *testing.T) {
+	tests := []struct {
+		CIDR     string
+		Prefix   int
+		Num      int
+		Expected []string
+	}{
+		{"2001:db8::/48", 64, 3, []string{"2001:db8::/64", "2001:db8:0:1::/64", "2001:db8:0:2::/64"}},
+		{"2001:db8::/63", 64, 4, []string{"2001:db8::/64", "2001:db8:0:1::/64"}},
+		{"2001:db8::/120", 64, 4, []string{"2001:db8::/120"}},
+		{"192.168.1.0/24", 64, 4, nil},
+	}
+
+	for _, test := range tests {
+		_, ipnet, _ := net.ParseCIDR(test.CIDR)
+
+		subnets := IPv6Subnets(ipnet, test.Prefix, test.Num)
+		if len(subnets) != len(test.Expected) {
+			t.Errorf("IPv6Subnets(%s) returned %d subnets, expected %d", test.CIDR, len(subnets), len(test.Expected))
+			continue
+		}
+		for i, subnet := range subnets {
+			if subnet.String() != test.Expected[i] {
+				t.Errorf("IPv6Subnets(%s) returned %s, expected %s", test.CIDR, subnet, test.Expected[i])
+			}
+		}
+	}
+}
+
+func TestLowByteHosts(t *testing.T) {
+	_, ipnet, _ := net.ParseCIDR("2001:db8::/64")
+
+	ips := LowByteHosts(ipnet, 16)
+	if len(ips) != 16 {
+		t.Fatalf("LowByteHosts returned %d addresses, expected 16", len(ips))
+	}
+	if ips[0].String() != "2001:db8::1" || ips[15].String() != "2001:db8::10" {
+		t.Errorf("LowByteHosts returned the addresses %s through %s", ips[0], ips[15])
+	}
+
+	_, ipnet, _ = net.ParseCIDR("2001:db8::/126")
+	if ips := LowByteHosts(ipnet, 16); len(ips) != 3 {
+		t.Errorf("LowByteHosts returned %d addresses outside of the subnet", len(ips)-3)
+	}
+}
+
+func TestEUI64Neighbors(t *testing.T) {
+	ip := net.ParseIP("2001:db8::211:22ff:fe33:4455")
+	if !IsEUI64(ip) || IsEUI64(net.ParseIP("2001:db8::1")) || IsEUI64(net.ParseIP("192.168.1.1")) {
+		t.Errorf("IsEUI64 did not identify the EUI-64 address")
+	}
+
+	expected := []string{
+		"2001:db8::211:22ff:fe33:4454",
+		"2001:db8::211:22ff:fe33:4456",
+		"2001:db8::211:22ff:fe33:4453",
+		"2001:db8::211:22ff:fe33:4457",
+	}
+
+	ips := EUI64Neighbors(ip, 4)
+	if len(ips) != len(expected) {
+		t.Fatalf("EUI64Neighbors returned %d addresses, expected %d", len(ips), len(expected))
+	}
+	for i, addr := range ips {
+		if addr.String() != expected[i] {
+			t.Errorf("EUI64Neighbors returned %s, expected %s", addr, expected[i])
+		}
+	}
+}
+
+func TestIPv6ReverseName(t *testing.T) {
+	ip := net.ParseIP("2001:db8::567:89ab")
+
+	nibbles := IPv6Nibbles(ip)
+	if len(nibbles) != 32 {
+		t.Fatalf("IPv6Nibbles returned %d digits", len(nibbles))
+	}
+	if name := IPv6ReverseName(nibbles); name != "b.a.9.8.7.6.5.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.8.b.d.0.1.0.0.2.ip6.arpa" {
+		t.Errorf("IPv6ReverseName returned %s", name)
+	}
+	if back := NibblesToIPv6(nibbles); !back.Equal(ip) {
+		t.Errorf("NibblesToIPv6 returned %s, expected %s", back, ip)
+	}
+}
+
+func TestIPv6NibblePrefixes(t *testing.T) {
+	tests := []struct {
+		CIDR     string
+		Expected []string
+	}{
+		{"2001:db8::/32", []string{"8.b.d.0.1.0.0.2.ip6.arpa"}},
+		{"2001:db8::/31", []string{"8.b.d.0.1.0.0.2.ip6.arpa", "9.b.d.0.1.0.0.2.ip6.arpa"}},
+		{"2001:db8:ab00::/39", []string{"a.a.8.b.d.0.1.0.0.2.ip6.arpa", "b.a.8.b.d.0.1.0.0.2.ip6.arpa"}},
+	}
+
+	for _, test := range tests {
+		_, ipnet, _ := net.ParseCIDR(test.CIDR)
+
+		prefixes := IPv6NibblePrefixes(ipnet)
+		if len(prefixes) != len(test.Expected) {
+			t.Errorf("IPv6NibblePrefixes(%s) returned %d prefixes, expected %d", test.CIDR, len(prefixes), len(test.Expected))
+			continue
+		}
+		for i, p := range prefixes {
+			if name := IPv6ReverseName(p); name != test.Expected[i] {
+				t.Errorf("IPv6NibblePrefixes(%s) returned %s, expected %s", test.CIDR, name, test.Expected[i])
+			}
+		}
+	}
+}
+
+func TestRangeCIDRs(t *testing.T) {
+	tests := []struct {
+		First    string
+		Last     string
+		Expected []string
+	}{
+		{"192.168.1.0", "192.168.1.255", []string{"192.168.1.0/24"}},
+		{"192.168.1.1", "192.168.1.6", []string{"192.168.1.1/32", "192.168.1.2/31", "192.168.1.4/31", "192.168.1.6/32"}},
+		{"2001:db8::", "2001:db8::1:ffff", []string{"2001:db8::/111"}},
+		{"2001:db8::ff", "2001:db8::100", []string{"2001:db8::ff/128", "2001:db8::100/128"}},
+		{"192.168.1.6", "192.168.1.1", nil},
+		{"192.168.1.1", "2001:db8::1", nil},
+	}
+
+	for _, test := range tests {
+		cidrs := RangeCIDRs(net.ParseIP(test.First), net.ParseIP(test.Last))
+		if len(cidrs) != len(test.Expected) {
+			t.Errorf("RangeCIDRs(%s, %s) returned %v, expected %v", test.First, test.Last, cidrs, test.Expected)
+			continue
+		}
+		for i, cidr := range cidrs {
+			if cidr.String() != test.Expected[i] {
+				t.Errorf("RangeCIDRs(%s, %s) returned %s, expected %s", test.First, test.Last, cidr, test.Expected[i])
+			}
+		}
+	}
+}
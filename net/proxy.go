@@ -0,0 +1,167 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package net
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/net/proxy"
+)
+
+// DialFunc is the signature shared by the dial functions in this package.
+type DialFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// ParseProxy checks that the proxy URL is supported (http, https, socks5 or socks5h) and returns it parsed.
+func ParseProxy(p string) (*url.URL, error) {
+	u, err := url.Parse(strings.TrimSpace(p))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the proxy URL: %v", err)
+	}
+
+	switch strings.ToLower(u.Scheme) {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("the proxy URL scheme %q is not supported", u.Scheme)
+	}
+	if u.Hostname() == "" {
+		return nil, fmt.Errorf("the proxy URL %s does not provide a host", u.Redacted())
+	}
+	return u, nil
+}
+
+// ProxyDialContext returns a dial function that establishes connections through the proxy.
+// HTTP proxies are accessed using the CONNECT method and only support TCP connections.
+// An empty proxy URL returns DialContext.
+func ProxyDialContext(p string) (DialFunc, error) {
+	if p == "" {
+		return DialContext, nil
+	}
+
+	u, err := ParseProxy(p)
+	if err != nil {
+		return nil, err
+	}
+
+	if scheme := strings.ToLower(u.Scheme); scheme == "socks5" || scheme == "socks5h" {
+		var auth *proxy.Auth
+		if u.User != nil {
+			pass, _ := u.User.Password()
+			auth = &proxy.Auth{
+				User:     u.User.Username(),
+				Password: pass,
+			}
+		}
+
+		d, err := proxy.SOCKS5("tcp", proxyHostPort(u), auth, forwardDialer{})
+		if err != nil {
+			return nil, err
+		}
+		return d.(proxy.ContextDialer).DialContext, nil
+	}
+
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return httpConnect(ctx, u, network, addr)
+	}, nil
+}
+
+// Makes the proxy dialer use DialContext to reach the proxy server.
+type forwardDialer struct{}
+
+func (forwardDialer) Dial(network, addr string) (net.Conn, error) {
+	return DialContext(context.Background(), network, addr)
+}
+
+func (forwardDialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	return DialContext(ctx, network, addr)
+}
+
+func proxyHostPort(u *url.URL) string {
+	if port := u.Port(); port != "" {
+		return net.JoinHostPort(u.Hostname(), port)
+	}
+
+	port := "1080"
+	switch strings.ToLower(u.Scheme) {
+	case "http":
+		port = "80"
+	case "https":
+		port = "443"
+	}
+	return net.JoinHostPort(u.Hostname(), port)
+}
+
+func httpConnect(ctx context.Context, u *url.URL, network, addr string) (net.Conn, error) {
+	if !strings.HasPrefix(network, "tcp") {
+		return nil, fmt.Errorf("the HTTP proxy %s cannot be used for %s connections", u.Redacted(), network)
+	}
+
+	conn, err := DialContext(ctx, "tcp", proxyHostPort(u))
+	if err != nil {
+		return nil, err
+	}
+	if strings.ToLower(u.Scheme) == "https" {
+		tlsConn := tls.Client(conn, &tls.Config{ServerName: u.Hostname()})
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		conn = tlsConn
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+		defer func() { _ = conn.SetDeadline(time.Time{}) }()
+	}
+
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: addr},
+		Host:   addr,
+		Header: make(http.Header),
+	}
+	if u.User != nil {
+		pass, _ := u.User.Password()
+		creds := base64.StdEncoding.EncodeToString([]byte(u.User.Username() + ":" + pass))
+		req.Header.Set("Proxy-Authorization", "Basic "+creds)
+	}
+	if err := req.Write(conn); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, req)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		conn.Close()
+		return nil, fmt.Errorf("the proxy %s refused the connection to %s: %s", u.Redacted(), addr, resp.Status)
+	}
+	// Keep any data the server sent right after the proxy response
+	if br.Buffered() > 0 {
+		return &bufferedConn{Conn: conn, r: br}, nil
+	}
+	return conn, nil
+}
+
+type bufferedConn struct {
+	net.Conn
+	r *bufio.Reader
+}
+
+func (c *bufferedConn) Read(b []byte) (int, error) {
+	return c.r.Read(b)
+}
@@ -0,0 +1,232 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package whois
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/OWASP/Amass/v3/net/http"
+)
+
+// Bootstrap maps domain name suffixes to RDAP service URLs using the IANA registry
+// format described in RFC 9224 (https://data.iana.org/rdap/dns.json).
+type Bootstrap struct {
+	services map[string][]string
+}
+
+type bootstrapFile struct {
+	Services [][][]string `json:"services"`
+}
+
+type rdapDomain struct {
+	LDHName     string       `json:"ldhName"`
+	Entities    []rdapEntity `json:"entities"`
+	Nameservers []struct {
+		LDHName string `json:"ldhName"`
+	} `json:"nameservers"`
+}
+
+type rdapEntity struct {
+	Roles      []string        `json:"roles"`
+	VCardArray json.RawMessage `json:"vcardArray"`
+	Entities   []rdapEntity    `json:"entities"`
+}
+
+// LoadBootstrap reads the IANA RDAP bootstrap registry file at the provided path.
+func LoadBootstrap(path string) (*Bootstrap, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the RDAP bootstrap file %s: %v", path, err)
+	}
+	defer f.Close()
+
+	return ParseBootstrap(f)
+}
+
+// ParseBootstrap reads the IANA RDAP bootstrap registry data provided by the reader.
+func ParseBootstrap(r io.Reader) (*Bootstrap, error) {
+	var bf bootstrapFile
+
+	if err := json.NewDecoder(r).Decode(&bf); err != nil {
+		return nil, fmt.Errorf("failed to decode the RDAP bootstrap data: %v", err)
+	}
+
+	b := &Bootstrap{services: make(map[string][]string)}
+	for _, svc := range bf.Services {
+		if len(svc) != 2 || len(svc[1]) == 0 {
+			continue
+		}
+		for _, suffix := range svc[0] {
+			key := strings.ToLower(strings.Trim(suffix, "."))
+
+			b.services[key] = append(b.services[key], svc[1]...)
+		}
+	}
+
+	if len(b.services) == 0 {
+		return nil, errors.New("the RDAP bootstrap data did not contain any services")
+	}
+	return b, nil
+}
+
+// ServiceURLs returns the RDAP base URLs for the longest suffix of the domain name in the registry.
+func (b *Bootstrap) ServiceURLs(domain string) []string {
+	labels := strings.Split(strings.ToLower(strings.Trim(domain, ".")), ".")
+
+	for i := range labels {
+		if urls, found := b.services[strings.Join(labels[i:], ".")]; found {
+			return urls
+		}
+	}
+	return nil
+}
+
+// Lookup performs an RDAP domain query for the domain name using the bootstrap registry.
+func (b *Bootstrap) Lookup(ctx context.Context, domain string) (*Record, error) {
+	domain = strings.ToLower(strings.TrimSpace(domain))
+
+	urls := b.ServiceURLs(domain)
+	if len(urls) == 0 {
+		return nil, fmt.Errorf("no RDAP service was found for %s", domain)
+	}
+
+	var err error
+	for _, base := range urls {
+		var rec *Record
+
+		if rec, err = rdapQuery(ctx, base, domain); err == nil {
+			return rec, nil
+		}
+	}
+	return nil, err
+}
+
+func rdapQuery(ctx context.Context, base, domain string) (*Record, error) {
+	u := strings.TrimSuffix(base, "/") + "/domain/" + domain
+
+	resp, err := http.RequestWebPage(ctx, &http.Request{
+		URL:    u,
+		Header: http.Header{"Accept": "application/rdap+json"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("the RDAP query to %s returned with status: %s", u, resp.Status)
+	}
+
+	rec, err := ParseRDAP(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	rec.Server = base
+	if rec.Domain == "" {
+		rec.Domain = domain
+	}
+	return rec, nil
+}
+
+// ParseRDAP extracts the registration details from an RDAP domain response.
+func ParseRDAP(data string) (*Record, error) {
+	var d rdapDomain
+
+	if err := json.Unmarshal([]byte(data), &d); err != nil {
+		return nil, fmt.Errorf("failed to decode the RDAP response: %v", err)
+	}
+
+	rec := &Record{
+		Domain: strings.ToLower(d.LDHName),
+		Raw:    data,
+	}
+	for _, ns := range d.Nameservers {
+		if n := strings.ToLower(strings.TrimSuffix(ns.LDHName, ".")); n != "" {
+			rec.NameServers = appendUnique(rec.NameServers, n)
+		}
+	}
+
+	for _, entity := range flattenEntities(d.Entities) {
+		fn, org, emails := parseVCard(entity.VCardArray)
+
+		switch {
+		case hasRole(entity.Roles, "registrant"):
+			if org == "" {
+				org = fn
+			}
+			if rec.Company == "" {
+				rec.Company = org
+			}
+			rec.Emails = appendUnique(rec.Emails, emails...)
+		case hasRole(entity.Roles, "registrar"):
+			if rec.Registrar == "" {
+				rec.Registrar = fn
+			}
+		}
+	}
+	return rec, nil
+}
+
+func flattenEntities(entities []rdapEntity) []rdapEntity {
+	var results []rdapEntity
+
+	for _, e := range entities {
+		results = append(results, e)
+		results = append(results, flattenEntities(e.Entities)...)
+	}
+	return results
+}
+
+func hasRole(roles []string, role string) bool {
+	for _, r := range roles {
+		if strings.EqualFold(r, role) {
+			return true
+		}
+	}
+	return false
+}
+
+// Extracts the formatted name, organization and email addresses from a jCard (RFC 7095).
+func parseVCard(raw json.RawMessage) (string, string, []string) {
+	var card []interface{}
+	if err := json.Unmarshal(raw, &card); err != nil || len(card) != 2 {
+		return "", "", nil
+	}
+
+	props, ok := card[1].([]interface{})
+	if !ok {
+		return "", "", nil
+	}
+
+	var fn, org string
+	var emails []string
+	for _, p := range props {
+		prop, ok := p.([]interface{})
+		if !ok || len(prop) < 4 {
+			continue
+		}
+
+		name, _ := prop[0].(string)
+		value, _ := prop[3].(string)
+		if value == "" || strings.Contains(strings.ToLower(value), "redacted") {
+			continue
+		}
+
+		switch strings.ToLower(name) {
+		case "fn":
+			fn = value
+		case "org":
+			org = value
+		case "email":
+			emails = append(emails, value)
+		}
+	}
+	return fn, org, emails
+}
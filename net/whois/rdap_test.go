@@ -0,0 +1,101 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package whois
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const rdapResponse = `{
+	"objectClassName": "domain",
+	"ldhName": "OWASP.ORG",
+	"entities": [
+		{
+			"roles": ["registrar"],
+			"vcardArray": ["vcard", [["version", {}, "text", "4.0"], ["fn", {}, "text", "Example Registrar, Inc."]]],
+			"entities": [
+				{
+					"roles": ["abuse"],
+					"vcardArray": ["vcard", [["email", {}, "text", "abuse@registrar.example"]]]
+				}
+			]
+		},
+		{
+			"roles": ["registrant"],
+			"vcardArray": ["vcard", [
+				["version", {}, "text", "4.0"],
+				["fn", {}, "text", "REDACTED FOR PRIVACY"],
+				["org", {}, "text", "OWASP Foundation"],
+				["email", {}, "text", "admin@owasp.org"]
+			]]
+		}
+	],
+	"nameservers": [
+		{"objectClassName": "nameserver", "ldhName": "NS1.OWASP.ORG"}
+	]
+}`
+
+func TestBootstrapLookup(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rdap/domain/owasp.org" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/rdap+json")
+		fmt.Fprint(w, rdapResponse)
+	}))
+	defer ts.Close()
+
+	path := filepath.Join(t.TempDir(), "dns.json")
+	data := `{"version": "1.0", "services": [[["org", "ngo"], ["` + ts.URL + `/rdap/"]], [["com"], ["https://rdap.example.com/"]]]}`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := LoadBootstrap(path)
+	if err != nil {
+		t.Fatalf("LoadBootstrap returned an error: %v", err)
+	}
+	if urls := b.ServiceURLs("www.owasp.org"); len(urls) != 1 || !strings.HasPrefix(urls[0], ts.URL) {
+		t.Errorf("ServiceURLs returned the incorrect service: %v", urls)
+	}
+	if urls := b.ServiceURLs("owasp.net"); len(urls) != 0 {
+		t.Errorf("ServiceURLs returned a service for an unknown suffix: %v", urls)
+	}
+
+	rec, err := b.Lookup(context.Background(), "owasp.org")
+	if err != nil {
+		t.Fatalf("Lookup returned an error: %v", err)
+	}
+	if rec.Domain != "owasp.org" || rec.Company != "OWASP Foundation" || rec.Registrar != "Example Registrar, Inc." {
+		t.Errorf("Lookup returned the incorrect registration details: %v", rec)
+	}
+	if rec.Email() != "admin@owasp.org" || len(rec.Emails) != 1 {
+		t.Errorf("Lookup returned the incorrect email addresses: %v", rec.Emails)
+	}
+	if len(rec.NameServers) != 1 || rec.NameServers[0] != "ns1.owasp.org" {
+		t.Errorf("Lookup returned the incorrect name servers: %v", rec.NameServers)
+	}
+
+	if _, err := b.Lookup(context.Background(), "utica.org"); err == nil {
+		t.Error("Lookup did not return an error when the service responded with not found")
+	}
+}
+
+func TestParseBootstrap(t *testing.T) {
+	if _, err := ParseBootstrap(strings.NewReader(`{"services": []}`)); err == nil {
+		t.Error("ParseBootstrap did not return an error for data without services")
+	}
+	if _, err := ParseBootstrap(strings.NewReader(`not json`)); err == nil {
+		t.Error("ParseBootstrap did not return an error for invalid data")
+	}
+}
@@ -0,0 +1,244 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package whois
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"regexp"
+	"strings"
+	"time"
+
+	amassnet "github.com/OWASP/Amass/v3/net"
+	"github.com/caffix/stringset"
+)
+
+const (
+	// DefaultServer is the WHOIS server that lookups start with when no server is provided.
+	DefaultServer = "whois.iana.org"
+	defaultPort   = "43"
+	maxReferrals  = 3
+	maxRespSize   = 1 << 20
+	queryTimeout  = 30 * time.Second
+)
+
+var emailRE = regexp.MustCompile(`[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}`)
+
+// Record contains the registration details obtained for a domain name.
+type Record struct {
+	Domain      string
+	Registrar   string
+	Company     string
+	Emails      []string
+	NameServers []string
+	// The server that provided the last response
+	Server string
+	Raw    string
+}
+
+// Email returns the first email address associated with the registration.
+func (r *Record) Email() string {
+	if len(r.Emails) > 0 {
+		return r.Emails[0]
+	}
+	return ""
+}
+
+// Merge fills the empty fields of the receiver with values from the provided Record.
+func (r *Record) Merge(other *Record) {
+	if other == nil {
+		return
+	}
+	if r.Domain == "" {
+		r.Domain = other.Domain
+	}
+	if r.Registrar == "" {
+		r.Registrar = other.Registrar
+	}
+	if r.Company == "" {
+		r.Company = other.Company
+	}
+	r.Emails = appendUnique(r.Emails, other.Emails...)
+	r.NameServers = appendUnique(r.NameServers, other.NameServers...)
+}
+
+// Query sends the query to the WHOIS server on port 43 and returns the response.
+// The server can include a port number, e.g. whois.example.com:4343.
+func Query(ctx context.Context, server, query string) (string, error) {
+	addr := server
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		addr = net.JoinHostPort(server, defaultPort)
+	}
+
+	tCtx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	conn, err := amassnet.DialContext(tCtx, "tcp", addr)
+	if err != nil {
+		return "", fmt.Errorf("failed to connect to the WHOIS server %s: %v", addr, err)
+	}
+	defer conn.Close()
+
+	if deadline, ok := tCtx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	if _, err := io.WriteString(conn, query+"\r\n"); err != nil {
+		return "", fmt.Errorf("failed to send the query to %s: %v", addr, err)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(conn, maxRespSize))
+	if err != nil && len(data) == 0 {
+		return "", fmt.Errorf("failed to read the response from %s: %v", addr, err)
+	}
+	return string(data), nil
+}
+
+// Lookup performs a WHOIS lookup for the domain name, starting with the provided server
+// and following referrals to the authoritative WHOIS server.
+func Lookup(ctx context.Context, server, domain string) (*Record, error) {
+	if server == "" {
+		server = DefaultServer
+	}
+
+	domain = strings.ToLower(strings.TrimSpace(domain))
+	if domain == "" {
+		return nil, errors.New("no domain name was provided")
+	}
+
+	var rec *Record
+	visited := stringset.New()
+	defer visited.Close()
+
+	for i := 0; i <= maxReferrals && server != "" && !visited.Has(server); i++ {
+		visited.Insert(server)
+
+		resp, err := Query(ctx, server, domain)
+		if err != nil {
+			if rec == nil {
+				return nil, err
+			}
+			break
+		}
+
+		r := ParseResponse(resp)
+		r.Server = server
+		if r.Domain == "" {
+			r.Domain = domain
+		}
+		// Only the final server provides the registrant details, since the registrant
+		// of the earlier responses is the operator of the TLD or the registry
+		if rec != nil {
+			rec.Company = ""
+			rec.Emails = nil
+		}
+		// Details from the referred server take precedence
+		r.Merge(rec)
+		rec = r
+		server = Referral(resp)
+	}
+
+	if rec == nil {
+		return nil, fmt.Errorf("failed to obtain a WHOIS response for %s", domain)
+	}
+	return rec, nil
+}
+
+// Referral returns the WHOIS server that the response refers to, or an empty string.
+func Referral(resp string) string {
+	for _, line := range responseLines(resp) {
+		key, value := line[0], line[1]
+
+		switch key {
+		case "refer", "whois", "registrar whois server", "referralserver":
+			// RWhois servers do not speak the WHOIS protocol
+			if strings.HasPrefix(value, "rwhois://") {
+				continue
+			}
+			if value = strings.TrimSuffix(strings.TrimPrefix(value, "whois://"), "/"); value != "" {
+				return value
+			}
+		}
+	}
+	return ""
+}
+
+// ParseResponse extracts the registration details from a WHOIS response. The company and email
+// addresses are only taken from the registrant fields, and are left empty when redacted.
+func ParseResponse(resp string) *Record {
+	rec := &Record{Raw: resp}
+
+	for _, line := range responseLines(resp) {
+		key, value := line[0], line[1]
+		if strings.Contains(strings.ToLower(value), "redacted") {
+			continue
+		}
+
+		switch key {
+		case "domain name", "domain":
+			if rec.Domain == "" {
+				rec.Domain = strings.ToLower(value)
+			}
+		case "registrar", "registrar name", "sponsoring registrar":
+			if rec.Registrar == "" {
+				rec.Registrar = value
+			}
+		case "registrant organization", "registrant organisation", "registrant org":
+			if rec.Company == "" {
+				rec.Company = value
+			}
+		case "registrant email", "registrant contact email":
+			rec.Emails = appendUnique(rec.Emails, emailRE.FindAllString(value, -1)...)
+		case "name server", "nserver", "nameserver":
+			if ns := strings.ToLower(strings.Fields(value)[0]); ns != "" {
+				rec.NameServers = appendUnique(rec.NameServers, strings.TrimSuffix(ns, "."))
+			}
+		}
+	}
+	return rec
+}
+
+func responseLines(resp string) [][2]string {
+	var lines [][2]string
+
+	scanner := bufio.NewScanner(strings.NewReader(resp))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "%") || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		key := strings.ToLower(strings.TrimSpace(parts[0]))
+		if value := strings.TrimSpace(parts[1]); key != "" && value != "" {
+			lines = append(lines, [2]string{key, value})
+		}
+	}
+	return lines
+}
+
+func appendUnique(list []string, values ...string) []string {
+	for _, v := range values {
+		var found bool
+
+		for _, l := range list {
+			if strings.EqualFold(l, v) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			list = append(list, v)
+		}
+	}
+	return list
+}
@@ -0,0 +1,165 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package whois
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"testing"
+)
+
+// Starts a stand-in WHOIS server that responds to every query with the provided response.
+func startWhoisServer(t *testing.T, resp func(query string) string) string {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to start the WHOIS server: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+
+			go func(c net.Conn) {
+				defer c.Close()
+
+				if line, err := bufio.NewReader(c).ReadString('\n'); err == nil {
+					_, _ = io.WriteString(c, resp(line[:len(line)-2]))
+				}
+			}(conn)
+		}
+	}()
+	return ln.Addr().String()
+}
+
+func TestLookup(t *testing.T) {
+	registrar := startWhoisServer(t, func(query string) string {
+		return `Domain Name: ` + query + `
+Registrar: Example Registrar, Inc.
+Registrant Organization: OWASP Foundation
+Registrant Email: admin@owasp.org
+Admin Email: REDACTED FOR PRIVACY
+Name Server: NS1.OWASP.ORG
+Name Server: ns2.owasp.org.
+`
+	})
+	registry := startWhoisServer(t, func(query string) string {
+		return `% This is the registry
+
+Domain Name: OWASP.ORG
+Registrar WHOIS Server: ` + registrar + `
+Registrar Abuse Contact Email: abuse@registrar.example
+`
+	})
+	root := startWhoisServer(t, func(query string) string {
+		return "refer: " + registry + "\n"
+	})
+
+	rec, err := Lookup(context.Background(), root, "owasp.org")
+	if err != nil {
+		t.Fatalf("Lookup returned an error: %v", err)
+	}
+	if rec.Server != registrar {
+		t.Errorf("Lookup failed to follow the referrals, last server: %s", rec.Server)
+	}
+	if rec.Domain != "owasp.org" || rec.Company != "OWASP Foundation" || rec.Registrar != "Example Registrar, Inc." {
+		t.Errorf("Lookup returned the incorrect registration details: %v", rec)
+	}
+	if rec.Email() != "admin@owasp.org" || len(rec.Emails) != 1 {
+		t.Errorf("Lookup returned the incorrect email addresses: %v", rec.Emails)
+	}
+	if len(rec.NameServers) != 2 || rec.NameServers[0] != "ns1.owasp.org" || rec.NameServers[1] != "ns2.owasp.org" {
+		t.Errorf("Lookup returned the incorrect name servers: %v", rec.NameServers)
+	}
+}
+
+func TestLookupRedactedRegistrant(t *testing.T) {
+	registrar := startWhoisServer(t, func(query string) string {
+		return `Domain Name: ` + query + `
+Registrar: Example Registrar, Inc.
+Registrar Abuse Contact Email: abuse@registrar.example
+Registrant Organization: REDACTED FOR PRIVACY
+Registrant Email: Please query the RDDS service of the Registrar of Record identified in this output for information on how to contact the Registrant (REDACTED)
+Tech Email: tech@registrar.example
+`
+	})
+	root := startWhoisServer(t, func(query string) string {
+		return `% IANA WHOIS server
+
+domain:       ORG
+
+organisation: Public Interest Registry (PIR)
+address:      1775 Wiehle Avenue
+e-mail:       ops@pir.org
+
+refer:        ` + registrar + `
+`
+	})
+
+	rec, err := Lookup(context.Background(), root, "owasp.org")
+	if err != nil {
+		t.Fatalf("Lookup returned an error: %v", err)
+	}
+	if rec.Registrar != "Example Registrar, Inc." {
+		t.Errorf("Lookup returned the incorrect registrar: %s", rec.Registrar)
+	}
+	if rec.Company != "" {
+		t.Errorf("Lookup returned a company for the redacted registrant: %s", rec.Company)
+	}
+	if len(rec.Emails) != 0 {
+		t.Errorf("Lookup returned email addresses for the redacted registrant: %v", rec.Emails)
+	}
+}
+
+func TestLookupReferralLoop(t *testing.T) {
+	var addr string
+	addr = startWhoisServer(t, func(query string) string {
+		return "refer: " + addr + "\nRegistrant Organization: Loop\n"
+	})
+
+	if rec, err := Lookup(context.Background(), addr, "owasp.org"); err != nil || rec.Company != "Loop" {
+		t.Errorf("Lookup failed to stop at the referral loop: %v", err)
+	}
+}
+
+func TestLookupUnreachable(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	if _, err := Lookup(context.Background(), addr, "owasp.org"); err == nil {
+		t.Error("Lookup did not return an error when the server was unreachable")
+	}
+	if _, err := Lookup(context.Background(), addr, ""); err == nil {
+		t.Error("Lookup did not return an error when no domain name was provided")
+	}
+}
+
+func TestReferral(t *testing.T) {
+	tests := []struct {
+		resp string
+		want string
+	}{
+		{"refer:        whois.verisign-grs.com\n", "whois.verisign-grs.com"},
+		{"whois: whois.pir.org\n", "whois.pir.org"},
+		{"ReferralServer:  whois://whois.ripe.net\n", "whois.ripe.net"},
+		{"ReferralServer:  rwhois://rwhois.example.com:4321\n", ""},
+		{"Domain Name: OWASP.ORG\n", ""},
+	}
+
+	for _, test := range tests {
+		if got := Referral(test.resp); got != test.want {
+			t.Errorf("Referral returned %s, expected %s", got, test.want)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package requests
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/OWASP/Amass/v3/stringutil"
+)
+
+// The minimum score of an AS description to be returned by OrganizationSearch
+const minOrgSearchScore = 0.5
+
+// Tokens in AS descriptions that do not help to identify the organization
+var orgStopTokens = map[string]struct{}{
+	"ab": {}, "ag": {}, "as": {}, "asn": {}, "bv": {}, "co": {}, "com": {}, "company": {},
+	"corp": {}, "corporation": {}, "gmbh": {}, "inc": {}, "incorporated": {}, "limited": {},
+	"llc": {}, "ltd": {}, "nv": {}, "plc": {}, "pty": {}, "sa": {}, "sas": {}, "srl": {}, "the": {},
+}
+
+// OrgSearchGroup contains the sibling ASNs that appear to belong to the same organization.
+type OrgSearchGroup struct {
+	Name  string
+	Score float64
+	ASNs  []*ASNRequest
+}
+
+// OrganizationSearch matches the tokens of the provided organization name against the AS
+// descriptions in the cache, while tolerating small spelling differences. The matching ASNs are
+// restricted to the country codes provided, if any, and grouped by the organization they belong to.
+// The groups and the ASNs within them are ranked by how well they match the organization name.
+func (c *ASNCache) OrganizationSearch(name string, ccs ...string) []*OrgSearchGroup {
+	query := orgTokens(name)
+	if len(query) == 0 {
+		return nil
+	}
+
+	countries := make(map[string]struct{})
+	for _, cc := range ccs {
+		if cc = strings.ToUpper(strings.TrimSpace(cc)); cc != "" {
+			countries[cc] = struct{}{}
+		}
+	}
+
+	c.RLock()
+	defer c.RUnlock()
+
+	scores := make(map[int]float64)
+	groups := make(map[string]*OrgSearchGroup)
+	for _, entry := range c.cache {
+		if _, found := countries[strings.ToUpper(entry.CC)]; len(countries) > 0 && !found {
+			continue
+		}
+
+		score := orgMatchScore(name, query, entry.Description)
+		if score < minOrgSearchScore {
+			continue
+		}
+		scores[entry.ASN] = score
+
+		key := orgKey(entry.Description)
+		g, found := groups[key]
+		if !found {
+			g = &OrgSearchGroup{}
+			groups[key] = g
+		}
+		if score > g.Score {
+			g.Score = score
+		}
+		g.ASNs = append(g.ASNs, entry)
+	}
+
+	var results []*OrgSearchGroup
+	for _, g := range groups {
+		sort.Slice(g.ASNs, func(i, j int) bool {
+			a, b := g.ASNs[i], g.ASNs[j]
+
+			if scores[a.ASN] != scores[b.ASN] {
+				return scores[a.ASN] > scores[b.ASN]
+			}
+			if len(a.Netblocks) != len(b.Netblocks) {
+				return len(a.Netblocks) > len(b.Netblocks)
+			}
+			return a.ASN < b.ASN
+		})
+
+		g.Name = orgName(g.ASNs[0].Description)
+		// Prefer the organization name over an AS handle
+		for _, entry := range g.ASNs {
+			if strings.Contains(entry.Description, " - ") {
+				g.Name = orgName(entry.Description)
+				break
+			}
+		}
+		results = append(results, g)
+	}
+	// Larger organizations are listed first when the names match equally well
+	sort.Slice(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if len(a.ASNs) != len(b.ASNs) {
+			return len(a.ASNs) > len(b.ASNs)
+		}
+		return a.Name < b.Name
+	})
+	return results
+}
+
+// Returns a score between zero and one for how well the AS description matches the query tokens.
+// Every query token must match a token of the description.
+func orgMatchScore(name string, query []string, desc string) float64 {
+	tokens := orgTokens(desc)
+	if len(tokens) == 0 {
+		return 0
+	}
+
+	var total float64
+	for _, q := range query {
+		var best float64
+
+		for _, t := range tokens {
+			if s := tokenSimilarity(q, t); s > best {
+				best = s
+			}
+		}
+		if best == 0 {
+			return 0
+		}
+		total += best
+	}
+
+	score := total / float64(len(query))
+	// Descriptions containing the complete name are the best matches
+	if strings.Contains(strings.ToLower(desc), strings.ToLower(strings.TrimSpace(name))) {
+		score = (score + 1) / 2
+	}
+	return score
+}
+
+func tokenSimilarity(q, t string) float64 {
+	switch {
+	case q == t:
+		return 1
+	case len(q) >= 3 && strings.HasPrefix(t, q):
+		// Handles such as CLOUDFLARENET contain the organization name
+		return 0.8
+	case len(q) < 4:
+		return 0
+	}
+
+	// Allow one edit for every four characters of the query token
+	dist := stringutil.Levenshtein(q, t)
+	if dist > len(q)/4 {
+		return 0
+	}
+	return 0.7 * (1 - float64(dist)/float64(len(q)))
+}
+
+// Returns the lowercase alphanumeric tokens of the name, without the legal and networking terms.
+func orgTokens(name string) []string {
+	var tokens []string
+
+	for _, t := range strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if _, stop := orgStopTokens[t]; !stop {
+			tokens = append(tokens, t)
+		}
+	}
+	return tokens
+}
+
+// Returns the organization name of the AS description, which often follows the AS handle,
+// such as 'Google LLC' in 'GOOGLE-CLOUD-PLATFORM - Google LLC'.
+func orgName(desc string) string {
+	if i := strings.Index(desc, " - "); i >= 0 {
+		if name := strings.TrimSpace(desc[i+3:]); name != "" {
+			return name
+		}
+	}
+	return strings.TrimSpace(desc)
+}
+
+// Returns the key used to group the sibling ASNs of an organization. Handles without an organization
+// name, such as AMAZON-02 and AMAZON-AES, are grouped by the first token of the handle.
+func orgKey(desc string) string {
+	name := orgName(desc)
+	tokens := orgTokens(name)
+
+	if name == strings.TrimSpace(desc) && len(tokens) > 0 {
+		return tokens[0]
+	}
+	if len(tokens) == 1 {
+		return tokens[0]
+	}
+	return strings.Join(tokens, " ")
+}
@@ -0,0 +1,52 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package requests
+
+import "testing"
+
+func TestOrganizationSearch(t *testing.T) {
+	cache := NewASNCache()
+	for _, req := range []*ASNRequest{
+		{ASN: 15169, CC: "US", Prefix: "8.8.8.0/24", Netblocks: []string{"8.8.8.0/24", "8.8.4.0/24"}, Description: "GOOGLE"},
+		{ASN: 396982, CC: "US", Prefix: "34.64.0.0/10", Description: "GOOGLE-CLOUD-PLATFORM - Google LLC"},
+		{ASN: 36040, CC: "BR", Prefix: "200.221.0.0/16", Description: "YOUTUBE - Google LLC"},
+		{ASN: 13335, CC: "US", Prefix: "1.1.1.0/24", Description: "CLOUDFLARENET"},
+		{ASN: 16509, CC: "US", Prefix: "3.5.0.0/16", Description: "AMAZON-02"},
+		{ASN: 14618, CC: "US", Prefix: "3.80.0.0/12", Description: "AMAZON-AES"},
+		{ASN: 64500, CC: "DE", Prefix: "192.0.2.0/24", Description: "GOOGOL-NETWORKS - Googol GmbH"},
+		{ASN: 26808, CC: "US", Prefix: "72.237.4.0/24", Description: "UTICA-COLLEGE"},
+	} {
+		cache.Update(req)
+	}
+
+	groups := cache.OrganizationSearch("Google Inc")
+	if len(groups) == 0 {
+		t.Fatal("OrganizationSearch did not match the Google ASNs")
+	}
+	if g := groups[0]; g.Name != "Google LLC" || len(g.ASNs) != 3 || g.ASNs[0].ASN != 15169 {
+		t.Errorf("OrganizationSearch did not group the sibling ASNs: %s %v", g.Name, g.ASNs)
+	}
+	for _, g := range groups[1:] {
+		if g.Score >= groups[0].Score {
+			t.Errorf("OrganizationSearch ranked %s above or equal to the exact matches", g.Name)
+		}
+	}
+
+	if groups := cache.OrganizationSearch("google", "br"); len(groups) != 1 || len(groups[0].ASNs) != 1 || groups[0].ASNs[0].ASN != 36040 {
+		t.Errorf("OrganizationSearch did not filter the ASNs by country code: %v", groups)
+	}
+	if groups := cache.OrganizationSearch("Cloudflare"); len(groups) != 1 || groups[0].ASNs[0].ASN != 13335 {
+		t.Errorf("OrganizationSearch did not match the name within the AS handle: %v", groups)
+	}
+	if groups := cache.OrganizationSearch("amazon.com, inc."); len(groups) != 1 || len(groups[0].ASNs) != 2 {
+		t.Errorf("OrganizationSearch did not group the AS handles of the organization: %v", groups)
+	}
+	if groups := cache.OrganizationSearch("utica colege"); len(groups) != 1 || groups[0].ASNs[0].ASN != 26808 {
+		t.Errorf("OrganizationSearch did not tolerate the misspelled name: %v", groups)
+	}
+	if groups := cache.OrganizationSearch("LLC"); len(groups) != 0 {
+		t.Errorf("OrganizationSearch matched the legal terms: %v", groups)
+	}
+}
@@ -288,21 +288,29 @@ type WhoisRequest struct {
 
 // Output contains all the output data for an enumerated DNS name.
 type Output struct {
-	Name      string        `json:"name"`
-	Domain    string        `json:"domain"`
-	Addresses []AddressInfo `json:"addresses"`
-	Tag       string        `json:"tag"`
-	Sources   []string      `json:"sources"`
+	Name       string        `json:"name"`
+	Domain     string        `json:"domain"`
+	Addresses  []AddressInfo `json:"addresses"`
+	Tag        string        `json:"tag"`
+	Sources    []string      `json:"sources"`
+	Confidence float64       `json:"confidence,omitempty"`
+	FirstSeen  *time.Time    `json:"first_seen,omitempty"`
+	LastSeen   *time.Time    `json:"last_seen,omitempty"`
+	Provenance []string      `json:"provenance,omitempty"`
 }
 
 // Clone implements pipeline Data.
 func (o *Output) Clone() pipeline.Data {
 	return &Output{
-		Name:      o.Name,
-		Domain:    o.Domain,
-		Addresses: append([]AddressInfo(nil), o.Addresses...),
-		Tag:       o.Tag,
-		Sources:   append([]string(nil), o.Sources...),
+		Name:       o.Name,
+		Domain:     o.Domain,
+		Addresses:  append([]AddressInfo(nil), o.Addresses...),
+		Tag:        o.Tag,
+		Sources:    append([]string(nil), o.Sources...),
+		Confidence: o.Confidence,
+		FirstSeen:  o.FirstSeen,
+		LastSeen:   o.LastSeen,
+		Provenance: append([]string(nil), o.Provenance...),
 	}
 }
 
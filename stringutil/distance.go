@@ -0,0 +1,36 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package stringutil
+
+// Levenshtein returns the number of single byte insertions, deletions and substitutions
+// required to change the string a into the string b.
+func Levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			cur[j] = cur[j-1] + 1
+			if v := prev[j] + 1; v < cur[j] {
+				cur[j] = v
+			}
+			if v := prev[j-1] + cost; v < cur[j] {
+				cur[j] = v
+			}
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,29 @@
+// Copyright © by Jeff Foley 2017-2023. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+// SPDX-License-Identifier: Apache-2.0
+
+package stringutil
+
+import "testing"
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"", "", 0},
+		{"", "abc", 3},
+		{"abc", "", 3},
+		{"timeout", "timeout", 0},
+		{"timout", "timeout", 1},
+		{"resolver", "resolvers", 1},
+		{"kitten", "sitting", 3},
+		{"microsoft", "microsfot", 2},
+	}
+
+	for _, test := range tests {
+		if got := Levenshtein(test.a, test.b); got != test.expected {
+			t.Errorf("Levenshtein(%q, %q) returned %d, expected %d", test.a, test.b, got, test.expected)
+		}
+	}
+}
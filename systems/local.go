@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"net/url"
 	"os"
 	"runtime"
 	"sort"
@@ -16,6 +17,7 @@ import (
 
 	"github.com/OWASP/Amass/v3/config"
 	amassnet "github.com/OWASP/Amass/v3/net"
+	"github.com/OWASP/Amass/v3/net/http"
 	"github.com/OWASP/Amass/v3/requests"
 	"github.com/OWASP/Amass/v3/resources"
 	"github.com/caffix/netmap"
@@ -42,6 +44,18 @@ func NewLocalSystem(cfg *config.Config) (*LocalSystem, error) {
 		return nil, err
 	}
 
+	if err := http.SetDefaultProxy(cfg.Proxy); err != nil {
+		return nil, err
+	}
+	if cfg.Proxy != "" && cfg.Verbose {
+		if u, err := url.Parse(cfg.Proxy); err == nil {
+			cfg.Log.Printf("Sending outbound web traffic through the proxy at %s", u.Redacted())
+		}
+	}
+
+	http.SetRetryPolicy(cfg.HTTPRetries, time.Duration(cfg.HTTPRetryBackoff)*time.Second)
+	http.SetHostConcurrency(cfg.HTTPHostConcurrency)
+
 	trusted, num := trustedResolvers(cfg)
 	if trusted == nil || num == 0 {
 		return nil, errors.New("the system was unable to build the pool of trusted resolvers")
@@ -67,3 +67,18 @@ func PopulateCache(ctx context.Context, asn int, sys System) {
 		}
 	}
 }
+
+// IsAddressExcluded returns true when the address falls within a netblock excluded by the System
+// configuration, or within an excluded autonomous system according to the System cache.
+func IsAddressExcluded(sys System, addr string) bool {
+	cfg := sys.Config()
+	if cfg.IsAddressExcluded(addr) {
+		return true
+	}
+	if len(cfg.ExcludedASNs) > 0 {
+		if r := sys.Cache().AddrSearch(addr); r != nil && cfg.IsASNExcluded(r.ASN) {
+			return true
+		}
+	}
+	return false
+}